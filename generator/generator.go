@@ -0,0 +1,69 @@
+// Package generator exposes otelgen's trace, metric and log generation to other Go
+// programs. Generators emit on providers built by the caller, so exporters, readers,
+// processors and resources are wired however the embedding program needs.
+package generator
+
+import (
+	"context"
+
+	"github.com/krzko/otelgen/internal/logs"
+	"github.com/krzko/otelgen/internal/metrics"
+	"github.com/krzko/otelgen/internal/traces"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Generator emits telemetry until its configured number or duration is reached, or
+// ctx is cancelled.
+type Generator interface {
+	Run(ctx context.Context) error
+}
+
+var (
+	_ Generator = (*traces.Generator)(nil)
+	_ Generator = (*metrics.Generator)(nil)
+	_ Generator = (*logs.Generator)(nil)
+)
+
+type (
+	// TraceConfig configures trace generation, exporter settings are ignored.
+	TraceConfig = traces.Config
+	// MetricConfig configures metric generation, exporter settings are ignored.
+	MetricConfig = metrics.Config
+	// LogConfig configures log generation, exporter settings are ignored.
+	LogConfig = logs.Config
+
+	// SumConfig describes a sum instrument.
+	SumConfig = metrics.SumConfig
+	// GaugeConfig describes a gauge instrument.
+	GaugeConfig = metrics.GaugeConfig
+	// HistogramConfig describes a histogram instrument.
+	HistogramConfig = metrics.HistogramConfig
+)
+
+// NewTraceGenerator returns a generator that emits the configured scenarios on tp.
+func NewTraceGenerator(tp trace.TracerProvider, c *TraceConfig, logger *zap.Logger) Generator {
+	return traces.NewGenerator(tp, c, logger)
+}
+
+// NewSumGenerator returns a generator that records the sum described by sc on mp.
+func NewSumGenerator(mp metric.MeterProvider, sc SumConfig, c *MetricConfig, logger *zap.Logger) Generator {
+	return metrics.NewSumGenerator(mp, sc, c, logger)
+}
+
+// NewGaugeGenerator returns a generator that observes the gauge described by gc on mp.
+func NewGaugeGenerator(mp metric.MeterProvider, gc GaugeConfig, c *MetricConfig, logger *zap.Logger) Generator {
+	return metrics.NewGaugeGenerator(mp, gc, c, logger)
+}
+
+// NewHistogramGenerator returns a generator that records the histogram described by hc on mp.
+func NewHistogramGenerator(mp metric.MeterProvider, hc HistogramConfig, c *MetricConfig, logger *zap.Logger) Generator {
+	return metrics.NewHistogramGenerator(mp, hc, c, logger)
+}
+
+// NewLogGenerator returns a generator that emits log records on lp.
+func NewLogGenerator(lp log.LoggerProvider, c *LogConfig, logger *zap.Logger) Generator {
+	return logs.NewGenerator(lp, c, logger)
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+func TestSumGeneratorRecordsOnReader(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer func() { _ = mp.Shutdown(context.Background()) }()
+
+	g := NewSumGenerator(mp, SumConfig{
+		Name:        "embedded.requests",
+		Unit:        "{request}",
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}, &MetricConfig{
+		ServiceName:   "embedded",
+		NumMetrics:    1,
+		Rate:          1,
+		TotalDuration: 1500 * time.Millisecond,
+	}, zap.NewNop())
+
+	start := time.Now()
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() took %s, want it to stop after its duration", elapsed)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "embedded.requests" {
+				continue
+			}
+			if m.Unit != "{request}" {
+				t.Errorf("unit = %q, want %q", m.Unit, "{request}")
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok || len(sum.DataPoints) == 0 || sum.DataPoints[0].Value < 1 {
+				t.Errorf("embedded.requests = %+v, want a recorded sum", m.Data)
+			}
+			return
+		}
+	}
+	t.Fatalf("embedded.requests wasn't collected from %+v", rm)
+}
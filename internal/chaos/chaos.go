@@ -0,0 +1,279 @@
+// Package chaos wraps exporters to occasionally turn exported telemetry into odd but
+// valid edge cases, to check that backends and pipelines tolerate them. Every altered
+// span or data point carries an otelgen.chaos attribute naming its edge case.
+//
+// Spans are given one of:
+//   - empty_value: an otelgen.chaos.empty attribute with an empty string value
+//   - long_name: a name padded to LongNameLength characters
+//   - unicode: a name and an otelgen.chaos.unicode attribute holding UnicodeText
+//   - zero_duration: an end time equal to the start time
+//
+// Metrics are given one of:
+//   - empty_value: an otelgen.chaos.empty attribute with an empty string value on every data point
+//   - unicode: an otelgen.chaos.unicode attribute holding UnicodeText on every data point
+//   - long_name: a name padded to MaxInstrumentNameLength characters
+//   - extreme_value: gauges only, data points set to the largest, smallest and negative
+//     zero finite values, never NaN or Inf
+package chaos
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// Key is the attribute naming the edge case a span or data point was given
+	Key = attribute.Key("otelgen.chaos")
+	// EmptyKey is the attribute holding an empty value
+	EmptyKey = attribute.Key("otelgen.chaos.empty")
+	// UnicodeKey is the attribute holding UnicodeText
+	UnicodeKey = attribute.Key("otelgen.chaos.unicode")
+
+	// Probability is the chance each exported span or metric is given an edge case
+	Probability = 0.1
+	// LongNameLength is the length long span names are padded to
+	LongNameLength = 1024
+	// MaxInstrumentNameLength is the longest instrument name the API accepts
+	MaxInstrumentNameLength = 255
+	// UnicodeText mixes accented latin, CJK, right-to-left and emoji characters
+	UnicodeText = "ünïcødé 日本語 العربية 🚀"
+)
+
+// Edge cases given to spans and metrics
+const (
+	EmptyValue   = "empty_value"
+	LongName     = "long_name"
+	Unicode      = "unicode"
+	ZeroDuration = "zero_duration"
+	ExtremeValue = "extreme_value"
+)
+
+// Source decides which exported telemetry is given an edge case
+type Source struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// New returns a source whose choices are derived from seed
+func New(seed int64) *Source {
+	return &Source{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// pick returns one of cases with Probability, or an empty string
+func (s *Source) pick(cases ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rnd.Float64() >= Probability {
+		return ""
+	}
+	return cases[s.rnd.Intn(len(cases))]
+}
+
+// intn returns a number in [0, n)
+func (s *Source) intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Intn(n)
+}
+
+type spanExporter struct {
+	sdktrace.SpanExporter
+	source *Source
+}
+
+// SpanExporter wraps exp to give some of the exported spans an edge case
+func (s *Source) SpanExporter(exp sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return &spanExporter{SpanExporter: exp, source: s}
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	altered := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, sp := range spans {
+		altered[i] = sp
+		if c := e.source.pick(EmptyValue, LongName, Unicode, ZeroDuration); c != "" {
+			altered[i] = &span{ReadOnlySpan: sp, edgeCase: c}
+		}
+	}
+	return e.SpanExporter.ExportSpans(ctx, altered)
+}
+
+// span reports the wrapped span with its edge case applied
+type span struct {
+	sdktrace.ReadOnlySpan
+	edgeCase string
+}
+
+func (s *span) Name() string {
+	name := s.ReadOnlySpan.Name()
+	switch s.edgeCase {
+	case LongName:
+		return pad(name, LongNameLength)
+	case Unicode:
+		return name + " " + UnicodeText
+	}
+	return name
+}
+
+func (s *span) EndTime() time.Time {
+	if s.edgeCase == ZeroDuration {
+		return s.ReadOnlySpan.StartTime()
+	}
+	return s.ReadOnlySpan.EndTime()
+}
+
+func (s *span) Attributes() []attribute.KeyValue {
+	attrs := append(append([]attribute.KeyValue{}, s.ReadOnlySpan.Attributes()...), Key.String(s.edgeCase))
+	switch s.edgeCase {
+	case EmptyValue:
+		attrs = append(attrs, EmptyKey.String(""))
+	case Unicode:
+		attrs = append(attrs, UnicodeKey.String(UnicodeText))
+	}
+	return attrs
+}
+
+type metricExporter struct {
+	sdkmetric.Exporter
+	source *Source
+}
+
+// MetricExporter wraps exp to give some of the exported metrics an edge case
+func (s *Source) MetricExporter(exp sdkmetric.Exporter) sdkmetric.Exporter {
+	return &metricExporter{Exporter: exp, source: s}
+}
+
+// Export hands exp a copy of rm with the edge cases applied, leaving the data the
+// reader reuses between collections untouched
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	altered := *rm
+	altered.ScopeMetrics = make([]metricdata.ScopeMetrics, len(rm.ScopeMetrics))
+	for i, sm := range rm.ScopeMetrics {
+		metrics := make([]metricdata.Metrics, len(sm.Metrics))
+		for j, m := range sm.Metrics {
+			cases := []string{EmptyValue, Unicode, LongName}
+			if isGauge(m.Data) {
+				cases = append(cases, ExtremeValue)
+			}
+			if c := e.source.pick(cases...); c != "" {
+				m = alterMetric(m, c, e.source.intn(len(extremeFloats)))
+			}
+			metrics[j] = m
+		}
+		sm.Metrics = metrics
+		altered.ScopeMetrics[i] = sm
+	}
+	return e.Exporter.Export(ctx, &altered)
+}
+
+func isGauge(data metricdata.Aggregation) bool {
+	switch data.(type) {
+	case metricdata.Gauge[int64], metricdata.Gauge[float64]:
+		return true
+	}
+	return false
+}
+
+// Finite extremes cycled through by ExtremeValue, the same length for both types
+var (
+	extremeInts   = []int64{math.MaxInt64, math.MinInt64, 0}
+	extremeFloats = []float64{math.MaxFloat64, math.SmallestNonzeroFloat64, math.Copysign(0, -1)}
+)
+
+// alterMetric returns m with edgeCase applied to a copy of its data points, extreme
+// values being cycled through from index first
+func alterMetric(m metricdata.Metrics, edgeCase string, first int) metricdata.Metrics {
+	attrs := []attribute.KeyValue{Key.String(edgeCase)}
+	switch edgeCase {
+	case EmptyValue:
+		attrs = append(attrs, EmptyKey.String(""))
+	case Unicode:
+		attrs = append(attrs, UnicodeKey.String(UnicodeText))
+	case LongName:
+		m.Name = pad(m.Name, MaxInstrumentNameLength)
+	}
+
+	switch d := m.Data.(type) {
+	case metricdata.Gauge[int64]:
+		d.DataPoints = alterDataPoints(d.DataPoints, attrs, edgeCase == ExtremeValue, rotate(extremeInts, first))
+		m.Data = d
+	case metricdata.Gauge[float64]:
+		d.DataPoints = alterDataPoints(d.DataPoints, attrs, edgeCase == ExtremeValue, rotate(extremeFloats, first))
+		m.Data = d
+	case metricdata.Sum[int64]:
+		d.DataPoints = alterDataPoints(d.DataPoints, attrs, false, nil)
+		m.Data = d
+	case metricdata.Sum[float64]:
+		d.DataPoints = alterDataPoints(d.DataPoints, attrs, false, nil)
+		m.Data = d
+	case metricdata.Histogram[int64]:
+		d.DataPoints = alterHistogramDataPoints(d.DataPoints, attrs)
+		m.Data = d
+	case metricdata.Histogram[float64]:
+		d.DataPoints = alterHistogramDataPoints(d.DataPoints, attrs)
+		m.Data = d
+	case metricdata.ExponentialHistogram[int64]:
+		d.DataPoints = alterExponentialHistogramDataPoints(d.DataPoints, attrs)
+		m.Data = d
+	case metricdata.ExponentialHistogram[float64]:
+		d.DataPoints = alterExponentialHistogramDataPoints(d.DataPoints, attrs)
+		m.Data = d
+	}
+	return m
+}
+
+func alterDataPoints[N int64 | float64](dps []metricdata.DataPoint[N], attrs []attribute.KeyValue, extreme bool, values []N) []metricdata.DataPoint[N] {
+	altered := make([]metricdata.DataPoint[N], len(dps))
+	for i, dp := range dps {
+		dp.Attributes = withAttributes(dp.Attributes, attrs)
+		if extreme {
+			dp.Value = values[i%len(values)]
+		}
+		altered[i] = dp
+	}
+	return altered
+}
+
+func alterHistogramDataPoints[N int64 | float64](dps []metricdata.HistogramDataPoint[N], attrs []attribute.KeyValue) []metricdata.HistogramDataPoint[N] {
+	altered := make([]metricdata.HistogramDataPoint[N], len(dps))
+	for i, dp := range dps {
+		dp.Attributes = withAttributes(dp.Attributes, attrs)
+		altered[i] = dp
+	}
+	return altered
+}
+
+func alterExponentialHistogramDataPoints[N int64 | float64](dps []metricdata.ExponentialHistogramDataPoint[N], attrs []attribute.KeyValue) []metricdata.ExponentialHistogramDataPoint[N] {
+	altered := make([]metricdata.ExponentialHistogramDataPoint[N], len(dps))
+	for i, dp := range dps {
+		dp.Attributes = withAttributes(dp.Attributes, attrs)
+		altered[i] = dp
+	}
+	return altered
+}
+
+// rotate returns values starting from index first, wrapping around
+func rotate[N int64 | float64](values []N, first int) []N {
+	return append(append([]N{}, values[first:]...), values[:first]...)
+}
+
+// withAttributes returns set with attrs added
+func withAttributes(set attribute.Set, attrs []attribute.KeyValue) attribute.Set {
+	return attribute.NewSet(append(set.ToSlice(), attrs...)...)
+}
+
+// pad extends name to length characters, leaving longer names as they are
+func pad(name string, length int) string {
+	if len(name) >= length {
+		return name
+	}
+	return name + strings.Repeat("x", length-len(name))
+}
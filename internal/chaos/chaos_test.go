@@ -0,0 +1,163 @@
+package chaos
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// iterations is enough exports for a seeded source to pick every edge case
+const iterations = 500
+
+// edgeCase returns the edge case named in attrs, or an empty string
+func edgeCase(attrs []attribute.KeyValue) string {
+	for _, kv := range attrs {
+		if kv.Key == Key {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+// exportSpans exports iterations spans one at a time through a source seeded with
+// seed and returns what reached the wrapped exporter
+func exportSpans(t *testing.T, seed int64) tracetest.SpanStubs {
+	t.Helper()
+	mem := tracetest.NewInMemoryExporter()
+	exp := New(seed).SpanExporter(mem)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stub := tracetest.SpanStub{Name: "span", StartTime: start, EndTime: start.Add(time.Second)}
+	for i := 0; i < iterations; i++ {
+		if err := exp.ExportSpans(context.Background(), tracetest.SpanStubs{stub}.Snapshots()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mem.GetSpans()
+}
+
+func TestSpanExporter(t *testing.T) {
+	spans := exportSpans(t, 1)
+
+	seen := map[string]int{}
+	for _, s := range spans {
+		c := edgeCase(s.Attributes)
+		seen[c]++
+		attrs := attribute.NewSet(s.Attributes...)
+		switch c {
+		case "":
+			if s.Name != "span" || s.EndTime.Sub(s.StartTime) != time.Second {
+				t.Errorf("unaltered span is %q lasting %v", s.Name, s.EndTime.Sub(s.StartTime))
+			}
+		case EmptyValue:
+			if v, ok := attrs.Value(EmptyKey); !ok || v.AsString() != "" {
+				t.Errorf("%s span has %s = %q, %t", c, EmptyKey, v.AsString(), ok)
+			}
+		case LongName:
+			if len(s.Name) != LongNameLength {
+				t.Errorf("%s span name is %d long, want %d", c, len(s.Name), LongNameLength)
+			}
+		case Unicode:
+			if v, _ := attrs.Value(UnicodeKey); v.AsString() != UnicodeText || !strings.Contains(s.Name, UnicodeText) {
+				t.Errorf("%s span is %q with %s = %q", c, s.Name, UnicodeKey, v.AsString())
+			}
+		case ZeroDuration:
+			if !s.EndTime.Equal(s.StartTime) {
+				t.Errorf("%s span lasts %v", c, s.EndTime.Sub(s.StartTime))
+			}
+		default:
+			t.Errorf("unknown edge case %q", c)
+		}
+	}
+
+	for _, c := range []string{EmptyValue, LongName, Unicode, ZeroDuration} {
+		if seen[c] == 0 {
+			t.Errorf("no %s span in %d", c, iterations)
+		}
+	}
+	// Roughly Probability of the spans are altered
+	if altered := iterations - seen[""]; altered < iterations/20 || altered > iterations/5 {
+		t.Errorf("%d of %d spans altered, want about %v of them", altered, iterations, Probability)
+	}
+}
+
+func TestSourceIsSeeded(t *testing.T) {
+	names := func(seed int64) []string {
+		var out []string
+		for _, s := range exportSpans(t, seed) {
+			out = append(out, edgeCase(s.Attributes))
+		}
+		return out
+	}
+	first, again, other := names(7), names(7), names(8)
+	if strings.Join(first, ",") != strings.Join(again, ",") {
+		t.Error("the same seed picked different edge cases")
+	}
+	if strings.Join(first, ",") == strings.Join(other, ",") {
+		t.Error("different seeds picked the same edge cases")
+	}
+}
+
+// metricCapture keeps the resource metrics exported to it
+type metricCapture struct {
+	sdkmetric.Exporter
+	exported []*metricdata.ResourceMetrics
+}
+
+func (e *metricCapture) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.exported = append(e.exported, rm)
+	return nil
+}
+
+func TestMetricExporter(t *testing.T) {
+	rm := &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{
+		{Name: "gauge", Data: metricdata.Gauge[float64]{DataPoints: []metricdata.DataPoint[float64]{{Value: 1}}}},
+		{Name: "sum", Data: metricdata.Sum[int64]{DataPoints: []metricdata.DataPoint[int64]{{Value: 1}}}},
+	}}}}
+	capture := &metricCapture{}
+	exp := New(1).MetricExporter(capture)
+	for i := 0; i < iterations; i++ {
+		if err := exp.Export(context.Background(), rm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := map[string]int{}
+	for _, got := range capture.exported {
+		for _, m := range got.ScopeMetrics[0].Metrics {
+			switch d := m.Data.(type) {
+			case metricdata.Gauge[float64]:
+				dp := d.DataPoints[0]
+				c := edgeCase(dp.Attributes.ToSlice())
+				seen[c]++
+				if math.IsNaN(dp.Value) || math.IsInf(dp.Value, 0) {
+					t.Errorf("%s gauge value is %v, want a finite one", c, dp.Value)
+				}
+				if c == LongName && len(m.Name) != MaxInstrumentNameLength {
+					t.Errorf("%s gauge name is %d long, want %d", c, len(m.Name), MaxInstrumentNameLength)
+				}
+			case metricdata.Sum[int64]:
+				if c := edgeCase(d.DataPoints[0].Attributes.ToSlice()); c == ExtremeValue {
+					t.Errorf("sum given the gauge only %s", c)
+				}
+			}
+		}
+	}
+	for _, c := range []string{EmptyValue, Unicode, LongName, ExtremeValue} {
+		if seen[c] == 0 {
+			t.Errorf("no %s gauge in %d exports", c, iterations)
+		}
+	}
+
+	// The reader's own data is reused between collections and must be left as it was
+	original := rm.ScopeMetrics[0].Metrics[0]
+	if original.Name != "gauge" || original.Data.(metricdata.Gauge[float64]).DataPoints[0].Attributes.Len() != 0 {
+		t.Errorf("the exported data was altered in place: %+v", original)
+	}
+}
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/krzko/otelgen/internal/traces"
+	"github.com/krzko/otelgen/internal/traces/scenarios"
+	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.uber.org/zap"
+)
+
+func genBenchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bench",
+		Usage: "Measure the maximum trace throughput the collector sustains by generating unthrottled",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "duration",
+				Aliases: []string{"d"},
+				Usage:   "duration in seconds to run the benchmark for",
+				Value:   10,
+			},
+			&cli.StringSliceFlag{
+				Name:    "scenarios",
+				Aliases: []string{"s"},
+				Usage:   "The trace scenarios to simulate (basic, clock_skew, deep, eventing, exception, fan_out, microservices, web_mobile)",
+				Value:   cli.NewStringSlice("basic"),
+			},
+			&cli.IntFlag{
+				Name:    "workers",
+				Aliases: []string{"w"},
+				Usage:   "number of workers (goroutines) to run",
+				Value:   1,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return runBench(c)
+		},
+	}
+}
+
+// benchExporter wraps a span exporter to record the latency and outcome of every export
+type benchExporter struct {
+	sdktrace.SpanExporter
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	spans     int
+	failed    int
+}
+
+func (e *benchExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	start := time.Now()
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	latency := time.Since(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.latencies = append(e.latencies, latency)
+	if err != nil {
+		e.failed++
+		return err
+	}
+	e.spans += len(spans)
+	return nil
+}
+
+// percentile returns the p-th percentile (0-1) of the recorded export latencies
+func (e *benchExporter) percentile(p float64) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), e.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func runBench(c *cli.Context) error {
+	if c.String("otel-exporter-otlp-endpoint") == "" {
+		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	}
+	if c.Int("duration") < 1 {
+		return errors.New("'duration' must be greater than or equal to 1")
+	}
+
+	tracesCfg := &traces.Config{
+		Endpoint:          c.String("otel-exporter-otlp-endpoint"),
+		ServiceName:       serviceName(c),
+		ServiceVersion:    c.String("service-version"),
+		ServiceInstanceID: serviceInstanceID(c),
+		Insecure:          c.Bool("insecure"),
+		UseHTTP:           c.String("protocol") == "http",
+		TotalDuration:     time.Duration(c.Int("duration")) * time.Second,
+		ProgressInterval:  time.Duration(c.Int("progress-interval")) * time.Second,
+		WorkerCount:       c.Int("workers"),
+		Scenarios:         c.StringSlice("scenarios"),
+		SpanCount:         scenarios.DefaultSpanCount,
+		MaxTraceDepth:     scenarios.DefaultMaxTraceDepth,
+		NamePrefix:        c.String("name-prefix"),
+		NameSuffix:        c.String("name-suffix"),
+		// A rate of 0 disables throttling
+		Rate: 0,
+	}
+
+	headers, err := parseHeaders(c)
+	if err != nil {
+		return err
+	}
+	tracesCfg.Headers = headers
+
+	tracesCfg.ShutdownTimeout, err = parseShutdownTimeout(c)
+	if err != nil {
+		return err
+	}
+
+	tracesCfg.Endpoint, tracesCfg.Insecure, tracesCfg.URLPath, err = parseEndpoint(c, tracesCfg.Insecure)
+	if err != nil {
+		return err
+	}
+
+	configureLogging(c)
+
+	exp, err := createTraceExporter(context.Background(), tracesCfg)
+	if err != nil {
+		logger.Error("failed to obtain OTLP exporter", zap.Error(err))
+		return err
+	}
+	bench := &benchExporter{SpanExporter: exp}
+
+	tracerProvider := sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(tracesCfg.ServiceName),
+			semconv.ServiceVersionKey.String(tracesCfg.ServiceVersion),
+			semconv.ServiceInstanceIDKey.String(tracesCfg.ServiceInstanceID),
+		)),
+		sdktrace.WithBatcher(bench, sdktrace.WithBatchTimeout(time.Second)),
+	}, tracesCfg.TracerProviderOptions()...)...)
+	otel.SetTracerProvider(tracerProvider)
+
+	start := time.Now()
+	if err := traces.Run(c.Context, tracesCfg, logger); err != nil {
+		return err
+	}
+
+	// Shutting down the provider exports everything still buffered before reporting
+	ctx, cancel := context.WithTimeout(context.Background(), tracesCfg.ShutdownTimeout)
+	defer cancel()
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		logger.Error("failed to stop the tracer provider", zap.Error(err))
+	}
+	elapsed := time.Since(start)
+
+	bench.mu.Lock()
+	spans, failed, batches := bench.spans, bench.failed, len(bench.latencies)
+	bench.mu.Unlock()
+
+	logger.Info("benchmark completed",
+		zap.String("endpoint", tracesCfg.Endpoint),
+		zap.String("protocol", c.String("protocol")),
+		zap.Duration("elapsed", elapsed),
+		zap.Int("spans_exported", spans),
+		zap.Float64("spans_per_second", float64(spans)/elapsed.Seconds()),
+		zap.Int("batches", batches),
+		zap.Int("exports_failed", failed),
+		zap.Duration("export_latency_p50", bench.percentile(0.5)),
+		zap.Duration("export_latency_p99", bench.percentile(0.99)),
+	)
+
+	return nil
+}
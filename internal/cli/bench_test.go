@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRunBench(t *testing.T) {
+	defer func(l *zap.Logger) { logger = l }(logger)
+
+	tests := []struct {
+		name       string
+		status     int
+		wantSpans  bool
+		wantFailed bool
+	}{
+		{name: "accepted", status: http.StatusOK, wantSpans: true},
+		{name: "rejected", status: http.StatusBadRequest, wantFailed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/x-protobuf")
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			core, logs := observer.New(zap.InfoLevel)
+			logger = zap.New(core)
+			global := newTestContext(t, getGlobalFlags(), "--otel-exporter-otlp-endpoint", srv.URL, "--protocol", "http")
+			c := newCommandContext(t, global, genBenchCommand().Flags, "--duration", "1")
+			if err := runBench(c); err != nil {
+				t.Fatalf("runBench() error = %v", err)
+			}
+
+			completed := logs.FilterMessage("benchmark completed").All()
+			if len(completed) != 1 {
+				t.Fatalf("got %d benchmark reports, want 1", len(completed))
+			}
+			report := completed[0].ContextMap()
+			spans, perSecond := report["spans_exported"].(int64), report["spans_per_second"].(float64)
+			if (spans > 0) != tt.wantSpans || (perSecond > 0) != tt.wantSpans {
+				t.Errorf("exported %d spans at %v per second, want throughput %v", spans, perSecond, tt.wantSpans)
+			}
+			if failed := report["exports_failed"].(int64); (failed > 0) != tt.wantFailed {
+				t.Errorf("exports_failed = %d, want failures %v", failed, tt.wantFailed)
+			}
+			if report["batches"].(int64) == 0 {
+				t.Error("the benchmark recorded no exports")
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/krzko/otelgen/internal/exportstats"
+	"github.com/krzko/otelgen/internal/logs"
+	"github.com/krzko/otelgen/internal/metrics"
+	"github.com/krzko/otelgen/internal/traces"
+	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// checkName names the span, metric and log record sent by the check
+const checkName = "otelgen.check"
+
+func genCheckCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Verify the collector is reachable by sending a span, a metric and a log record",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "timeout",
+				Usage: "timeout in seconds to wait for each export to complete",
+				Value: 10,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return checkConnectivity(c)
+		},
+	}
+}
+
+// collectorCheck exports a single item of a signal through a real provider, counting
+// the export on stats and returning how long the export took
+type collectorCheck func(ctx context.Context, res *resource.Resource, stats *exportstats.Stats) (time.Duration, error)
+
+// namedCheck is the collector check of a signal
+type namedCheck struct {
+	signal string
+	run    collectorCheck
+}
+
+func checkConnectivity(c *cli.Context) error {
+	if c.String("otel-exporter-otlp-endpoint") == "" {
+		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	}
+
+	tracesCfg := &traces.Config{
+		Endpoint:          c.String("otel-exporter-otlp-endpoint"),
+		ServiceName:       serviceName(c),
+		ServiceVersion:    c.String("service-version"),
+		ServiceInstanceID: serviceInstanceID(c),
+		Insecure:          c.Bool("insecure"),
+		UseHTTP:           c.String("protocol") == "http",
+	}
+
+	headers, err := parseHeaders(c)
+	if err != nil {
+		return err
+	}
+	tracesCfg.Headers = headers
+
+	tracesCfg.Endpoint, tracesCfg.Insecure, tracesCfg.URLPath, err = parseEndpoint(c, tracesCfg.Insecure)
+	if err != nil {
+		return err
+	}
+
+	metricsCfg := &metrics.Config{
+		Endpoint: tracesCfg.Endpoint,
+		Insecure: tracesCfg.Insecure,
+		UseHTTP:  tracesCfg.UseHTTP,
+		Headers:  headers,
+		URLPath:  tracesCfg.URLPath,
+	}
+	logsCfg := &logs.Config{
+		Endpoint: tracesCfg.Endpoint,
+		Insecure: tracesCfg.Insecure,
+		UseHTTP:  tracesCfg.UseHTTP,
+		Headers:  headers,
+		URLPath:  tracesCfg.URLPath,
+	}
+
+	configureLogging(c)
+
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(tracesCfg.ServiceName),
+		semconv.ServiceVersionKey.String(tracesCfg.ServiceVersion),
+		semconv.ServiceInstanceIDKey.String(tracesCfg.ServiceInstanceID),
+	)
+
+	checks := []namedCheck{
+		{"traces", func(ctx context.Context, res *resource.Resource, stats *exportstats.Stats) (time.Duration, error) {
+			return checkTraces(ctx, tracesCfg, res, stats)
+		}},
+		{"metrics", func(ctx context.Context, res *resource.Resource, stats *exportstats.Stats) (time.Duration, error) {
+			return checkMetrics(ctx, metricsCfg, res, stats)
+		}},
+		{"logs", func(ctx context.Context, res *resource.Resource, stats *exportstats.Stats) (time.Duration, error) {
+			return checkLogs(ctx, logsCfg, res, stats)
+		}},
+	}
+
+	return runCollectorChecks(checks, res, time.Duration(c.Int("timeout"))*time.Second,
+		zap.String("endpoint", tracesCfg.Endpoint),
+		zap.String("protocol", c.String("protocol")),
+	)
+}
+
+// runCollectorChecks runs every check within timeout, logging the outcome of each with
+// fields, and returns the errors of those that failed joined together
+func runCollectorChecks(checks []namedCheck, res *resource.Resource, timeout time.Duration, fields ...zap.Field) error {
+	var failed []error
+	for _, check := range checks {
+		// The providers report export errors to the global handler, so they're
+		// taken from the stats wrapping each exporter instead
+		var exportErr error
+		stats := exportstats.New(check.signal, nil).OnError(func(err error) { exportErr = err })
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		latency, err := check.run(ctx, res, stats)
+		cancel()
+		if err == nil {
+			err = exportErr
+		}
+		if err == nil && stats.Counts().Succeeded == 0 {
+			err = errors.New("nothing was exported")
+		}
+
+		outcome := append([]zap.Field{zap.String("signal", check.signal)}, fields...)
+		outcome = append(outcome, zap.Duration("latency", latency))
+		if err != nil {
+			logger.Error("collector check failed", append(outcome, zap.Error(err))...)
+			failed = append(failed, fmt.Errorf("%s: %w", check.signal, err))
+			continue
+		}
+
+		logger.Info("collector check succeeded", outcome...)
+	}
+
+	return errors.Join(failed...)
+}
+
+// checkTraces ends a span on a tracer provider that exports it synchronously
+func checkTraces(ctx context.Context, tracesCfg *traces.Config, res *resource.Resource, stats *exportstats.Stats) (time.Duration, error) {
+	exp, err := createTraceExporter(ctx, tracesCfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to obtain OTLP exporter: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(stats.SpanExporter(boundedSpanExporter{exp, ctx}))),
+		sdktrace.WithResource(res),
+	)
+	defer shutdownCheck(ctx, tp.Shutdown)
+
+	start := time.Now()
+	_, span := tp.Tracer("otelgen").Start(ctx, checkName,
+		trace.WithAttributes(attribute.Bool(checkName, true)),
+	)
+	span.End()
+	return time.Since(start), nil
+}
+
+// checkMetrics records a counter and flushes the meter provider, exporting it
+func checkMetrics(ctx context.Context, metricsCfg *metrics.Config, res *resource.Resource, stats *exportstats.Stats) (time.Duration, error) {
+	var exp MetricExporter
+	var err error
+	grpcExpOpt, httpExpOpt := getConnectionOptions(metricsCfg)
+	if metricsCfg.UseHTTP {
+		exp, err = NewMetricExporter(ctx, "http", httpExpOpt)
+	} else {
+		exp, err = NewMetricExporter(ctx, "grpc", grpcExpOpt)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to obtain OTLP exporter: %w", err)
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(stats.MetricExporter(exp))),
+		sdkmetric.WithResource(res),
+	)
+	defer shutdownCheck(ctx, mp.Shutdown)
+
+	counter, err := mp.Meter("otelgen").Int64Counter(checkName)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	counter.Add(ctx, 1)
+	err = mp.ForceFlush(ctx)
+	return time.Since(start), err
+}
+
+// checkLogs emits a record on a logger provider that exports it synchronously
+func checkLogs(ctx context.Context, logsCfg *logs.Config, res *resource.Resource, stats *exportstats.Stats) (time.Duration, error) {
+	exp, err := logs.NewExporter(logsCfg)
+	if err != nil {
+		return 0, err
+	}
+	lp := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewSimpleProcessor(stats.LogExporter(exp))),
+		sdklog.WithResource(res),
+	)
+	defer shutdownCheck(ctx, lp.Shutdown)
+
+	record := log.Record{}
+	record.SetTimestamp(time.Now())
+	record.SetSeverity(log.SeverityInfo)
+	record.SetBody(log.StringValue(checkName))
+	record.AddAttributes(log.Bool(checkName, true))
+
+	start := time.Now()
+	lp.Logger("otelgen").Emit(ctx, record)
+	return time.Since(start), nil
+}
+
+// shutdownCheck shuts a provider down once its check is done, within the check's timeout
+func shutdownCheck(ctx context.Context, shutdown func(context.Context) error) {
+	if err := shutdown(ctx); err != nil {
+		logger.Debug("failed to stop the provider", zap.Error(err))
+	}
+}
+
+// boundedSpanExporter exports within ctx, as the simple span processor exports
+// without a deadline of its own
+type boundedSpanExporter struct {
+	sdktrace.SpanExporter
+	ctx context.Context
+}
+
+func (e boundedSpanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return e.SpanExporter.ExportSpans(e.ctx, spans)
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/exportstats"
+	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// checkContext returns the context of the check command sent to endpoint over OTLP/HTTP
+func checkContext(t *testing.T, endpoint string, args ...string) *cli.Context {
+	t.Helper()
+	return newTestContext(t, append(getGlobalFlags(), genCheckCommand().Flags...), append([]string{
+		"--otel-exporter-otlp-endpoint", endpoint,
+		"--protocol", "http",
+		"--timeout", "1",
+	}, args...)...)
+}
+
+func TestCheckConnectivity(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusOK},
+		{name: "rejected", status: http.StatusBadRequest, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			paths := map[string]bool{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				paths[r.URL.Path] = true
+				mu.Unlock()
+				w.Header().Set("Content-Type", "application/x-protobuf")
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := checkConnectivity(checkContext(t, srv.URL))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkConnectivity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, signal := range []string{"traces", "metrics", "logs"} {
+				if tt.wantErr && !strings.Contains(err.Error(), signal+":") {
+					t.Errorf("error %q doesn't report the %s check", err, signal)
+				}
+			}
+			for _, path := range []string{"/v1/traces", "/v1/metrics", "/v1/logs"} {
+				if !paths[path] {
+					t.Errorf("the stub collector got no request to %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckConnectivityUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := "http://" + l.Addr().String()
+	l.Close()
+
+	err = checkConnectivity(checkContext(t, endpoint))
+	if err == nil {
+		t.Fatal("checkConnectivity() succeeded against a closed port")
+	}
+	for _, signal := range []string{"traces", "metrics", "logs"} {
+		if !strings.Contains(err.Error(), signal+":") {
+			t.Errorf("error %q doesn't report the %s check", err, signal)
+		}
+	}
+}
+
+func TestRunCollectorChecks(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	exported := func(ctx context.Context, _ *resource.Resource, stats *exportstats.Stats) (time.Duration, error) {
+		return time.Millisecond, stats.SpanExporter(tracetest.NewInMemoryExporter()).ExportSpans(ctx, nil)
+	}
+	silent := func(context.Context, *resource.Resource, *exportstats.Stats) (time.Duration, error) {
+		return 0, nil
+	}
+	failing := func(context.Context, *resource.Resource, *exportstats.Stats) (time.Duration, error) {
+		return 0, errRefused
+	}
+
+	tests := []struct {
+		name     string
+		checks   []namedCheck
+		wantErrs []string
+	}{
+		{name: "all exported", checks: []namedCheck{{"traces", exported}, {"logs", exported}}},
+		{name: "nothing exported", checks: []namedCheck{{"traces", exported}, {"logs", silent}}, wantErrs: []string{"logs: nothing was exported"}},
+		{
+			name:     "several failed",
+			checks:   []namedCheck{{"traces", failing}, {"metrics", exported}, {"logs", failing}},
+			wantErrs: []string{"traces: connection refused", "logs: connection refused"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCollectorChecks(tt.checks, resource.Empty(), time.Second)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("runCollectorChecks() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("runCollectorChecks() succeeded")
+			}
+			if got := strings.Split(err.Error(), "\n"); strings.Join(got, ",") != strings.Join(tt.wantErrs, ",") {
+				t.Errorf("runCollectorChecks() errors = %q, want %q", got, tt.wantErrs)
+			}
+			if strings.Contains(err.Error(), "refused") && !errors.Is(err, errRefused) {
+				t.Errorf("runCollectorChecks() error = %v doesn't wrap the check's error", err)
+			}
+		})
+	}
+}
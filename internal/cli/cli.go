@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/krzko/otelgen/internal/partialsuccess"
 	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +25,23 @@ func initLogger(c *cli.Context) error {
 	default:
 		cfg = zap.NewProductionConfig()
 	}
+
+	level, err := zap.ParseAtomicLevel(c.String("log-level"))
+	if err != nil {
+		return fmt.Errorf("unsupported log level: %s, use one of: debug, info, warn, error", c.String("log-level"))
+	}
+	cfg.Level = level
+
+	switch c.String("log-format") {
+	case "json", "console":
+		// Keep the encoding of the level defaults unless one is asked for explicitly
+		if c.IsSet("log-format") {
+			cfg.Encoding = c.String("log-format")
+		}
+	default:
+		return fmt.Errorf("unsupported log format: %s, use one of: json, console", c.String("log-format"))
+	}
+
 	logger, err = cfg.Build()
 	if err != nil {
 		panic(err)
@@ -30,6 +49,11 @@ func initLogger(c *cli.Context) error {
 
 	defer logger.Sync() // nolint: errcheck
 
+	// Surface the partial successes the OTLP exporters only report to the error handler
+	otel.SetErrorHandler(partialsuccess.NewHandler(logger, func(ps *partialsuccess.Error) {
+		runSummary.Reject(ps.Signal, ps.Rejected, ps)
+	}))
+
 	return err
 }
 
@@ -62,10 +86,12 @@ func New(version, commit, date string) *cli.App {
 		Version: v,
 		Flags:   flags,
 		Commands: []*cli.Command{
+			genBenchCommand(),
+			genCheckCommand(),
 			// genDiagnosticsCommand(),
-			genLogsCommand(),
-			genMetricsCommand(),
-			genTracesCommand(),
+			withSummary(genLogsCommand()),
+			withSummary(genMetricsCommand()),
+			withSummary(genTracesCommand()),
 		},
 		Before: initLogger,
 	}
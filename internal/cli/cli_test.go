@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func TestInitLogger(t *testing.T) {
+	defer func(l *zap.Logger) { logger = l }(logger)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "json", args: []string{"--log-format", "json"}},
+		{name: "console", args: []string{"--log-format", "console"}},
+		{name: "console debug", args: []string{"--log-format", "console", "--log-level", "debug"}},
+		{name: "unknown format", args: []string{"--log-format", "xml"}, wantErr: true},
+		{name: "unknown level", args: []string{"--log-level", "loud"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger = nil
+			err := initLogger(newTestContext(t, getGlobalFlags(), tt.args...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("initLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && logger == nil {
+				t.Error("initLogger() didn't build a logger")
+			}
+		})
+	}
+}
+
+func TestInitLoggerLevel(t *testing.T) {
+	defer func(l *zap.Logger) { logger = l }(logger)
+
+	tests := []struct {
+		level    string
+		wantInfo bool
+	}{
+		{level: "debug", wantInfo: true},
+		{level: "info", wantInfo: true},
+		{level: "warn"},
+		{level: "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if err := initLogger(newTestContext(t, getGlobalFlags(), "--log-level", tt.level)); err != nil {
+				t.Fatalf("initLogger() error = %v", err)
+			}
+			if got := logger.Core().Enabled(zap.InfoLevel); got != tt.wantInfo {
+				t.Errorf("info enabled = %v at %s, want %v", got, tt.level, tt.wantInfo)
+			}
+			if !logger.Core().Enabled(zap.ErrorLevel) {
+				t.Errorf("error disabled at %s", tt.level)
+			}
+		})
+	}
+}
+
+// newTestContext returns a context for a command declaring flags, parsed from args
+func newTestContext(t *testing.T, flags []cli.Flag, args ...string) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet(t.Name(), flag.ContinueOnError)
+	for _, f := range flags {
+		if err := f.Apply(set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+// newMetricsContext returns the context of a metrics subcommand declaring flags, parsed
+// from args, whose parents hold the global and metrics flags parsed from globalArgs
+func newMetricsContext(t *testing.T, flags []cli.Flag, globalArgs []string, args ...string) *cli.Context {
+	t.Helper()
+	parent := newTestContext(t, append(getGlobalFlags(), genMetricsCommand().Flags...), globalArgs...)
+	return newCommandContext(t, parent, flags, args...)
+}
+
+// newCommandContext returns the context of a command declaring flags, parsed from args,
+// run under parent
+func newCommandContext(t *testing.T, parent *cli.Context, flags []cli.Flag, args ...string) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet(t.Name(), flag.ContinueOnError)
+	for _, f := range flags {
+		if err := f.Apply(set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(parent.App, set, parent)
+}
+
+// captureOutput returns what fn writes to stdout and stderr
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+	read := func(f **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		orig := *f
+		*f = w
+		var buf bytes.Buffer
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = io.Copy(&buf, r)
+		}()
+		return func() string {
+			*f = orig
+			w.Close()
+			<-done
+			r.Close()
+			return buf.String()
+		}
+	}
+	stdout, stderr := read(&os.Stdout), read(&os.Stderr)
+	fn()
+	return stdout(), stderr()
+}
@@ -1,18 +1,74 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krzko/otelgen/internal/exportguard"
+	"github.com/krzko/otelgen/internal/kafka"
+	"github.com/krzko/otelgen/internal/payload"
+	"github.com/krzko/otelgen/internal/promrw"
+	"github.com/krzko/otelgen/internal/rateprofile"
+	"github.com/krzko/otelgen/internal/socket"
+	"github.com/krzko/otelgen/internal/syslog"
+	"github.com/krzko/otelgen/internal/timeshift"
 	"github.com/urfave/cli/v2"
 	"github.com/urfave/cli/v2/altsrc"
+	"go.uber.org/zap"
+)
+
+const (
+	// outputOTLP exports telemetry to the OTLP endpoint
+	outputOTLP = "otlp"
+	// outputDiscard counts telemetry and drops it without exporting
+	outputDiscard = "discard"
 )
 
 func getGlobalFlags() []cli.Flag {
 	return []cli.Flag{
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "chaos",
+			Usage: "occasionally give exported spans and metrics odd but valid edge cases, such as empty values, long names, unicode and zero durations",
+			Value: false,
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:  "cycles",
+			Usage: "number of times to repeat the generation run, 0 repeats until interrupted",
+			Value: 1,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "detect-resources",
+			Usage: "detect host, process and OS resource attributes from the environment",
+			Value: false,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "deterministic-ids",
+			Usage: "derive trace and span IDs from --seed so single worker runs emit the same IDs",
+			Value: false,
+		}),
 		altsrc.NewIntFlag(&cli.IntFlag{
 			Name:    "duration",
 			Aliases: []string{"d"},
 			Usage:   "duration in seconds",
 			Value:   0,
 		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "export-stats",
+			Usage: "log a summary of successful and failed exports when the run ends",
+			Value: false,
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:  "flush-interval",
+			Usage: "interval in seconds at which buffered telemetry is force flushed without waiting for the batch, 0 disables it",
+			Value: 0,
+		}),
 		altsrc.NewStringSliceFlag(&cli.StringSliceFlag{
 			Name: "header",
 			// Aliases: []string{"h"},
@@ -31,12 +87,55 @@ func getGlobalFlags() []cli.Flag {
 			// EnvVars: []string{"OTEL_LOG_LEVEL"},
 			Value: "info",
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "log-format",
+			Usage: "encoding used by the logger, one of: json, console",
+			Value: "json",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:  "max-export-errors",
+			Usage: "abort with a non-zero exit after this many consecutive export failures, 0 never aborts",
+			Value: 0,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "name-prefix",
+			Usage: "prefix added to the service name, span names and metric instrument names, to keep runs sharing a backend apart",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "name-suffix",
+			Usage: "suffix added to the service name, span names and metric instrument names",
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:  "otel-exporter-otlp-endpoint",
 			Usage: "target URL to exporter endpoint",
 			// EnvVars: []string{"OTEL_EXPORTER_OTLP_ENDPOINT"},
 			// Required: true,
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "output",
+			Usage: "where telemetry is sent, one of: otlp, discard (counts items without exporting them), tcp://host:port or udp://host:port (streams ndjson to a socket), syslog://host:port or syslog+tcp://host:port (RFC 5424, logs only), promrw://host:port/path (Prometheus remote write, metrics only), kafka://host:port/topic (produces ndjson to a Kafka topic, needs a build with -tags kafka)",
+			Value: outputOTLP,
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:  "payload-size",
+			Usage: fmt.Sprintf("size in bytes of a synthetic %s attribute added to every span and log record, 0 disables it", payload.Key),
+			Value: 0,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "pretty",
+			Usage: "indent the JSON of --tee-stdout, --print-config and --summary-file, set --pretty=false for compact output such as ndjson",
+			Value: true,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "print-config",
+			Usage: "print the resolved configuration as JSON to stderr before generation begins",
+			Value: false,
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:  "progress-interval",
+			Usage: "interval in seconds between progress heartbeat logs, 0 disables them",
+			Value: 10,
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:    "protocol",
 			Usage:   "the transport protocol, one of: grpc, http",
@@ -50,6 +149,31 @@ func getGlobalFlags() []cli.Flag {
 			Usage:   "rate in seconds",
 			Value:   5,
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "rate-profile",
+			Usage: "how the rate is modulated over the duration, one of: constant, linear, spike",
+			Value: string(rateprofile.Constant),
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "rate-unit",
+			Usage: "unit of --rate, one of: s, m, h, counting items per unit for logs and traces and the export interval for metrics",
+			Value: "s",
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "resource-detector-container",
+			Usage: "detect the container.id resource attribute from the cgroup of the running process",
+			Value: false,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "resource-detector-env",
+			Usage: "merge resource attributes from OTEL_RESOURCE_ATTRIBUTES, flags take precedence on conflicts",
+			Value: false,
+		}),
+		altsrc.NewInt64Flag(&cli.Int64Flag{
+			Name:  "seed",
+			Usage: "seed used to derive trace and span IDs when --deterministic-ids is set, and to pick the --chaos edge cases",
+			Value: 0,
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:    "service-name",
 			Usage:   "service name to use",
@@ -57,5 +181,431 @@ func getGlobalFlags() []cli.Flag {
 			// EnvVars: []string{"OTEL_SERVICE_NAME"},
 			Value: "otelgen",
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "service-version",
+			Usage: "service version to use",
+			Value: "0.0.1",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "service-instance-id",
+			Usage: "service instance id to use, defaults to a generated UUID",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:  "shutdown-timeout",
+			Usage: "timeout in seconds for exporters and providers to drain on shutdown",
+			Value: 10,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "since",
+			Usage: "start of a historical window, as an RFC 3339 timestamp, that the run's telemetry is spread evenly across, requires --until and --duration",
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:  "start-time-offset",
+			Usage: "shift the timestamps of all exported telemetry this far into the past to simulate backfill, e.g. 24h",
+			Value: 0,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "strict-attributes",
+			Usage: "reject attributes whose keys collide with resource attributes such as service.name",
+			Value: false,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "summary-file",
+			Usage: "write a JSON summary of the run (counts, duration, achieved rate, errors and a per-signal breakdown) to this file when it ends, including when interrupted",
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "sync-export",
+			Usage: "export each span and log record as soon as it ends instead of batching, useful when debugging",
+			Value: false,
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:  "output-buffer",
+			Usage: "size in bytes of a buffer --tee-stdout batches its writes to stdout in, flushed when full, on flush and on shutdown, 0 writes every export straight through",
+			Value: 0,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:  "tee-stdout",
+			Usage: "also print every exported span, data point and log record to stdout as JSON, e.g. to debug a remote run",
+			Value: false,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "until",
+			Usage: "end of the historical window started by --since, as an RFC 3339 timestamp",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:  "url-path",
+			Usage: "URL path used by the HTTP exporter instead of the default signal path, e.g. /v1/traces",
+		}),
+	}
+}
+
+// parseRateProfile returns the rate profile selected on the command line
+func parseRateProfile(c *cli.Context) (rateprofile.Profile, error) {
+	p, err := rateprofile.Parse(c.String("rate-profile"))
+	if err != nil {
+		return "", err
+	}
+	if p.RequiresDuration() && c.Int("duration") <= 0 {
+		return "", fmt.Errorf("'rate-profile' %s requires 'duration' to be set", p)
+	}
+	return p, nil
+}
+
+// rateUnits maps each --rate-unit to the period --rate is counted over
+var rateUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// parseRateUnit returns the period --rate is counted over
+func parseRateUnit(c *cli.Context) (time.Duration, error) {
+	unit, ok := rateUnits[c.String("rate-unit")]
+	if !ok {
+		return 0, fmt.Errorf("unsupported rate unit: %s, use one of: s, m, h", c.String("rate-unit"))
+	}
+	return unit, nil
+}
+
+// parseRate returns --rate as items per second, scaled from its --rate-unit
+func parseRate(c *cli.Context) (float64, error) {
+	unit, err := parseRateUnit(c)
+	if err != nil {
+		return 0, err
+	}
+	return float64(c.Int64("rate")) / unit.Seconds(), nil
+}
+
+// parseFlushInterval returns the interval between forced flushes, 0 when disabled
+func parseFlushInterval(c *cli.Context) (time.Duration, error) {
+	seconds := c.Int("flush-interval")
+	if seconds < 0 {
+		return 0, fmt.Errorf("'flush-interval' must be greater than or equal to 0")
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// parseOutput returns the output selected on the command line, requiring an
+// endpoint only when exporting over OTLP
+func parseOutput(c *cli.Context) (string, error) {
+	switch output := c.String("output"); output {
+	case outputOTLP:
+		if c.String("otel-exporter-otlp-endpoint") == "" {
+			return "", errors.New("'otel-exporter-otlp-endpoint' must be set")
+		}
+		return output, nil
+	case outputDiscard:
+		return output, nil
+	default:
+		if promrw.IsOutput(output) {
+			if _, err := promrw.ParseAddress(output); err != nil {
+				return "", err
+			}
+			return output, nil
+		}
+		if syslog.IsOutput(output) {
+			if _, _, err := syslog.ParseAddress(output); err != nil {
+				return "", err
+			}
+			return output, nil
+		}
+		if kafka.IsOutput(output) {
+			if _, _, err := kafka.ParseAddress(output); err != nil {
+				return "", err
+			}
+			if !kafka.Enabled {
+				return "", errors.New("kafka output isn't supported by this build, rebuild otelgen with -tags kafka")
+			}
+			return output, nil
+		}
+		if strings.Contains(output, "://") {
+			if _, _, err := socket.ParseAddress(output); err != nil {
+				return "", err
+			}
+			return output, nil
+		}
+		return "", fmt.Errorf("unsupported output: %s, use one of: otlp, discard, tcp://host:port, udp://host:port, syslog://host:port, promrw://host:port/path, kafka://host:port/topic", output)
+	}
+}
+
+// socketOutput returns the tcp:// or udp:// address telemetry is streamed to, or
+// an empty string for any other output
+func socketOutput(output string) string {
+	if output == outputOTLP || output == outputDiscard || syslog.IsOutput(output) || promrw.IsOutput(output) || kafka.IsOutput(output) {
+		return ""
+	}
+	return output
+}
+
+// syslogOutput returns the syslog receiver logs are sent to, or an empty string
+// for any other output
+func syslogOutput(output string) string {
+	if syslog.IsOutput(output) {
+		return output
+	}
+	return ""
+}
+
+// kafkaOutput returns the Kafka topic telemetry is produced to, or an empty string
+// for any other output
+func kafkaOutput(output string) string {
+	if kafka.IsOutput(output) {
+		return output
+	}
+	return ""
+}
+
+// remoteWriteOutput returns the Prometheus remote-write receiver metrics are sent
+// to, or an empty string for any other output
+func remoteWriteOutput(output string) string {
+	if promrw.IsOutput(output) {
+		return output
+	}
+	return ""
+}
+
+// requireNonRemoteWriteOutput rejects a Prometheus remote-write output for signals
+// other than metrics
+func requireNonRemoteWriteOutput(output string) error {
+	if promrw.IsOutput(output) {
+		return errors.New("remote-write output is only supported by the metrics command")
+	}
+	return nil
+}
+
+// requireNonSyslogOutput rejects a syslog output for signals other than logs
+func requireNonSyslogOutput(output string) error {
+	if syslog.IsOutput(output) {
+		return errors.New("syslog output is only supported by the logs command")
+	}
+	return nil
+}
+
+// parseURLPath returns the configured HTTP exporter URL path, which must be absolute
+func parseURLPath(c *cli.Context) (string, error) {
+	p := c.String("url-path")
+	if p != "" && !strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("'url-path' must begin with '/', got %s", p)
+	}
+	return p, nil
+}
+
+// parseEndpoint returns the host:port the exporters connect to, accepting the endpoint
+// either bare or as an http:// or https:// URL. An http:// scheme makes the connection
+// insecure, otherwise the insecure flag decides, and a path on the URL is used as the
+// URL path unless --url-path is set.
+func parseEndpoint(c *cli.Context, insecure bool) (string, bool, string, error) {
+	raw := c.String("otel-exporter-otlp-endpoint")
+	urlPath, err := parseURLPath(c)
+	if err != nil {
+		return "", false, "", err
+	}
+
+	if !strings.Contains(raw, "://") {
+		return raw, insecure, urlPath, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false, "", fmt.Errorf("invalid 'otel-exporter-otlp-endpoint' %s: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http":
+		insecure = true
+	case "https":
+	default:
+		return "", false, "", fmt.Errorf("unsupported 'otel-exporter-otlp-endpoint' scheme: %s, use one of: http, https", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", false, "", fmt.Errorf("invalid 'otel-exporter-otlp-endpoint' %s: missing host", raw)
+	}
+	if urlPath == "" && u.Path != "" && u.Path != "/" {
+		urlPath = u.Path
+	}
+
+	return u.Host, insecure, urlPath, nil
+}
+
+// parseShutdownTimeout returns the shutdown timeout, which must be positive
+func parseShutdownTimeout(c *cli.Context) (time.Duration, error) {
+	seconds := c.Int("shutdown-timeout")
+	if seconds <= 0 {
+		return 0, fmt.Errorf("'shutdown-timeout' must be greater than 0")
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// parsePayloadSize returns the size of the synthetic payload attribute in bytes
+func parsePayloadSize(c *cli.Context) (int, error) {
+	size := c.Int("payload-size")
+	if size < 0 || size > payload.MaxSize {
+		return 0, fmt.Errorf("'payload-size' must be between 0 and %d", payload.MaxSize)
+	}
+	return size, nil
+}
+
+// parseOutputBuffer returns the size in bytes of the --tee-stdout write buffer
+func parseOutputBuffer(c *cli.Context) (int, error) {
+	size := c.Int("output-buffer")
+	if size < 0 {
+		return 0, fmt.Errorf("'output-buffer' must be greater than or equal to 0")
+	}
+	return size, nil
+}
+
+// parseStartTimeOffset returns how far exported timestamps are shifted into the past
+func parseStartTimeOffset(c *cli.Context) (time.Duration, error) {
+	offset := c.Duration("start-time-offset")
+	if offset < 0 {
+		return 0, fmt.Errorf("'start-time-offset' must be greater than or equal to 0")
+	}
+	return offset, nil
+}
+
+// parseWindow returns the historical window a run of duration is replayed across,
+// nil when neither 'since' nor 'until' is set
+func parseWindow(c *cli.Context, duration time.Duration) (*timeshift.Window, error) {
+	since, until := c.String("since"), c.String("until")
+	if since == "" && until == "" {
+		return nil, nil
+	}
+	if since == "" || until == "" {
+		return nil, errors.New("'since' and 'until' must be set together")
+	}
+	if c.Duration("start-time-offset") > 0 {
+		return nil, errors.New("'since' and 'until' can't be combined with 'start-time-offset'")
+	}
+	if duration <= 0 {
+		return nil, errors.New("'since' and 'until' require 'duration' to be set")
+	}
+
+	start, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return nil, fmt.Errorf("'since' must be an RFC 3339 timestamp: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return nil, fmt.Errorf("'until' must be an RFC 3339 timestamp: %w", err)
+	}
+	if end.After(time.Now()) {
+		return nil, errors.New("'until' must not be in the future")
+	}
+	return timeshift.NewWindow(start, end, duration)
+}
+
+// serviceName returns the service name wrapped in --name-prefix and --name-suffix
+func serviceName(c *cli.Context) string {
+	return c.String("name-prefix") + c.String("service-name") + c.String("name-suffix")
+}
+
+// serviceInstanceID returns the configured service instance id or generates a new one
+func serviceInstanceID(c *cli.Context) string {
+	if id := c.String("service-instance-id"); id != "" {
+		return id
+	}
+	return uuid.New().String()
+}
+
+// chaosSeed returns the seed --chaos picks its edge cases with, --seed when it's
+// set so runs can be reproduced
+func chaosSeed(c *cli.Context) int64 {
+	if c.IsSet("seed") {
+		return c.Int64("seed")
+	}
+	return time.Now().UnixNano()
+}
+
+// runCycles runs fn for the configured number of cycles, flushing any buffered
+// telemetry at the end of each one
+func runCycles(c *cli.Context, fn func() error, flush func(context.Context) error) error {
+	cycles := c.Int("cycles")
+	if cycles < 0 {
+		return fmt.Errorf("'cycles' must be greater than or equal to 0")
+	}
+
+	for i := 1; cycles == 0 || i <= cycles; i++ {
+		logger.Info("starting generation cycle", zap.Int("cycle", i))
+		if err := fn(); err != nil {
+			return err
+		}
+		if flush != nil {
+			if err := flush(context.Background()); err != nil {
+				logger.Error("failed to flush telemetry", zap.Int("cycle", i), zap.Error(err))
+			}
+		}
+		logger.Info("generation cycle completed", zap.Int("cycle", i))
+		if err := exportguard.Err(c.Context); err != nil {
+			return err
+		}
+		if c.Context.Err() != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// newExportGuard returns the guard of the command's exporters. It replaces c.Context
+// with a context the guard cancels once --max-export-errors consecutive exports
+// fail, so generation stops and runCycles returns the failure.
+func newExportGuard(c *cli.Context, max int) *exportguard.Guard {
+	ctx, cancel := context.WithCancelCause(c.Context)
+	c.Context = ctx
+	return exportguard.New(max, cancel, logger)
+}
+
+// lineageBool reports whether the named bool flag is set on the command or any of its parents
+func lineageBool(c *cli.Context, name string) bool {
+	for _, ctx := range c.Lineage() {
+		if ctx.Bool(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedHeaderKeys are substrings of header names whose values are never printed
+var redactedHeaderKeys = []string{"authorization", "token", "key"}
+
+// marshalJSON encodes v as JSON, indented unless --pretty=false
+func marshalJSON(c *cli.Context, v interface{}) ([]byte, error) {
+	if c.Bool("pretty") {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return json.Marshal(v)
+}
+
+// printConfig writes cfg as JSON to stderr when --print-config is set, replacing
+// the values of any headers that look like credentials
+func printConfig(c *cli.Context, cfg interface{}) error {
+	if !c.Bool("print-config") {
+		return nil
+	}
+
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	var resolved map[string]interface{}
+	if err := json.Unmarshal(b, &resolved); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if headers, ok := resolved["Headers"].(map[string]interface{}); ok {
+		for k := range headers {
+			for _, secret := range redactedHeaderKeys {
+				if strings.Contains(strings.ToLower(k), secret) {
+					headers[k] = "REDACTED"
+					break
+				}
+			}
+		}
+	}
+
+	out, err := marshalJSON(c, resolved)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
 	}
+	fmt.Fprintln(os.Stderr, string(out))
+	return nil
 }
@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestServiceInstanceID(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "set", args: []string{"--service-instance-id", "instance-1"}, want: "instance-1"},
+		{name: "generated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestContext(t, getGlobalFlags(), tt.args...)
+			got := serviceInstanceID(c)
+			if tt.want != "" {
+				if got != tt.want {
+					t.Errorf("serviceInstanceID() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if _, err := uuid.Parse(got); err != nil {
+				t.Errorf("serviceInstanceID() = %q, want a UUID: %v", got, err)
+			}
+			if again := serviceInstanceID(c); again == got {
+				t.Errorf("serviceInstanceID() generated %q twice", got)
+			}
+		})
+	}
+}
+
+func TestRunCycles(t *testing.T) {
+	errRun := errors.New("run failed")
+
+	tests := []struct {
+		name string
+		args []string
+		// cancelAfter cancels the command's context after that many passes
+		cancelAfter int
+		// failAfter fails the pass with that number
+		failAfter  int
+		wantPasses int
+		wantErr    error
+	}{
+		{name: "three cycles", args: []string{"--cycles", "3"}, wantPasses: 3},
+		{name: "one cycle", args: []string{"--cycles", "1"}, wantPasses: 1},
+		{name: "until interrupted", args: []string{"--cycles", "0"}, cancelAfter: 5, wantPasses: 5},
+		{name: "interrupted", args: []string{"--cycles", "3"}, cancelAfter: 2, wantPasses: 2},
+		{name: "failed pass", args: []string{"--cycles", "3"}, failAfter: 2, wantPasses: 2, wantErr: errRun},
+		{name: "negative", args: []string{"--cycles", "-1"}, wantErr: errors.New("'cycles' must be greater than or equal to 0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestContext(t, getGlobalFlags(), tt.args...)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c.Context = ctx
+
+			var passes, flushes int
+			err := runCycles(c, func() error {
+				passes++
+				if passes == tt.cancelAfter {
+					cancel()
+				}
+				if passes == tt.failAfter {
+					return errRun
+				}
+				return nil
+			}, func(context.Context) error {
+				if flushes != passes-1 {
+					t.Errorf("flushed %d times before pass %d completed", flushes, passes)
+				}
+				flushes++
+				return nil
+			})
+			if (err != nil) != (tt.wantErr != nil) || err != nil && err.Error() != tt.wantErr.Error() {
+				t.Fatalf("runCycles() error = %v, want %v", err, tt.wantErr)
+			}
+			if passes != tt.wantPasses {
+				t.Errorf("runCycles() ran %d passes, want %d", passes, tt.wantPasses)
+			}
+			if tt.wantErr == nil && flushes != passes {
+				t.Errorf("runCycles() flushed %d times over %d passes", flushes, passes)
+			}
+		})
+	}
+}
+
+func TestPrintConfig(t *testing.T) {
+	type config struct {
+		ServiceName string
+		Headers     map[string]string
+	}
+	cfg := config{
+		ServiceName: "otelgen",
+		Headers: map[string]string{
+			"Authorization": "Bearer secret",
+			"X-API-Key":     "secret",
+			"x-auth-token":  "secret",
+			"Content-Type":  "application/x-protobuf",
+		},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want map[string]string
+	}{
+		{name: "disabled"},
+		{
+			name: "redacted",
+			args: []string{"--print-config"},
+			want: map[string]string{
+				"Authorization": "REDACTED",
+				"X-API-Key":     "REDACTED",
+				"x-auth-token":  "REDACTED",
+				"Content-Type":  "application/x-protobuf",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestContext(t, getGlobalFlags(), tt.args...)
+			var err error
+			stdout, stderr := captureOutput(t, func() { err = printConfig(c, cfg) })
+			if err != nil {
+				t.Fatalf("printConfig() error = %v", err)
+			}
+			if stdout != "" {
+				t.Errorf("printConfig() wrote %q to stdout", stdout)
+			}
+			if tt.want == nil {
+				if stderr != "" {
+					t.Errorf("printConfig() printed %q without --print-config", stderr)
+				}
+				return
+			}
+
+			var got config
+			if err := json.Unmarshal([]byte(stderr), &got); err != nil {
+				t.Fatalf("printConfig() printed %q: %v", stderr, err)
+			}
+			if got.ServiceName != cfg.ServiceName {
+				t.Errorf("printed ServiceName = %q, want %q", got.ServiceName, cfg.ServiceName)
+			}
+			if !reflect.DeepEqual(got.Headers, tt.want) {
+				t.Errorf("printed Headers = %v, want %v", got.Headers, tt.want)
+			}
+		})
+	}
+	if cfg.Headers["Authorization"] != "Bearer secret" {
+		t.Error("printConfig() redacted the config it was given")
+	}
+}
+
+func TestURLPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		args      []string
+		wantPaths []string
+		wantErr   bool
+	}{
+		{name: "default", wantPaths: []string{"/v1/logs", "/v1/metrics", "/v1/traces"}},
+		{name: "flag", args: []string{"--url-path", "/gateway/otlp"}, wantPaths: []string{"/gateway/otlp"}},
+		{name: "endpoint path", path: "/ingest", wantPaths: []string{"/ingest"}},
+		{name: "flag over endpoint path", path: "/ingest", args: []string{"--url-path", "/gateway/otlp"}, wantPaths: []string{"/gateway/otlp"}},
+		{name: "relative", args: []string{"--url-path", "gateway"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			paths := map[string]bool{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				paths[r.URL.Path] = true
+				mu.Unlock()
+				w.Header().Set("Content-Type", "application/x-protobuf")
+			}))
+			defer srv.Close()
+
+			err := checkConnectivity(checkContext(t, srv.URL+tt.path, tt.args...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkConnectivity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			got := make([]string, 0, len(paths))
+			for p := range paths {
+				got = append(got, p)
+			}
+			sort.Strings(got)
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("the exporters posted to %v, want %v", got, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		insecure     bool
+		wantEndpoint string
+		wantInsecure bool
+		wantURLPath  string
+		wantErr      bool
+	}{
+		{name: "bare", args: []string{"--otel-exporter-otlp-endpoint", "collector:4317"}, wantEndpoint: "collector:4317"},
+		{name: "bare insecure", args: []string{"--otel-exporter-otlp-endpoint", "collector:4317"}, insecure: true, wantEndpoint: "collector:4317", wantInsecure: true},
+		{name: "http", args: []string{"--otel-exporter-otlp-endpoint", "http://collector:4318"}, wantEndpoint: "collector:4318", wantInsecure: true},
+		{name: "https", args: []string{"--otel-exporter-otlp-endpoint", "https://collector:4318"}, wantEndpoint: "collector:4318"},
+		{name: "https insecure", args: []string{"--otel-exporter-otlp-endpoint", "https://collector:4318"}, insecure: true, wantEndpoint: "collector:4318", wantInsecure: true},
+		{name: "root path", args: []string{"--otel-exporter-otlp-endpoint", "https://collector:4318/"}, wantEndpoint: "collector:4318"},
+		{name: "url path", args: []string{"--otel-exporter-otlp-endpoint", "https://collector:4318/otlp/v1/traces"}, wantEndpoint: "collector:4318", wantURLPath: "/otlp/v1/traces"},
+		{name: "url path flag wins", args: []string{"--otel-exporter-otlp-endpoint", "https://collector:4318/otlp/v1/traces", "--url-path", "/v1/traces"}, wantEndpoint: "collector:4318", wantURLPath: "/v1/traces"},
+		{name: "unsupported scheme", args: []string{"--otel-exporter-otlp-endpoint", "grpc://collector:4317"}, wantErr: true},
+		{name: "missing host", args: []string{"--otel-exporter-otlp-endpoint", "https:///v1/traces"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestContext(t, getGlobalFlags(), tt.args...)
+			endpoint, insecure, urlPath, err := parseEndpoint(c, tt.insecure)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseEndpoint() = %q, want an error", endpoint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEndpoint() error = %v", err)
+			}
+			if endpoint != tt.wantEndpoint || insecure != tt.wantInsecure || urlPath != tt.wantURLPath {
+				t.Errorf("parseEndpoint() = %q, %t, %q, want %q, %t, %q", endpoint, insecure, urlPath, tt.wantEndpoint, tt.wantInsecure, tt.wantURLPath)
+			}
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    float64
+		wantErr bool
+	}{
+		{name: "per second", args: []string{"--rate", "5"}, want: 5},
+		{name: "per minute", args: []string{"--rate", "60", "--rate-unit", "m"}, want: 1},
+		{name: "per hour", args: []string{"--rate", "36", "--rate-unit", "h"}, want: 0.01},
+		{name: "unknown unit", args: []string{"--rate", "1", "--rate-unit", "d"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRate(newTestContext(t, getGlobalFlags(), tt.args...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyFlag(t *testing.T) {
+	v := map[string]int{"points": 1}
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "default", want: "{\n  \"points\": 1\n}"},
+		{name: "compact", args: []string{"--pretty=false"}, want: `{"points":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags, append([]string{"--output", "discard"}, tt.args...))
+			got, err := marshalJSON(c, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("marshalJSON() = %s, want %s", got, tt.want)
+			}
+
+			cfg, err := newMetricsConfig(c)
+			if err != nil {
+				t.Fatalf("newMetricsConfig() error = %v", err)
+			}
+			if cfg.Pretty != (tt.args == nil) {
+				t.Errorf("Pretty = %t with %q", cfg.Pretty, tt.args)
+			}
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/krzko/otelgen/internal/idgen"
 	"github.com/krzko/otelgen/internal/logs"
+	"github.com/krzko/otelgen/internal/payload"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -22,6 +24,7 @@ func genLogsCommand() *cli.Command {
 				Name:    "single",
 				Usage:   "generate a single log event",
 				Aliases: []string{"s"},
+				Flags:   getLogFlags(),
 				Action: func(c *cli.Context) error {
 					return generateLogs(c, true)
 				},
@@ -30,7 +33,7 @@ func genLogsCommand() *cli.Command {
 				Name:    "multi",
 				Usage:   "generate multiple logs",
 				Aliases: []string{"m"},
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.IntFlag{
 						Name:    "number",
 						Aliases: []string{"n"},
@@ -40,7 +43,7 @@ func genLogsCommand() *cli.Command {
 					&cli.IntFlag{
 						Name:    "workers",
 						Aliases: []string{"w"},
-						Usage:   "number of workers (goroutines) to run",
+						Usage:   "number of workers (goroutines) to run, sharing --rate between them so it remains the total",
 						Value:   1,
 					},
 					&cli.IntFlag{
@@ -48,7 +51,7 @@ func genLogsCommand() *cli.Command {
 						Aliases: []string{"d"},
 						Usage:   "duration in seconds for how long to generate logs",
 					},
-				},
+				}, getLogFlags()...),
 				Action: func(c *cli.Context) error {
 					return generateLogs(c, false)
 				},
@@ -57,16 +60,125 @@ func genLogsCommand() *cli.Command {
 	}
 }
 
+// getLogFlags returns the flags that shape the generated log records
+func getLogFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "body-format",
+			Usage: "format of the log body, one of: string, structured",
+			Value: logs.BodyFormatString,
+		},
+		&cli.IntFlag{
+			Name:  "min-body-size",
+			Usage: "pad the message of every log body to at least this many bytes, with a marker followed by filler, 0 disables it",
+			Value: 0,
+		},
+		&cli.StringFlag{
+			Name:  "min-severity",
+			Usage: "lowest severity given to log records, one of: trace, debug, info, warn, error, fatal",
+			Value: "trace",
+		},
+		&cli.StringSliceFlag{
+			Name:  "log-attribute",
+			Usage: "Attributes to add to every log record (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
+		},
+		&cli.StringFlag{
+			Name:  "k8s-namespace",
+			Usage: "k8s.namespace.name reported on the resource and each log record",
+			Value: logs.DefaultK8SNamespace,
+		},
+		&cli.StringFlag{
+			Name:  "k8s-pod",
+			Usage: "k8s.pod.name reported on the resource and each log record, a random name is generated per worker when unset",
+		},
+		&cli.BoolFlag{
+			Name:  "randomize-pod-name",
+			Usage: "generate a new random pod name for every log record instead of one per worker",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "k8s-container",
+			Usage: "k8s.container.name reported on the resource and each log record",
+			Value: logs.DefaultK8SContainer,
+		},
+		&cli.BoolFlag{
+			Name:  "emit-spans",
+			Usage: "record a real trace per request with a span per phase, that the log records reference, exported to the same output",
+			Value: false,
+		},
+		&cli.IntFlag{
+			Name:  "trace-pool-size",
+			Usage: "reuse this many trace IDs round-robin across requests, so each trace spans many log records, 0 gives every request its own trace",
+			Value: 0,
+		},
+		&cli.BoolFlag{
+			Name:  "no-default-attributes",
+			Usage: "omit the default k8s attributes from each log record",
+			Value: false,
+		},
+	}
+}
+
 func generateLogs(c *cli.Context, isSingle bool) error {
-	if c.String("otel-exporter-otlp-endpoint") == "" {
-		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	output, err := parseOutput(c)
+	if err != nil {
+		return err
+	}
+
+	if err := requireNonRemoteWriteOutput(output); err != nil {
+		return err
+	}
+
+	if c.Int("max-export-errors") < 0 {
+		return errors.New("'max-export-errors' must be greater than or equal to 0")
+	}
+
+	attributes, err := parseAttributes(c.StringSlice("log-attribute"))
+	if err != nil {
+		return err
+	}
+
+	if err := checkAttributeKeys(c, attributes); err != nil {
+		return err
+	}
+
+	bodyFormat := c.String("body-format")
+	if bodyFormat != logs.BodyFormatString && bodyFormat != logs.BodyFormatStructured {
+		return fmt.Errorf("unsupported body format: %s, use one of: string, structured", bodyFormat)
+	}
+
+	if c.Bool("emit-spans") && syslogOutput(output) != "" {
+		return errors.New("'emit-spans' can't be used with a syslog output")
 	}
 
 	logsCfg := &logs.Config{
-		Endpoint:    c.String("otel-exporter-otlp-endpoint"),
-		ServiceName: c.String("service-name"),
-		Insecure:    c.Bool("insecure"),
-		UseHTTP:     c.String("protocol") == "http",
+		Endpoint:              c.String("otel-exporter-otlp-endpoint"),
+		ServiceName:           serviceName(c),
+		ServiceVersion:        c.String("service-version"),
+		ServiceInstanceID:     serviceInstanceID(c),
+		DetectResources:       c.Bool("detect-resources"),
+		ResourceFromEnv:       c.Bool("resource-detector-env"),
+		ResourceFromContainer: c.Bool("resource-detector-container"),
+		MaxExportErrors:       c.Int("max-export-errors"),
+		ExportStats:           c.Bool("export-stats"),
+		Summary:               runSummary,
+		SyncExport:            c.Bool("sync-export"),
+		Discard:               output == outputDiscard,
+		Socket:                socketOutput(output),
+		Kafka:                 kafkaOutput(output),
+		TeeStdout:             c.Bool("tee-stdout"),
+		Pretty:                c.Bool("pretty"),
+		Syslog:                syslogOutput(output),
+		BodyFormat:            bodyFormat,
+		Attributes:            attributes,
+		NoDefaultAttributes:   c.Bool("no-default-attributes"),
+		K8SNamespace:          c.String("k8s-namespace"),
+		K8SPod:                c.String("k8s-pod"),
+		K8SContainer:          c.String("k8s-container"),
+		RandomizePodName:      c.Bool("randomize-pod-name"),
+		EmitSpans:             c.Bool("emit-spans"),
+		Insecure:              c.Bool("insecure"),
+		UseHTTP:               c.String("protocol") == "http",
 	}
 
 	// Handle single log generation
@@ -77,7 +189,16 @@ func generateLogs(c *cli.Context, isSingle bool) error {
 		logsCfg.NumLogs = c.Int("number")
 		logsCfg.WorkerCount = c.Int("workers")
 		logsCfg.TotalDuration = time.Duration(c.Int("duration") * int(time.Second))
-		logsCfg.Rate = c.Float64("rate")
+		logsCfg.ProgressInterval = time.Duration(c.Int("progress-interval") * int(time.Second))
+		logsCfg.Rate, err = parseRate(c)
+		if err != nil {
+			return err
+		}
+		rateProfile, err := parseRateProfile(c)
+		if err != nil {
+			return err
+		}
+		logsCfg.RateProfile = rateProfile
 
 		// If neither `NumLogs` nor `TotalDuration` is set, default to indefinite generation
 		if logsCfg.NumLogs == 0 && logsCfg.TotalDuration == 0 {
@@ -86,6 +207,11 @@ func generateLogs(c *cli.Context, isSingle bool) error {
 		}
 	}
 
+	logsCfg.Window, err = parseWindow(c, logsCfg.TotalDuration)
+	if err != nil {
+		return err
+	}
+
 	// Parse headers
 	headers := make(map[string]string)
 	for _, h := range c.StringSlice("header") {
@@ -97,27 +223,93 @@ func generateLogs(c *cli.Context, isSingle bool) error {
 	}
 	logsCfg.Headers = headers
 
-	// Set up logger without stack trace for warnings
-	logger, err := newCustomLogger()
+	logsCfg.ShutdownTimeout, err = parseShutdownTimeout(c)
 	if err != nil {
-		return fmt.Errorf("failed to create logger: %w", err)
+		return err
 	}
 
-	// Run the log generation
-	if err := logs.Run(logsCfg, logger); err != nil {
-		logger.Error("failed to run logs generation", zap.Error(err))
+	logsCfg.StartTimeOffset, err = parseStartTimeOffset(c)
+	if err != nil {
+		return err
+	}
+
+	logsCfg.FlushInterval, err = parseFlushInterval(c)
+	if err != nil {
+		return err
+	}
+
+	logsCfg.PayloadSize, err = parsePayloadSize(c)
+	if err != nil {
+		return err
+	}
+
+	logsCfg.OutputBuffer, err = parseOutputBuffer(c)
+	if err != nil {
+		return err
+	}
+
+	if size := c.Int("min-body-size"); size < 0 || size > payload.MaxSize {
+		return fmt.Errorf("'min-body-size' must be between 0 and %d", payload.MaxSize)
+	}
+	logsCfg.MinBodySize = c.Int("min-body-size")
+
+	logsCfg.MinSeverity, err = logs.ParseSeverity(c.String("min-severity"))
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("deterministic-ids") {
+		logsCfg.IDGenerator = idgen.NewSeeded(c.Int64("seed"))
+	}
+
+	if c.IsSet("trace-pool-size") {
+		if c.Int("trace-pool-size") < 1 {
+			return errors.New("'trace-pool-size' must be greater than or equal to 1")
+		}
+		base := logsCfg.IDGenerator
+		if base == nil {
+			base = idgen.NewCrypto()
+		}
+		logsCfg.IDGenerator = idgen.NewPool(base, c.Int("trace-pool-size"))
+	}
+
+	logsCfg.Endpoint, logsCfg.Insecure, logsCfg.URLPath, err = parseEndpoint(c, logsCfg.Insecure)
+	if err != nil {
+		return err
+	}
+
+	if err := printConfig(c, logsCfg); err != nil {
 		return err
 	}
 
-	return nil
+	// Set up logger without stack trace for warnings
+	logger, err := newCustomLogger(c.String("log-format"), c.String("log-level"))
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	// Run the log generation
+	// Each run creates and shuts down its own provider, which flushes the logs
+	return runCycles(c, func() error {
+		if err := logs.Run(c.Context, logsCfg, logger); err != nil {
+			logger.Error("failed to run logs generation", zap.Error(err))
+			return err
+		}
+		return nil
+	}, nil)
 }
 
-func newCustomLogger() (*zap.Logger, error) {
+func newCustomLogger(format, level string) (*zap.Logger, error) {
+	atomicLevel, err := zap.ParseAtomicLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := zap.Config{
-		Level:       zap.NewAtomicLevelAt(zap.DebugLevel),
+		Level:       atomicLevel,
 		Development: true,
 		Sampling:    nil,
-		Encoding:    "json", // or "console" if you prefer
+		Encoding:    format,
 		EncoderConfig: zapcore.EncoderConfig{
 			MessageKey:    "message",
 			LevelKey:      "level",
@@ -130,13 +322,12 @@ func newCustomLogger() (*zap.Logger, error) {
 			EncodeTime:    zapcore.ISO8601TimeEncoder,
 			EncodeCaller:  zapcore.ShortCallerEncoder,
 		},
-		OutputPaths:      []string{"stdout"},
+		OutputPaths:      []string{"stderr"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
 
 	// Disable stacktrace for warnings and below
 	cfg.EncoderConfig.StacktraceKey = ""
-	cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
 
 	return cfg.Build()
 }
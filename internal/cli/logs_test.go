@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/krzko/otelgen/internal/payload"
+	"go.uber.org/zap"
+)
+
+func TestNewCustomLogger(t *testing.T) {
+	tests := []struct {
+		format  string
+		level   string
+		wantErr bool
+	}{
+		{format: "json", level: "info"},
+		{format: "console", level: "info"},
+		{format: "console", level: "debug"},
+		{format: "xml", level: "info", wantErr: true},
+		{format: "json", level: "loud", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.level, func(t *testing.T) {
+			l, err := newCustomLogger(tt.format, tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newCustomLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if l != nil {
+				l.Info("built")
+			}
+		})
+	}
+}
+
+func TestDiagnosticsGoToStderr(t *testing.T) {
+	defer func(l *zap.Logger) { logger = l }(logger)
+
+	tests := []struct {
+		name string
+		emit func(t *testing.T)
+		want string
+	}{
+		{
+			name: "logs logger",
+			emit: func(t *testing.T) {
+				l, err := newCustomLogger("json", "info")
+				if err != nil {
+					t.Fatal(err)
+				}
+				l.Info("logs diagnostic")
+				_ = l.Sync()
+			},
+			want: "logs diagnostic",
+		},
+		{
+			name: "global logger",
+			emit: func(t *testing.T) {
+				if err := initLogger(newTestContext(t, getGlobalFlags(), "--log-format", "console")); err != nil {
+					t.Fatal(err)
+				}
+				logger.Info("global diagnostic")
+				_ = logger.Sync()
+			},
+			want: "global diagnostic",
+		},
+		{
+			name: "counter deprecation",
+			emit: func(t *testing.T) { _ = generateMetricsCounterCommand.Before(nil) },
+			want: "DEPRECATION WARNING",
+		},
+		{
+			name: "up-down-counter deprecation",
+			emit: func(t *testing.T) { _ = generateMetricsUpDownCounterCommand.Before(nil) },
+			want: "DEPRECATION WARNING",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := captureOutput(t, func() { tt.emit(t) })
+			if stdout != "" {
+				t.Errorf("stdout = %q, want it left for telemetry", stdout)
+			}
+			if !strings.Contains(stderr, tt.want) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracePoolSizeAtLeastOne(t *testing.T) {
+	for _, size := range []string{"0", "-1"} {
+		t.Run(size, func(t *testing.T) {
+			parent := newTestContext(t, getGlobalFlags(), "--output", "discard")
+			err := generateLogs(newCommandContext(t, parent, getLogFlags(), "--trace-pool-size", size), true)
+			if err == nil || !strings.Contains(err.Error(), "trace-pool-size") {
+				t.Errorf("generateLogs() error = %v, want the trace pool size rejected", err)
+			}
+		})
+	}
+}
+
+func TestMinBodySizeBounds(t *testing.T) {
+	for _, size := range []string{"-1", fmt.Sprint(payload.MaxSize + 1)} {
+		t.Run(size, func(t *testing.T) {
+			parent := newTestContext(t, getGlobalFlags(), "--output", "discard")
+			err := generateLogs(newCommandContext(t, parent, getLogFlags(), "--min-body-size", size), true)
+			if err == nil || !strings.Contains(err.Error(), "min-body-size") {
+				t.Errorf("generateLogs() error = %v, want the body size rejected", err)
+			}
+		})
+	}
+}
+
+func TestMinSeverityFlag(t *testing.T) {
+	parent := newTestContext(t, getGlobalFlags(), "--output", "discard")
+	err := generateLogs(newCommandContext(t, parent, getLogFlags(), "--min-severity", "verbose"), true)
+	if err == nil || !strings.Contains(err.Error(), "verbose") {
+		t.Errorf("generateLogs() error = %v, want the severity rejected", err)
+	}
+}
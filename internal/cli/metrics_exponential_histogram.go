@@ -1,13 +1,10 @@
 package cli
 
 import (
-	"context"
-	"errors"
-	"time"
+	"fmt"
 
 	"github.com/krzko/otelgen/internal/metrics"
 	"github.com/urfave/cli/v2"
-	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap"
 )
@@ -17,7 +14,7 @@ var generateMetricsExponentialHistogramCommand = &cli.Command{
 	Usage:       "generate metrics of type exponential histogram",
 	Description: "ExponentialHistogram demonstrates how to measure a distribution of values with high dynamic range",
 	Aliases:     []string{"ehist"},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "temporality",
 			Usage: "Temporality defines the window that an aggregation was calculated over, one of: delta, cumulative",
@@ -30,11 +27,11 @@ var generateMetricsExponentialHistogramCommand = &cli.Command{
 		},
 		&cli.StringSliceFlag{
 			Name:  "attribute",
-			Usage: "Attributes to add to the exponential histogram (format: key=value)",
+			Usage: "Attributes to add to the exponential histogram (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
 		},
 		&cli.IntFlag{
 			Name:  "scale",
-			Usage: "Scale factor for the exponential histogram buckets",
+			Usage: "Scale factor for the exponential histogram buckets, between -10 and 20",
 			Value: 0,
 		},
 		&cli.Float64Flag{
@@ -52,45 +49,56 @@ var generateMetricsExponentialHistogramCommand = &cli.Command{
 			Usage: "Threshold for the zero bucket",
 			Value: 1e-6,
 		},
-	},
+		&cli.Float64Flag{
+			Name:  "zero-fraction",
+			Usage: "Probability (0-1) that a generated value is zero, landing in the zero bucket, every other value is kept above the zero threshold",
+			Value: metrics.DefaultZeroFraction,
+		},
+		&cli.Float64Flag{
+			Name:  "negative-fraction",
+			Usage: "Probability (0-1) that a generated non-zero value is negative, 0 keeps every value positive",
+			Value: metrics.DefaultNegativeFraction,
+		},
+		&cli.StringFlag{
+			Name:  "aggregation",
+			Usage: "Aggregation used to export the exponential histogram, one of: default, explicit, exponential, drop",
+			Value: "default",
+		},
+	}, commonMetricFlags()...),
 	Action: func(c *cli.Context) error {
 		return generateMetricsExponentialHistogramAction(c)
 	},
 }
 
 func generateMetricsExponentialHistogramAction(c *cli.Context) error {
-	if c.String("otel-exporter-otlp-endpoint") == "" {
-		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	scale := c.Int("scale")
+	if scale < int(metrics.MinExponentialHistogramScale) || scale > int(metrics.MaxExponentialHistogramScale) {
+		return fmt.Errorf("'scale' must be between %d and %d, got %d",
+			metrics.MinExponentialHistogramScale, metrics.MaxExponentialHistogramScale, scale)
 	}
 
-	metricsCfg := &metrics.Config{
-		TotalDuration: time.Duration(c.Int("duration") * int(time.Second)),
-		Endpoint:      c.String("otel-exporter-otlp-endpoint"),
-		Rate:          c.Int64("rate"),
-		ServiceName:   c.String("service-name"),
+	zeroFraction := c.Float64("zero-fraction")
+	if zeroFraction < 0 || zeroFraction > 1 {
+		return fmt.Errorf("'zero-fraction' must be between 0 and 1, got %v", zeroFraction)
 	}
 
-	configureLogging(c)
-
-	grpcExpOpt, httpExpOpt := getExporterOptions(c, metricsCfg)
-
-	ctx := context.Background()
+	negativeFraction := c.Float64("negative-fraction")
+	if negativeFraction < 0 || negativeFraction > 1 {
+		return fmt.Errorf("'negative-fraction' must be between 0 and 1, got %v", negativeFraction)
+	}
 
-	exp, err := createExporter(ctx, c, grpcExpOpt, httpExpOpt)
+	name := c.String("service-name") + ".metrics.exponential_histogram"
+	view, err := aggregationView(c.String("aggregation"), name, nil, c.Bool("record-minmax"))
 	if err != nil {
-		logger.Error("failed to obtain OTLP exporter", zap.Error(err))
 		return err
 	}
-	defer shutdownExporter(exp)
-
-	logger.Info("Starting metrics generation")
 
-	reader := metric.NewPeriodicReader(
-		exp,
-		metric.WithInterval(time.Duration(metricsCfg.Rate)*time.Second),
-	)
+	metricsCfg, err := newMetricsConfig(c)
+	if err != nil {
+		return err
+	}
 
-	provider := createMeterProvider(reader, metricsCfg)
+	configureLogging(c)
 
 	temporality := metricdata.CumulativeTemporality
 	if c.String("temporality") == "delta" {
@@ -103,19 +111,36 @@ func generateMetricsExponentialHistogramAction(c *cli.Context) error {
 		return err
 	}
 
+	if err := checkAttributeKeys(c, attributes); err != nil {
+		return err
+	}
+
 	expHistConfig := metrics.ExponentialHistogramConfig{
-		Name:          metricsCfg.ServiceName + ".metrics.exponential_histogram",
-		Description:   "ExponentialHistogram demonstrates how to measure a distribution of values with high dynamic range",
-		Unit:          c.String("unit"),
-		Attributes:    attributes,
-		Temporality:   temporality,
-		Scale:         int32(c.Int("scale")),
-		MaxSize:       c.Float64("max-size"),
-		RecordMinMax:  c.Bool("record-minmax"),
-		ZeroThreshold: c.Float64("zero-threshold"),
+		Name:             name,
+		Description:      "ExponentialHistogram demonstrates how to measure a distribution of values with high dynamic range",
+		Unit:             c.String("unit"),
+		Attributes:       attributes,
+		Temporality:      temporality,
+		Scale:            int32(scale),
+		MaxSize:          c.Float64("max-size"),
+		RecordMinMax:     c.Bool("record-minmax"),
+		ZeroThreshold:    c.Float64("zero-threshold"),
+		ZeroFraction:     zeroFraction,
+		NegativeFraction: negativeFraction,
 	}
 
-	metrics.SimulateExponentialHistogram(provider, expHistConfig, metricsCfg, logger)
+	// Keep the configured scale unless a different aggregation was requested
+	if agg := c.String("aggregation"); agg == "default" || agg == "exponential" {
+		view = metrics.ExponentialHistogramView(expHistConfig)
+	}
+	provider, shutdown, err := newMeterProvider(c, metricsCfg, view)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
 
-	return nil
+	return runCycles(c, func() error {
+		metrics.SimulateExponentialHistogram(c.Context, provider, expHistConfig, metricsCfg, logger)
+		return nil
+	}, provider.ForceFlush)
 }
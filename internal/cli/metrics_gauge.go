@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/krzko/otelgen/internal/metrics"
+	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+var generateMetricsGaugeCommand = &cli.Command{
+	Name:        "gauge",
+	Usage:       "generate metrics of type gauge",
+	Description: "Gauge demonstrates how to measure a value that can go up and down",
+	Aliases:     []string{"g"},
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "temporality",
+			Usage: "Temporality defines the window that an aggregation was calculated over, gauges only support: cumulative",
+			Value: "cumulative",
+		},
+		&cli.StringFlag{
+			Name:  "unit",
+			Usage: "Unit of measurement for the gauge",
+			Value: "1",
+		},
+		&cli.StringSliceFlag{
+			Name:  "attribute",
+			Usage: "Attributes to add to the gauge (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
+		},
+		&cli.Float64Flag{
+			Name:  "min",
+			Usage: "Minimum value for the gauge",
+			Value: 0,
+		},
+		&cli.Float64Flag{
+			Name:  "max",
+			Usage: "Maximum value for the gauge",
+			Value: 100,
+		},
+		&cli.StringSliceFlag{
+			Name:  "attribute-drift",
+			Usage: "Attribute whose value rotates across records (format: key=v1,v2,v3)",
+		},
+		&cli.IntFlag{
+			Name:  "attribute-drift-every",
+			Usage: "Number of records after which drifting attributes move to their next value",
+			Value: 1,
+		},
+		&cli.StringSliceFlag{
+			Name:  "attribute-pool",
+			Usage: "Attribute added to the pool that random subsets are drawn from for each record (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
+		},
+		&cli.IntFlag{
+			Name:  "attributes-per-point",
+			Usage: "Number of attributes drawn at random from the attribute pool for each record",
+			Value: 0,
+		},
+		&cli.StringFlag{
+			Name:  "attributes-csv",
+			Usage: "CSV file whose header row holds attribute keys and each further row a series that records cycle through",
+		},
+	}, commonMetricFlags()...),
+	Action: func(c *cli.Context) error {
+		return generateMetricsGaugeAction(c)
+	},
+}
+
+func generateMetricsGaugeAction(c *cli.Context) error {
+	// Gauges are aggregated as a last value, which has no notion of delta temporality
+	if c.String("temporality") != "cumulative" {
+		return fmt.Errorf("'temporality' %s is not supported for gauge metrics, use cumulative", c.String("temporality"))
+	}
+
+	metricsCfg, err := newMetricsConfig(c)
+	if err != nil {
+		return err
+	}
+
+	if err := setAttributeDrift(c, metricsCfg); err != nil {
+		return err
+	}
+
+	if err := setAttributePool(c, metricsCfg); err != nil {
+		return err
+	}
+
+	if err := setAttributeRows(c, metricsCfg); err != nil {
+		return err
+	}
+
+	configureLogging(c)
+
+	provider, shutdown, err := newMeterProvider(c, metricsCfg)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
+
+	attributes, err := parseAttributes(c.StringSlice("attribute"))
+	if err != nil {
+		logger.Error("failed to parse attributes", zap.Error(err))
+		return err
+	}
+
+	if err := checkAttributeKeys(c, attributes); err != nil {
+		return err
+	}
+
+	gaugeConfig := metrics.GaugeConfig{
+		Name:        metricsCfg.ServiceName + ".metrics.gauge",
+		Description: "Gauge demonstrates how to measure a value that can go up and down",
+		Unit:        c.String("unit"),
+		Attributes:  attributes,
+		Min:         c.Float64("min"),
+		Max:         c.Float64("max"),
+		Temporality: metricdata.CumulativeTemporality,
+	}
+
+	return runCycles(c, func() error {
+		return metrics.SimulateGauge(c.Context, provider, gaugeConfig, metricsCfg, logger)
+	}, provider.ForceFlush)
+}
@@ -1,9 +1,7 @@
 package cli
 
 import (
-	"context"
-	"errors"
-	"time"
+	"fmt"
 
 	"github.com/krzko/otelgen/internal/metrics"
 	"github.com/urfave/cli/v2"
@@ -17,7 +15,7 @@ var generateMetricsHistogramCommand = &cli.Command{
 	Usage:       "generate metrics of type histogram",
 	Description: "Histogram demonstrates how to measure a distribution of values",
 	Aliases:     []string{"hist"},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "temporality",
 			Usage: "Temporality defines the window that an aggregation was calculated over, one of: delta, cumulative",
@@ -30,7 +28,7 @@ var generateMetricsHistogramCommand = &cli.Command{
 		},
 		&cli.StringSliceFlag{
 			Name:  "attribute",
-			Usage: "Attributes to add to the histogram (format: key=value)",
+			Usage: "Attributes to add to the histogram (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
 		},
 		&cli.Float64SliceFlag{
 			Name:  "bounds",
@@ -42,45 +40,93 @@ var generateMetricsHistogramCommand = &cli.Command{
 			Usage: "Record min and max values",
 			Value: true,
 		},
-	},
+		&cli.StringFlag{
+			Name:  "value-type",
+			Usage: "Type of the recorded values, one of: float, int",
+			Value: metrics.ValueTypeFloat,
+		},
+		&cli.StringFlag{
+			Name:  "aggregation",
+			Usage: "Aggregation used to export the histogram, one of: default, explicit, exponential, drop",
+			Value: "default",
+		},
+		&cli.IntFlag{
+			Name:  "series",
+			Usage: fmt.Sprintf("Number of independent series each record is made into, told apart by a %s attribute", metrics.SeriesKey),
+			Value: 1,
+		},
+		&cli.StringSliceFlag{
+			Name:  "attribute-drift",
+			Usage: "Attribute whose value rotates across records (format: key=v1,v2,v3)",
+		},
+		&cli.IntFlag{
+			Name:  "attribute-drift-every",
+			Usage: "Number of records after which drifting attributes move to their next value",
+			Value: 1,
+		},
+		&cli.StringSliceFlag{
+			Name:  "attribute-pool",
+			Usage: "Attribute added to the pool that random subsets are drawn from for each record (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
+		},
+		&cli.IntFlag{
+			Name:  "attributes-per-point",
+			Usage: "Number of attributes drawn at random from the attribute pool for each record",
+			Value: 0,
+		},
+		&cli.StringFlag{
+			Name:  "attributes-csv",
+			Usage: "CSV file whose header row holds attribute keys and each further row a series that records cycle through",
+		},
+	}, commonMetricFlags()...),
 	Action: func(c *cli.Context) error {
 		return generateMetricsHistogramAction(c)
 	},
 }
 
 func generateMetricsHistogramAction(c *cli.Context) error {
-	if c.String("otel-exporter-otlp-endpoint") == "" {
-		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	valueType := c.String("value-type")
+	if valueType != metrics.ValueTypeFloat && valueType != metrics.ValueTypeInt {
+		return fmt.Errorf("'value-type' must be one of: float, int, got %s", valueType)
 	}
 
-	metricsCfg := &metrics.Config{
-		TotalDuration: time.Duration(c.Int("duration") * int(time.Second)),
-		Endpoint:      c.String("otel-exporter-otlp-endpoint"),
-		Rate:          c.Int64("rate"),
-		ServiceName:   c.String("service-name"),
+	if c.Int("series") < 1 {
+		return fmt.Errorf("'series' must be greater than or equal to 1")
 	}
 
-	configureLogging(c)
+	name := c.String("service-name") + ".metrics.histogram"
+	view, err := aggregationView(c.String("aggregation"), name, c.Float64Slice("bounds"), c.Bool("record-minmax"))
+	if err != nil {
+		return err
+	}
 
-	grpcExpOpt, httpExpOpt := getExporterOptions(c, metricsCfg)
+	metricsCfg, err := newMetricsConfig(c)
+	if err != nil {
+		return err
+	}
 
-	ctx := context.Background()
+	if err := setAttributeDrift(c, metricsCfg); err != nil {
+		return err
+	}
 
-	exp, err := createExporter(ctx, c, grpcExpOpt, httpExpOpt)
-	if err != nil {
-		logger.Error("failed to obtain OTLP exporter", zap.Error(err))
+	if err := setAttributePool(c, metricsCfg); err != nil {
 		return err
 	}
-	defer shutdownExporter(exp)
 
-	logger.Info("Starting metrics generation")
+	if err := setAttributeRows(c, metricsCfg); err != nil {
+		return err
+	}
 
-	reader := metric.NewPeriodicReader(
-		exp,
-		metric.WithInterval(time.Duration(metricsCfg.Rate)*time.Second),
-	)
+	configureLogging(c)
 
-	provider := createMeterProvider(reader, metricsCfg)
+	var views []metric.View
+	if view != nil {
+		views = append(views, view)
+	}
+	provider, shutdown, err := newMeterProvider(c, metricsCfg, views...)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
 
 	temporality := metricdata.CumulativeTemporality
 	if c.String("temporality") == "delta" {
@@ -93,17 +139,24 @@ func generateMetricsHistogramAction(c *cli.Context) error {
 		return err
 	}
 
+	if err := checkAttributeKeys(c, attributes); err != nil {
+		return err
+	}
+
 	histogramConfig := metrics.HistogramConfig{
-		Name:         metricsCfg.ServiceName + ".metrics.histogram",
+		Name:         name,
 		Description:  "Histogram demonstrates how to measure a distribution of values",
 		Unit:         c.String("unit"),
 		Attributes:   attributes,
 		Temporality:  temporality,
 		Bounds:       c.Float64Slice("bounds"),
 		RecordMinMax: c.Bool("record-minmax"),
+		ValueType:    valueType,
+		Series:       c.Int("series"),
 	}
 
-	metrics.SimulateHistogram(provider, histogramConfig, metricsCfg, logger)
-
-	return nil
+	return runCycles(c, func() error {
+		metrics.SimulateHistogram(c.Context, provider, histogramConfig, metricsCfg, logger)
+		return nil
+	}, provider.ForceFlush)
 }
@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHistogramSeriesAtLeastOne(t *testing.T) {
+	for _, series := range []string{"0", "-1"} {
+		t.Run(series, func(t *testing.T) {
+			c := newMetricsContext(t, generateMetricsHistogramCommand.Flags, []string{"--output", "discard"}, "--series", series)
+			err := generateMetricsHistogramAction(c)
+			if err == nil || !strings.Contains(err.Error(), "series") {
+				t.Errorf("generateMetricsHistogramAction() error = %v, want the series rejected", err)
+			}
+		})
+	}
+}
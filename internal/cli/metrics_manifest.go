@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/krzko/otelgen/internal/metrics"
+	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+var generateMetricsManifestCommand = &cli.Command{
+	Name:        "from-manifest",
+	Usage:       "generate every metric instrument defined in a YAML manifest",
+	Description: "Manifest emits many instruments with their own names, types, units and attributes on a shared provider",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "file",
+			Aliases:  []string{"f"},
+			Usage:    "path to the YAML manifest listing the instruments to generate",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "temporality",
+			Usage: "Temporality defines the window that an aggregation was calculated over, one of: delta, cumulative",
+			Value: "cumulative",
+		},
+	}, commonMetricFlags()...),
+	Action: func(c *cli.Context) error {
+		return generateMetricsManifestAction(c)
+	},
+}
+
+func generateMetricsManifestAction(c *cli.Context) error {
+	temporality := metricdata.CumulativeTemporality
+	switch c.String("temporality") {
+	case "cumulative":
+	case "delta":
+		temporality = metricdata.DeltaTemporality
+	default:
+		return fmt.Errorf("unsupported temporality: %s, use one of: delta, cumulative", c.String("temporality"))
+	}
+
+	manifest, err := metrics.LoadManifest(c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	metricsCfg, err := newMetricsConfig(c)
+	if err != nil {
+		return err
+	}
+
+	configureLogging(c)
+
+	provider, shutdown, err := newMeterProvider(c, metricsCfg)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
+
+	return runCycles(c, func() error {
+		return metrics.SimulateManifest(c.Context, provider, manifest, temporality, metricsCfg, logger)
+	}, provider.ForceFlush)
+}
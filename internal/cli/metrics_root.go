@@ -2,11 +2,26 @@ package cli
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	grpcZap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
+	"github.com/krzko/otelgen/internal/chaos"
+	"github.com/krzko/otelgen/internal/discard"
+	"github.com/krzko/otelgen/internal/flush"
+	"github.com/krzko/otelgen/internal/kafka"
 	"github.com/krzko/otelgen/internal/metrics"
+	"github.com/krzko/otelgen/internal/promrw"
+	"github.com/krzko/otelgen/internal/resourcedetect"
+	"github.com/krzko/otelgen/internal/shard"
+	"github.com/krzko/otelgen/internal/socket"
+	"github.com/krzko/otelgen/internal/tee"
+	"github.com/krzko/otelgen/internal/timeshift"
 	"github.com/urfave/cli/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
@@ -24,9 +39,36 @@ func genMetricsCommand() *cli.Command {
 		Name:    "metrics",
 		Usage:   "Generate metrics",
 		Aliases: []string{"m"},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "keep-attributes",
+				Usage: "only keep recorded attributes with these keys, dropping all others through a view (format: key1,key2)",
+			},
+			&cli.IntFlag{
+				Name:  "points-per-export",
+				Usage: "number of records each instrument makes between exports, the reader exports every rate x points-per-export seconds",
+				Value: 1,
+			},
+			&cli.IntFlag{
+				Name:  "exemplar-count",
+				Usage: "maximum number of exemplars sampled per instrument, 0 disables them",
+				Value: metrics.DefaultExemplarCount,
+			},
+			&cli.StringFlag{
+				Name:  "exemplar-filter",
+				Usage: "which measurements the SDK samples exemplars from, one of: always_on, always_off, trace_based",
+				Value: string(metrics.ExemplarFilterTraceBased),
+			},
+			&cli.IntFlag{
+				Name:  "connections",
+				Usage: "number of OTLP exporters, each with its own connection, that every export is split across, for very high rates",
+				Value: 1,
+			},
+		},
 		Subcommands: []*cli.Command{
 			generateMetricsCounterCommand,
 			generateMetricsExponentialHistogramCommand,
+			generateMetricsManifestCommand,
 			generateMetricsGaugeCommand,
 			generateMetricsHistogramCommand,
 			generateMetricsSumCommand,
@@ -35,6 +77,154 @@ func genMetricsCommand() *cli.Command {
 	}
 }
 
+// commonMetricFlags returns the flags shared by all metric commands, allowing
+// them to be set after the subcommand as well as globally
+func commonMetricFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "insecure",
+			Usage: "whether to enable client transport security",
+			Value: false,
+		},
+		&cli.Float64Flag{
+			Name:  "timing-jitter",
+			Usage: "fraction (0-1) by which each emission interval is randomly varied, 0 keeps an exact cadence",
+			Value: 0,
+		},
+	}
+}
+
+// newMetricsConfig builds the metrics config shared by all metric commands from the command line flags
+func newMetricsConfig(c *cli.Context) (*metrics.Config, error) {
+	output, err := parseOutput(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireNonSyslogOutput(output); err != nil {
+		return nil, err
+	}
+
+	protocol := c.String("protocol")
+	if protocol != "grpc" && protocol != "http" {
+		return nil, fmt.Errorf("unsupported protocol: %s, use one of: grpc, http", protocol)
+	}
+
+	headers, err := parseHeaders(c)
+	if err != nil {
+		return nil, err
+	}
+
+	rateProfile, err := parseRateProfile(c)
+	if err != nil {
+		return nil, err
+	}
+
+	jitter, err := parseTimingJitter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, insecure, urlPath, err := parseEndpoint(c, lineageBool(c, "insecure"))
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := parseShutdownTimeout(c)
+	if err != nil {
+		return nil, err
+	}
+
+	startTimeOffset, err := parseStartTimeOffset(c)
+	if err != nil {
+		return nil, err
+	}
+
+	flushInterval, err := parseFlushInterval(c)
+	if err != nil {
+		return nil, err
+	}
+
+	outputBuffer, err := parseOutputBuffer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := parseWindow(c, time.Duration(c.Int("duration"))*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Int("max-export-errors") < 0 {
+		return nil, errors.New("'max-export-errors' must be greater than or equal to 0")
+	}
+
+	if c.Int("exemplar-count") < 0 {
+		return nil, errors.New("'exemplar-count' must be greater than or equal to 0")
+	}
+
+	exemplarFilter, err := metrics.ParseExemplarFilter(c.String("exemplar-filter"))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Int("points-per-export") < 1 {
+		return nil, errors.New("'points-per-export' must be greater than or equal to 1")
+	}
+
+	rateUnit, err := parseRateUnit(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Int("connections") < 1 {
+		return nil, errors.New("'connections' must be greater than or equal to 1")
+	}
+	if c.Int("connections") > 1 && output != outputOTLP {
+		return nil, fmt.Errorf("'connections' is only supported with the %s output", outputOTLP)
+	}
+
+	return &metrics.Config{
+		TotalDuration:         time.Duration(c.Int("duration") * int(time.Second)),
+		Rate:                  c.Int64("rate") * int64(rateUnit/time.Second),
+		RateProfile:           rateProfile,
+		TimingJitter:          jitter,
+		ExemplarCount:         c.Int("exemplar-count"),
+		ExemplarFilter:        exemplarFilter,
+		PointsPerExport:       c.Int("points-per-export"),
+		Connections:           c.Int("connections"),
+		KeepAttributes:        c.StringSlice("keep-attributes"),
+		ServiceName:           c.String("service-name"),
+		NamePrefix:            c.String("name-prefix"),
+		Chaos:                 c.Bool("chaos"),
+		ChaosSeed:             chaosSeed(c),
+		NameSuffix:            c.String("name-suffix"),
+		ServiceVersion:        c.String("service-version"),
+		ServiceInstanceID:     serviceInstanceID(c),
+		DetectResources:       c.Bool("detect-resources"),
+		ResourceFromEnv:       c.Bool("resource-detector-env"),
+		ResourceFromContainer: c.Bool("resource-detector-container"),
+		MaxExportErrors:       c.Int("max-export-errors"),
+		ExportStats:           c.Bool("export-stats"),
+		ShutdownTimeout:       shutdownTimeout,
+		StartTimeOffset:       startTimeOffset,
+		FlushInterval:         flushInterval,
+		Window:                window,
+		Discard:               output == outputDiscard,
+		Socket:                socketOutput(output),
+		Kafka:                 kafkaOutput(output),
+		RemoteWrite:           remoteWriteOutput(output),
+		TeeStdout:             c.Bool("tee-stdout"),
+		Pretty:                c.Bool("pretty"),
+		OutputBuffer:          outputBuffer,
+		Endpoint:              endpoint,
+		Insecure:              insecure,
+		UseHTTP:               protocol == "http",
+		Headers:               headers,
+		URLPath:               urlPath,
+	}, nil
+}
+
 // MetricExporter is an interface that abstracts the functionality of both
 // otlpmetricgrpc and otlpmetrichttp exporters.
 type MetricExporter interface {
@@ -63,6 +253,30 @@ func NewMetricExporter(ctx context.Context, protocol string, options interface{}
 	}
 }
 
+// aggregationView returns a view that overrides the aggregation of the named instrument.
+// A nil view is returned when the instrument should keep its default aggregation.
+func aggregationView(aggregation, name string, bounds []float64, recordMinMax bool) (metric.View, error) {
+	var agg metric.Aggregation
+	switch aggregation {
+	case "default":
+		return nil, nil
+	case "explicit":
+		if len(bounds) == 0 {
+			explicit := metric.DefaultAggregationSelector(metric.InstrumentKindHistogram).(metric.AggregationExplicitBucketHistogram)
+			bounds = explicit.Boundaries
+		}
+		agg = metric.AggregationExplicitBucketHistogram{Boundaries: bounds, NoMinMax: !recordMinMax}
+	case "exponential":
+		agg = metric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20, NoMinMax: !recordMinMax}
+	case "drop":
+		agg = metric.AggregationDrop{}
+	default:
+		return nil, fmt.Errorf("unsupported aggregation: %s, use one of: default, explicit, exponential, drop", aggregation)
+	}
+
+	return metric.NewView(metric.Instrument{Name: name}, metric.Stream{Aggregation: agg}), nil
+}
+
 // configureLogging configures the logging level for the gRPC logger
 func configureLogging(c *cli.Context) {
 	if c.String("log-level") == "debug" {
@@ -94,43 +308,104 @@ func createExporter(ctx context.Context, c *cli.Context, grpcExpOpt []otlpmetric
 	return exp, err
 }
 
-// createReader creates a new reader based on the command line flags
-func createMeterProvider(reader metric.Reader, metricsCfg *metrics.Config) *metric.MeterProvider {
-	provider := metric.NewMeterProvider(
-		metric.WithReader(reader),
-		metric.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName(metricsCfg.ServiceName),
-			semconv.DeploymentEnvironment("local"),
-		)),
+// createMeterProvider creates a new meter provider using the reader and any additional views
+func createMeterProvider(reader metric.Reader, metricsCfg *metrics.Config, views ...metric.View) *metric.MeterProvider {
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(metricsCfg.NamePrefix+metricsCfg.ServiceName+metricsCfg.NameSuffix),
+		semconv.ServiceVersion(metricsCfg.ServiceVersion),
+		semconv.ServiceInstanceID(metricsCfg.ServiceInstanceID),
+		semconv.DeploymentEnvironment("local"),
 	)
+	if metricsCfg.DetectResources {
+		detected, err := resourcedetect.Merge(context.Background(), res)
+		if err != nil {
+			logger.Warn("falling back to synthetic resource attributes", zap.Error(err))
+		} else {
+			res = detected
+		}
+	}
+	if metricsCfg.ResourceFromContainer {
+		merged, err := resourcedetect.MergeContainer(context.Background(), res)
+		if err != nil {
+			logger.Warn("ignoring container resource attributes", zap.Error(err))
+		} else {
+			res = merged
+		}
+	}
+	if metricsCfg.ResourceFromEnv {
+		merged, err := resourcedetect.MergeEnv(context.Background(), res)
+		if err != nil {
+			logger.Warn("ignoring resource attributes from the environment", zap.Error(err))
+		} else {
+			res = merged
+		}
+	}
 
-	return provider
-}
+	if len(metricsCfg.KeepAttributes) > 0 {
+		views = []metric.View{attributeFilterView(metricsCfg.KeepAttributes, views)}
+	}
 
-// getExporterOptions returns the exporter options based on the command line flags
-func getExporterOptions(c *cli.Context, mc *metrics.Config) ([]otlpmetricgrpc.Option, []otlpmetrichttp.Option) {
-	grpcExpOpt := []otlpmetricgrpc.Option{
-		otlpmetricgrpc.WithEndpoint(mc.Endpoint),
-		otlpmetricgrpc.WithDialOption(
-			grpc.WithBlock(),
-		),
+	if metricsCfg.NamePrefix != "" || metricsCfg.NameSuffix != "" {
+		views = []metric.View{renameView(metricsCfg.NamePrefix, metricsCfg.NameSuffix, views)}
 	}
 
-	httpExpOpt := []otlpmetrichttp.Option{
-		otlpmetrichttp.WithEndpoint(mc.Endpoint),
+	opts := []metric.Option{
+		metric.WithReader(reader),
+		metric.WithView(views...),
+		metric.WithResource(res),
+	}
+	if metricsCfg.ExemplarFilter != "" {
+		opts = append(opts, metricsCfg.ExemplarFilter.Option())
 	}
 
-	if c.Bool("insecure") {
-		grpcExpOpt = append(grpcExpOpt, otlpmetricgrpc.WithInsecure())
-		httpExpOpt = append(httpExpOpt, otlpmetrichttp.WithInsecure())
+	return metric.NewMeterProvider(opts...)
+}
+
+// attributeFilterView returns a view that applies the first matching view in views,
+// or the default stream when none match, keeping only the attributes with the given keys
+func attributeFilterView(keys []string, views []metric.View) metric.View {
+	allowed := make([]attribute.Key, 0, len(keys))
+	for _, k := range keys {
+		allowed = append(allowed, attribute.Key(k))
+	}
+	filter := attribute.NewAllowKeysFilter(allowed...)
+
+	return func(inst metric.Instrument) (metric.Stream, bool) {
+		stream := metric.Stream{Name: inst.Name, Description: inst.Description, Unit: inst.Unit}
+		for _, v := range views {
+			if s, ok := v(inst); ok {
+				stream = s
+				break
+			}
+		}
+		stream.AttributeFilter = filter
+		return stream, true
 	}
+}
 
-	headers, _ := parseHeaders(c)
-	if len(headers) > 0 {
-		grpcExpOpt = append(grpcExpOpt, otlpmetricgrpc.WithHeaders(headers))
-		httpExpOpt = append(httpExpOpt, otlpmetrichttp.WithHeaders(headers))
+// renameView returns a view that applies the first matching view in views, or the
+// default stream when none match, wrapping the stream name in prefix and suffix
+func renameView(prefix, suffix string, views []metric.View) metric.View {
+	return func(inst metric.Instrument) (metric.Stream, bool) {
+		stream := metric.Stream{Name: inst.Name, Description: inst.Description, Unit: inst.Unit}
+		for _, v := range views {
+			if s, ok := v(inst); ok {
+				stream = s
+				break
+			}
+		}
+		if stream.Name == "" {
+			stream.Name = inst.Name
+		}
+		stream.Name = prefix + stream.Name + suffix
+		return stream, true
 	}
+}
+
+// getExporterOptions returns the exporter options based on the command line flags
+func getExporterOptions(c *cli.Context, mc *metrics.Config) ([]otlpmetricgrpc.Option, []otlpmetrichttp.Option) {
+	grpcExpOpt, httpExpOpt := getConnectionOptions(mc)
 
 	if c.String("temporality") == "delta" {
 		logger.Info("using", zap.String("temporarility", c.String("temporality")))
@@ -149,24 +424,372 @@ func getExporterOptions(c *cli.Context, mc *metrics.Config) ([]otlpmetricgrpc.Op
 	return grpcExpOpt, httpExpOpt
 }
 
-// parseAttributes parses the attributes from the command line and returns a slice of attribute.KeyValue
+// getConnectionOptions returns the exporter options that reach the collector: its
+// endpoint, transport security, URL path and headers
+func getConnectionOptions(mc *metrics.Config) ([]otlpmetricgrpc.Option, []otlpmetrichttp.Option) {
+	grpcExpOpt := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(mc.Endpoint),
+		otlpmetricgrpc.WithDialOption(
+			grpc.WithBlock(),
+		),
+	}
+
+	httpExpOpt := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(mc.Endpoint),
+	}
+
+	if mc.Insecure {
+		grpcExpOpt = append(grpcExpOpt, otlpmetricgrpc.WithInsecure())
+		httpExpOpt = append(httpExpOpt, otlpmetrichttp.WithInsecure())
+	}
+
+	if mc.URLPath != "" {
+		httpExpOpt = append(httpExpOpt, otlpmetrichttp.WithURLPath(mc.URLPath))
+	}
+
+	if len(mc.Headers) > 0 {
+		grpcExpOpt = append(grpcExpOpt, otlpmetricgrpc.WithHeaders(mc.Headers))
+		httpExpOpt = append(httpExpOpt, otlpmetrichttp.WithHeaders(mc.Headers))
+	}
+
+	return grpcExpOpt, httpExpOpt
+}
+
+// parseAttributes parses the attributes from the command line and returns a slice of attribute.KeyValue.
+// Values are strings unless the key is suffixed with a type, as in key:int=5, key:float=0.5 or key:bool=true.
+// A value in brackets is a slice, as in key=[a,b] or key:int=[1,2], whose elements may be double quoted
+// or have their commas escaped with a backslash.
 func parseAttributes(attrs []string) ([]attribute.KeyValue, error) {
 	var result []attribute.KeyValue
-	for i, attr := range attrs {
+	for i, attr := range joinAttributeLists(attrs) {
 		parts := strings.SplitN(attr, "=", 2)
 		if len(parts) != 2 {
 			return nil, fmt.Errorf("invalid attribute format at index %d: %s (expected key=value)", i, attr)
 		}
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
+		typ := "string"
+		if j := strings.LastIndex(key, ":"); j >= 0 && isAttributeType(key[j+1:]) {
+			key, typ = strings.TrimSpace(key[:j]), key[j+1:]
+		}
 		if key == "" {
 			return nil, fmt.Errorf("empty key in attribute at index %d: %s", i, attr)
 		}
-		result = append(result, attribute.String(key, value))
+
+		if strings.HasPrefix(value, "[") || strings.HasSuffix(value, "]") {
+			if len(value) < 2 || !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+				return nil, fmt.Errorf("unbalanced brackets in attribute at index %d: %s", i, attr)
+			}
+			elems, err := splitAttributeList(value[1 : len(value)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid list in attribute at index %d: %s: %w", i, attr, err)
+			}
+			kv, err := typedSliceAttribute(key, typ, elems)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value in attribute at index %d: %s", typ, i, attr)
+			}
+			result = append(result, kv)
+			continue
+		}
+
+		kv, err := typedAttribute(key, typ, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value in attribute at index %d: %s", typ, i, attr)
+		}
+		result = append(result, kv)
 	}
 	return result, nil
 }
 
+// reservedAttributeKeys are the resource attribute keys set by otelgen, the SDK or the
+// resource detectors, which a user attribute of the same key would be confused with
+var reservedAttributeKeys = []attribute.Key{
+	semconv.ServiceNameKey,
+	semconv.ServiceVersionKey,
+	semconv.ServiceInstanceIDKey,
+	semconv.ServiceNamespaceKey,
+	semconv.DeploymentEnvironmentKey,
+	semconv.TelemetrySDKNameKey,
+	semconv.TelemetrySDKLanguageKey,
+	semconv.TelemetrySDKVersionKey,
+	semconv.HostNameKey,
+	semconv.K8SNamespaceNameKey,
+	semconv.K8SContainerNameKey,
+	semconv.K8SPodNameKey,
+	semconv.OSTypeKey,
+	semconv.OSDescriptionKey,
+	semconv.ProcessPIDKey,
+	semconv.ProcessExecutableNameKey,
+	semconv.ProcessExecutablePathKey,
+	semconv.ProcessCommandArgsKey,
+	semconv.ProcessOwnerKey,
+	semconv.ProcessRuntimeNameKey,
+	semconv.ProcessRuntimeVersionKey,
+	semconv.ProcessRuntimeDescriptionKey,
+}
+
+// checkAttributeKeys rejects attributes whose keys collide with a reserved resource
+// attribute key when --strict-attributes is set
+func checkAttributeKeys(c *cli.Context, attrs []attribute.KeyValue) error {
+	if !c.Bool("strict-attributes") {
+		return nil
+	}
+	for _, kv := range attrs {
+		for _, reserved := range reservedAttributeKeys {
+			if kv.Key == reserved {
+				return fmt.Errorf("attribute key %s collides with the resource attribute of the same name", kv.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// isAttributeType reports whether t is a type accepted as a key suffix by parseAttributes
+func isAttributeType(t string) bool {
+	switch t {
+	case "string", "int", "float", "bool":
+		return true
+	}
+	return false
+}
+
+// typedAttribute returns the attribute of type typ holding value
+func typedAttribute(key, typ, value string) (attribute.KeyValue, error) {
+	switch typ {
+	case "int":
+		v, err := strconv.ParseInt(value, 10, 64)
+		return attribute.Int64(key, v), err
+	case "float":
+		v, err := strconv.ParseFloat(value, 64)
+		return attribute.Float64(key, v), err
+	case "bool":
+		v, err := strconv.ParseBool(value)
+		return attribute.Bool(key, v), err
+	default:
+		return attribute.String(key, value), nil
+	}
+}
+
+// typedSliceAttribute returns the slice attribute of type typ holding elems
+func typedSliceAttribute(key, typ string, elems []string) (attribute.KeyValue, error) {
+	switch typ {
+	case "int":
+		vs := make([]int64, len(elems))
+		for i, e := range elems {
+			v, err := strconv.ParseInt(e, 10, 64)
+			if err != nil {
+				return attribute.KeyValue{}, err
+			}
+			vs[i] = v
+		}
+		return attribute.Int64Slice(key, vs), nil
+	case "float":
+		vs := make([]float64, len(elems))
+		for i, e := range elems {
+			v, err := strconv.ParseFloat(e, 64)
+			if err != nil {
+				return attribute.KeyValue{}, err
+			}
+			vs[i] = v
+		}
+		return attribute.Float64Slice(key, vs), nil
+	case "bool":
+		vs := make([]bool, len(elems))
+		for i, e := range elems {
+			v, err := strconv.ParseBool(e)
+			if err != nil {
+				return attribute.KeyValue{}, err
+			}
+			vs[i] = v
+		}
+		return attribute.BoolSlice(key, vs), nil
+	default:
+		return attribute.StringSlice(key, elems), nil
+	}
+}
+
+// joinAttributeLists rejoins the bracketed values that a slice flag split on their
+// commas, appending each element to the value before it while its bracket is open
+func joinAttributeLists(values []string) []string {
+	var joined []string
+	open := false
+	for _, v := range values {
+		if open {
+			joined[len(joined)-1] += "," + v
+		} else {
+			joined = append(joined, v)
+		}
+		last := joined[len(joined)-1]
+		open = strings.Count(last, "[") > strings.Count(last, "]")
+	}
+	return joined
+}
+
+// splitAttributeList splits the comma separated elements of a bracketed attribute value.
+// Commas inside double quotes or preceded by a backslash are kept, and an empty list
+// has no elements.
+func splitAttributeList(s string) ([]string, error) {
+	elems := []string{}
+	if strings.TrimSpace(s) == "" {
+		return elems, nil
+	}
+
+	var b strings.Builder
+	quoted, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			elems = append(elems, strings.TrimSpace(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing escape")
+	}
+	return append(elems, strings.TrimSpace(b.String())), nil
+}
+
+// parseTimingJitter returns the timing jitter, ensuring it is within 0 and 1
+func parseTimingJitter(c *cli.Context) (float64, error) {
+	jitter := c.Float64("timing-jitter")
+	if jitter < 0 || jitter > 1 {
+		return 0, fmt.Errorf("'timing-jitter' must be between 0 and 1, got %v", jitter)
+	}
+	return jitter, nil
+}
+
+// setAttributeDrift parses the drifting attributes from the command line into the metrics config
+func setAttributeDrift(c *cli.Context, mc *metrics.Config) error {
+	every := c.Int("attribute-drift-every")
+	if every < 1 {
+		return fmt.Errorf("'attribute-drift-every' must be greater than or equal to 1")
+	}
+
+	var drift []metrics.AttributeDrift
+	for i, attr := range joinKeyValues(c.StringSlice("attribute-drift")) {
+		parts := strings.SplitN(attr, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid attribute drift format at index %d: %s (expected key=v1,v2,v3)", i, attr)
+		}
+		key := strings.TrimSpace(parts[0])
+		if key == "" {
+			return fmt.Errorf("empty key in attribute drift at index %d: %s", i, attr)
+		}
+		var values []string
+		for _, v := range strings.Split(parts[1], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("no values in attribute drift at index %d: %s", i, attr)
+		}
+		drift = append(drift, metrics.AttributeDrift{Key: key, Values: values})
+	}
+
+	mc.AttributeDrift = drift
+	mc.AttributeDriftEvery = every
+	return nil
+}
+
+// joinKeyValues rejoins key=value flag values that a slice flag split on their commas,
+// appending each element without a key to the value before it
+func joinKeyValues(values []string) []string {
+	var joined []string
+	for _, v := range values {
+		if len(joined) > 0 && !strings.Contains(v, "=") {
+			joined[len(joined)-1] += "," + v
+			continue
+		}
+		joined = append(joined, v)
+	}
+	return joined
+}
+
+// setAttributePool parses the attribute pool from the command line into the metrics config
+func setAttributePool(c *cli.Context, mc *metrics.Config) error {
+	pool, err := parseAttributes(c.StringSlice("attribute-pool"))
+	if err != nil {
+		return err
+	}
+
+	if err := checkAttributeKeys(c, pool); err != nil {
+		return err
+	}
+
+	n := c.Int("attributes-per-point")
+	if n < 0 {
+		return fmt.Errorf("'attributes-per-point' must be greater than or equal to 0")
+	}
+	if n > len(pool) {
+		return fmt.Errorf("'attributes-per-point' must be less than or equal to the attribute pool size %d, got %d", len(pool), n)
+	}
+
+	mc.AttributePool = pool
+	mc.AttributesPerPoint = n
+	return nil
+}
+
+// setAttributeRows loads the attribute rows from the CSV file given on the command line
+// into the metrics config
+func setAttributeRows(c *cli.Context, mc *metrics.Config) error {
+	path := c.String("attributes-csv")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open attributes csv: %w", err)
+	}
+	defer f.Close()
+
+	// Rows are checked against the header width below for a clearer error
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read attributes csv %s: %w", path, err)
+	}
+	if len(records) < 2 {
+		return fmt.Errorf("attributes csv %s must have a header row and at least one data row", path)
+	}
+
+	header := records[0]
+	for i, key := range header {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("empty key in attributes csv %s header at column %d", path, i+1)
+		}
+	}
+
+	rows := make([][]attribute.KeyValue, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) != len(header) {
+			return fmt.Errorf("attributes csv %s row %d has %d fields, expected %d", path, i+2, len(record), len(header))
+		}
+		row := make([]attribute.KeyValue, 0, len(header))
+		for j, value := range record {
+			row = append(row, attribute.String(strings.TrimSpace(header[j]), strings.TrimSpace(value)))
+		}
+		rows = append(rows, row)
+	}
+
+	mc.AttributeRows = rows
+	return nil
+}
+
 // parseHeaders parses the headers from the command line and returns a map of string
 func parseHeaders(c *cli.Context) (map[string]string, error) {
 	headers := make(map[string]string)
@@ -208,13 +831,96 @@ func preferCumulativeTemporalitySelector(kind metric.InstrumentKind) metricdata.
 	}
 }
 
-// shutdownExporter shuts down the exporter
-func shutdownExporter(exp MetricExporter) {
-	defer func() {
+// temporalitySelector returns the temporality selector matching the temporality flag,
+// falling back to delta like the OTLP exporters
+func temporalitySelector(c *cli.Context) metric.TemporalitySelector {
+	if c.String("temporality") == "cumulative" {
+		return preferCumulativeTemporalitySelector
+	}
+	return preferDeltaTemporalitySelector
+}
+
+// newMeterProvider creates a single exporter, periodic reader and meter provider
+// that every instrument of a run is registered on. The returned shutdown func
+// flushes any pending metrics and stops the exporter.
+func newMeterProvider(c *cli.Context, metricsCfg *metrics.Config, views ...metric.View) (*metric.MeterProvider, func(), error) {
+	if err := printConfig(c, metricsCfg); err != nil {
+		return nil, nil, err
+	}
+
+	var exp metric.Exporter
+	if metricsCfg.Discard {
+		logger.Info("discarding metrics instead of exporting them")
+		exp = discard.NewMetricExporter(temporalitySelector(c), logger)
+	} else if metricsCfg.RemoteWrite != "" {
+		logger.Info("sending metrics to a remote-write receiver", zap.String("output", metricsCfg.RemoteWrite))
+		rw, err := promrw.NewMetricExporter(metricsCfg.RemoteWrite, metricsCfg.Headers, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		exp = rw
+	} else if metricsCfg.Socket != "" {
+		logger.Info("streaming metrics to a socket", zap.String("output", metricsCfg.Socket))
+		w, err := socket.NewWriter(metricsCfg.Socket, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		exp = socket.NewMetricExporter(w, temporalitySelector(c))
+	} else if metricsCfg.Kafka != "" {
+		logger.Info("producing metrics to kafka", zap.String("output", metricsCfg.Kafka))
+		w, err := kafka.NewWriter(metricsCfg.Kafka, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		exp = socket.NewMetricExporter(w, temporalitySelector(c))
+	} else {
+		grpcExpOpt, httpExpOpt := getExporterOptions(c, metricsCfg)
+
+		// Each exporter dials its own connection to the endpoint
+		exps := make([]metric.Exporter, metricsCfg.Connections)
+		for i := range exps {
+			var err error
+			exps[i], err = createExporter(context.Background(), c, grpcExpOpt, httpExpOpt)
+			if err != nil {
+				logger.Error("failed to obtain OTLP exporter", zap.Error(err))
+				return nil, nil, err
+			}
+		}
+		if len(exps) > 1 {
+			logger.Info("sharding exports across connections", zap.Int("connections", len(exps)))
+		}
+		exp = shard.MetricExporter(exps)
+	}
+	if metricsCfg.TeeStdout {
+		exp = tee.MetricExporter(exp, socket.NewMetricExporter(socket.Stdout(metricsCfg.Pretty, metricsCfg.OutputBuffer), exp.Temporality))
+	}
+
+	logger.Info("Starting metrics generation")
+
+	metricExp := timeshift.MetricExporter(exp, timeshift.New(metricsCfg.StartTimeOffset, metricsCfg.Window))
+	if metricsCfg.Chaos {
+		metricExp = chaos.New(metricsCfg.ChaosSeed).MetricExporter(metricExp)
+	}
+	metricExp = newExportGuard(c, metricsCfg.MaxExportErrors).MetricExporter(metricExp)
+	metricExp = newExportStats("metrics", metricsCfg.ExportStats).MetricExporter(metricExp)
+
+	reader := metric.NewPeriodicReader(
+		metricExp,
+		metric.WithInterval(metricsCfg.ExportInterval()),
+	)
+
+	provider := createMeterProvider(reader, metricsCfg, views...)
+	stopFlush := flush.Start(logger, metricsCfg.FlushInterval, provider.ForceFlush)
+
+	shutdown := func() {
+		stopFlush()
 		logger.Info("stopping the exporter")
-		if err := exp.Shutdown(context.Background()); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsCfg.ShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
 			logger.Error("failed to stop the exporter", zap.Error(err))
-			return
 		}
-	}()
+	}
+
+	return provider, shutdown, nil
 }
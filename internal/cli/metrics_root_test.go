@@ -0,0 +1,871 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestMetricCommandsAcceptTimingJitter(t *testing.T) {
+	for _, cmd := range genMetricsCommand().Subcommands {
+		t.Run(cmd.Name, func(t *testing.T) {
+			c := newTestContext(t, cmd.Flags, "--timing-jitter", "0.2")
+			got, err := parseTimingJitter(c)
+			if err != nil {
+				t.Fatalf("parseTimingJitter() error = %v", err)
+			}
+			if got != 0.2 {
+				t.Errorf("parseTimingJitter() = %v, want 0.2", got)
+			}
+		})
+	}
+}
+
+func TestParseTimingJitter(t *testing.T) {
+	tests := []struct {
+		jitter  string
+		wantErr bool
+	}{
+		{jitter: "0"},
+		{jitter: "0.5"},
+		{jitter: "1"},
+		{jitter: "-0.1", wantErr: true},
+		{jitter: "1.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jitter, func(t *testing.T) {
+			c := newTestContext(t, commonMetricFlags(), "--timing-jitter", tt.jitter)
+			if _, err := parseTimingJitter(c); (err != nil) != tt.wantErr {
+				t.Errorf("parseTimingJitter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAggregationView(t *testing.T) {
+	const name = "test.histogram"
+
+	tests := []struct {
+		aggregation string
+		bounds      []float64
+		wantType    string
+		wantErr     bool
+	}{
+		{aggregation: "default", wantType: "metricdata.Histogram[float64]"},
+		{aggregation: "explicit", wantType: "metricdata.Histogram[float64]"},
+		{aggregation: "explicit", bounds: []float64{1, 2}, wantType: "metricdata.Histogram[float64]"},
+		{aggregation: "exponential", wantType: "metricdata.ExponentialHistogram[float64]"},
+		{aggregation: "drop"},
+		{aggregation: "summary", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.aggregation, tt.bounds), func(t *testing.T) {
+			view, err := aggregationView(tt.aggregation, name, tt.bounds, true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("aggregationView() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			reader := metric.NewManualReader()
+			opts := []metric.Option{metric.WithReader(reader)}
+			if view != nil {
+				opts = append(opts, metric.WithView(view))
+			}
+			mp := metric.NewMeterProvider(opts...)
+			hist, err := mp.Meter("test").Float64Histogram(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hist.Record(context.Background(), 1.5)
+
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(context.Background(), &rm); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantType == "" {
+				if len(rm.ScopeMetrics) != 0 {
+					t.Errorf("got %d scopes, want the histogram dropped", len(rm.ScopeMetrics))
+				}
+				return
+			}
+			data := rm.ScopeMetrics[0].Metrics[0].Data
+			if got := fmt.Sprintf("%T", data); got != tt.wantType {
+				t.Fatalf("exported %s, want %s", got, tt.wantType)
+			}
+			if hist, ok := data.(metricdata.Histogram[float64]); ok && tt.bounds != nil {
+				if got := hist.DataPoints[0].Bounds; !reflect.DeepEqual(got, tt.bounds) {
+					t.Errorf("bounds = %v, want %v", got, tt.bounds)
+				}
+			}
+		})
+	}
+}
+
+// collectMeterProvider records on a meter provider built from cfg and views and
+// returns what it exports
+func collectMeterProvider(t *testing.T, cfg *metrics.Config, views []metric.View, record func(otelmetric.Meter)) metricdata.ResourceMetrics {
+	t.Helper()
+	reader := metric.NewManualReader()
+	mp := createMeterProvider(reader, cfg, views...)
+	defer func() { _ = mp.Shutdown(context.Background()) }()
+
+	record(mp.Meter("test"))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	return rm
+}
+
+// exportedResource returns the resource of the metrics a meter provider built from cfg exports
+func exportedResource(t *testing.T, cfg *metrics.Config) *resource.Resource {
+	t.Helper()
+	return collectMeterProvider(t, cfg, nil, func(m otelmetric.Meter) {
+		counter, err := m.Int64Counter("test.counter")
+		if err != nil {
+			t.Fatal(err)
+		}
+		counter.Add(context.Background(), 1)
+	}).Resource
+}
+
+func TestMeterProviderResource(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  metrics.Config
+		want map[attribute.Key]string
+		// detect lists attributes that are only set when they're detected
+		detect []attribute.Key
+		// env is set as OTEL_RESOURCE_ATTRIBUTES
+		env string
+	}{
+		{
+			name: "service",
+			cfg:  metrics.Config{ServiceName: "otelgen", ServiceVersion: "1.2.3", ServiceInstanceID: "instance-1"},
+			want: map[attribute.Key]string{
+				semconv.ServiceNameKey:       "otelgen",
+				semconv.ServiceVersionKey:    "1.2.3",
+				semconv.ServiceInstanceIDKey: "instance-1",
+			},
+		},
+		{
+			name: "prefixed",
+			cfg:  metrics.Config{ServiceName: "otelgen", NamePrefix: "load-", ServiceVersion: "1.2.3", ServiceInstanceID: "instance-2"},
+			want: map[attribute.Key]string{
+				semconv.ServiceNameKey:       "load-otelgen",
+				semconv.ServiceVersionKey:    "1.2.3",
+				semconv.ServiceInstanceIDKey: "instance-2",
+			},
+		},
+		{
+			name:   "detected",
+			cfg:    metrics.Config{ServiceName: "otelgen", ServiceVersion: "1.2.3", ServiceInstanceID: "instance-3", DetectResources: true},
+			want:   map[attribute.Key]string{semconv.ServiceNameKey: "otelgen", semconv.ServiceInstanceIDKey: "instance-3"},
+			detect: []attribute.Key{semconv.HostNameKey, semconv.ProcessPIDKey},
+		},
+		{
+			name: "from the environment",
+			cfg:  metrics.Config{ServiceName: "otelgen", ServiceVersion: "1.2.3", ServiceInstanceID: "instance-4", ResourceFromEnv: true},
+			env:  "service.name=from-env,team=platform",
+			want: map[attribute.Key]string{semconv.ServiceNameKey: "otelgen", "team": "platform"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_RESOURCE_ATTRIBUTES", tt.env)
+			res := exportedResource(t, &tt.cfg)
+			for key, want := range tt.want {
+				got, ok := res.Set().Value(key)
+				if !ok {
+					t.Errorf("exported resource has no %s", key)
+					continue
+				}
+				if got.AsString() != want {
+					t.Errorf("exported %s = %q, want %q", key, got.AsString(), want)
+				}
+			}
+			for _, key := range []attribute.Key{semconv.HostNameKey, semconv.ProcessPIDKey} {
+				_, got := res.Set().Value(key)
+				if want := slices.Contains(tt.detect, key); got != want {
+					t.Errorf("exported resource has %s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNameAffixes(t *testing.T) {
+	const name = "test.histogram"
+
+	tests := []struct {
+		name           string
+		prefix, suffix string
+		want           string
+	}{
+		{name: "none", want: name},
+		{name: "prefix", prefix: "load-", want: "load-" + name},
+		{name: "both", prefix: "load-", suffix: "-run2", want: "load-" + name + "-run2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The aggregation view still matches the instrument by its unwrapped name
+			view, err := aggregationView("exponential", name, nil, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := &metrics.Config{ServiceName: "otelgen", NamePrefix: tt.prefix, NameSuffix: tt.suffix}
+			rm := collectMeterProvider(t, cfg, []metric.View{view}, func(m otelmetric.Meter) {
+				hist, err := m.Float64Histogram(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				hist.Record(context.Background(), 1.5)
+			})
+
+			got := rm.ScopeMetrics[0].Metrics[0]
+			if got.Name != tt.want {
+				t.Errorf("instrument name = %q, want %q", got.Name, tt.want)
+			}
+			if _, ok := got.Data.(metricdata.ExponentialHistogram[float64]); !ok {
+				t.Errorf("exported %T, want the view's exponential histogram", got.Data)
+			}
+			service, _ := rm.Resource.Set().Value(semconv.ServiceNameKey)
+			if want := tt.prefix + "otelgen" + tt.suffix; service.AsString() != want {
+				t.Errorf("service name = %q, want %q", service.AsString(), want)
+			}
+		})
+	}
+}
+
+func TestMetricsProtocol(t *testing.T) {
+	tests := []struct {
+		protocol string
+		want     string
+		wantErr  bool
+	}{
+		{protocol: "grpc", want: "*otlpmetricgrpc.Exporter"},
+		{protocol: "http", want: "*otlpmetrichttp.Exporter"},
+		{protocol: "thrift", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags,
+				[]string{"--otel-exporter-otlp-endpoint", "localhost:4317", "--protocol", tt.protocol})
+			cfg, err := newMetricsConfig(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newMetricsConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.UseHTTP != (tt.protocol == "http") {
+				t.Errorf("newMetricsConfig() UseHTTP = %v for protocol %s", cfg.UseHTTP, tt.protocol)
+			}
+
+			grpcExpOpt, httpExpOpt := getConnectionOptions(cfg)
+			exp, err := createExporter(context.Background(), c, grpcExpOpt, httpExpOpt)
+			if err != nil {
+				t.Fatalf("createExporter() error = %v", err)
+			}
+			defer func() { _ = exp.Shutdown(context.Background()) }()
+			if got := fmt.Sprintf("%T", exp); got != tt.want {
+				t.Errorf("createExporter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointsPerExport(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want time.Duration
+	}{
+		{name: "one point every second", args: []string{"--rate", "1"}, want: time.Second},
+		{name: "ten points every second", args: []string{"--rate", "1", "--points-per-export", "10"}, want: 10 * time.Second},
+		{name: "ten points every five seconds", args: []string{"--rate", "5", "--points-per-export", "10"}, want: 50 * time.Second},
+		{name: "three points every two minutes", args: []string{"--rate", "2", "--rate-unit", "m", "--points-per-export", "3"}, want: 6 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			globalArgs := append([]string{"--otel-exporter-otlp-endpoint", "localhost:4317"}, tt.args...)
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags, globalArgs)
+			cfg, err := newMetricsConfig(c)
+			if err != nil {
+				t.Fatalf("newMetricsConfig() error = %v", err)
+			}
+			if got := cfg.ExportInterval(); got != tt.want {
+				t.Errorf("ExportInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExemplarFilterFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    metrics.ExemplarFilter
+		wantErr bool
+	}{
+		{name: "default", want: metrics.ExemplarFilterTraceBased},
+		{name: "always off", args: []string{"--exemplar-filter", "always_off"}, want: metrics.ExemplarFilterAlwaysOff},
+		{name: "unknown", args: []string{"--exemplar-filter", "sometimes"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			globalArgs := append([]string{"--otel-exporter-otlp-endpoint", "localhost:4317"}, tt.args...)
+			cfg, err := newMetricsConfig(newMetricsContext(t, generateMetricsSumCommand.Flags, globalArgs))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newMetricsConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.ExemplarFilter != tt.want {
+				t.Errorf("ExemplarFilter = %q, want %q", cfg.ExemplarFilter, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsInsecure(t *testing.T) {
+	endpoint := []string{"--otel-exporter-otlp-endpoint", "localhost:4317"}
+	tests := []struct {
+		name       string
+		globalArgs []string
+		args       []string
+		want       bool
+	}{
+		{name: "unset", globalArgs: endpoint},
+		{name: "global", globalArgs: append([]string{"--insecure"}, endpoint...), want: true},
+		{name: "subcommand", globalArgs: endpoint, args: []string{"--insecure"}, want: true},
+	}
+	for _, cmd := range genMetricsCommand().Subcommands {
+		for _, tt := range tests {
+			t.Run(cmd.Name+"/"+tt.name, func(t *testing.T) {
+				c := newMetricsContext(t, cmd.Flags, tt.globalArgs, tt.args...)
+				cfg, err := newMetricsConfig(c)
+				if err != nil {
+					t.Fatalf("newMetricsConfig() error = %v", err)
+				}
+				if cfg.Insecure != tt.want {
+					t.Errorf("newMetricsConfig() Insecure = %v, want %v", cfg.Insecure, tt.want)
+				}
+
+				secure := *cfg
+				secure.Insecure = false
+				grpcSecure, httpSecure := getConnectionOptions(&secure)
+				grpcExpOpt, httpExpOpt := getConnectionOptions(cfg)
+				wantExtra := 0
+				if tt.want {
+					wantExtra = 1
+				}
+				if got := len(grpcExpOpt) - len(grpcSecure); got != wantExtra {
+					t.Errorf("got %d extra gRPC options, want %d", got, wantExtra)
+				}
+				if got := len(httpExpOpt) - len(httpSecure); got != wantExtra {
+					t.Errorf("got %d extra HTTP options, want %d", got, wantExtra)
+				}
+			})
+		}
+	}
+}
+
+func TestSetAttributeDrift(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []metrics.AttributeDrift
+		every   int
+		wantErr bool
+	}{
+		{name: "none", every: 1},
+		{
+			name:  "values",
+			args:  []string{"--attribute-drift", "pod.name= a, b ,c", "--attribute-drift-every", "5"},
+			want:  []metrics.AttributeDrift{{Key: "pod.name", Values: []string{"a", "b", "c"}}},
+			every: 5,
+		},
+		{
+			name: "several attributes",
+			args: []string{"--attribute-drift", "pod.name=a,b", "--attribute-drift", "zone=x,y,z"},
+			want: []metrics.AttributeDrift{
+				{Key: "pod.name", Values: []string{"a", "b"}},
+				{Key: "zone", Values: []string{"x", "y", "z"}},
+			},
+			every: 1,
+		},
+		{name: "missing values", args: []string{"--attribute-drift", "pod.name"}, wantErr: true},
+		{name: "empty key", args: []string{"--attribute-drift", "=a,b"}, wantErr: true},
+		{name: "empty values", args: []string{"--attribute-drift", "pod.name=,"}, wantErr: true},
+		{name: "every zero", args: []string{"--attribute-drift-every", "0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestContext(t, generateMetricsSumCommand.Flags, tt.args...)
+			var mc metrics.Config
+			err := setAttributeDrift(c, &mc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setAttributeDrift() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(mc.AttributeDrift, tt.want) || mc.AttributeDriftEvery != tt.every {
+				t.Errorf("setAttributeDrift() = %v every %d, want %v every %d", mc.AttributeDrift, mc.AttributeDriftEvery, tt.want, tt.every)
+			}
+		})
+	}
+}
+
+func TestNewMeterProviderSharesExporter(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	type conn struct {
+		data []byte
+		err  error
+	}
+	conns := make(chan conn, 4)
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				close(conns)
+				return
+			}
+			data, err := io.ReadAll(c)
+			conns <- conn{data, err}
+		}
+	}()
+
+	c := newMetricsContext(t, generateMetricsSumCommand.Flags, []string{"--output", "tcp://" + ln.Addr().String()})
+	cfg, err := newMetricsConfig(c)
+	if err != nil {
+		t.Fatalf("newMetricsConfig() error = %v", err)
+	}
+	provider, shutdown, err := newMeterProvider(c, cfg)
+	if err != nil {
+		t.Fatalf("newMeterProvider() error = %v", err)
+	}
+
+	names := []string{"test.counter", "test.histogram", "test.gauge"}
+	meter := provider.Meter("test")
+	counter, err := meter.Int64Counter(names[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	histogram, err := meter.Float64Histogram(names[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	gauge, err := meter.Float64Gauge(names[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(context.Background(), 1)
+	histogram.Record(context.Background(), 2)
+	gauge.Record(context.Background(), 3)
+
+	shutdown()
+	ln.Close()
+
+	var got []conn
+	for c := range conns {
+		got = append(got, c)
+	}
+	if len(got) != 1 {
+		t.Fatalf("the instruments were exported over %d connections, want one shared exporter", len(got))
+	}
+	if got[0].err != nil {
+		t.Fatal(got[0].err)
+	}
+	for _, name := range names {
+		if !bytes.Contains(got[0].data, []byte(`"`+name+`"`)) {
+			t.Errorf("the shared exporter didn't export %s: %s", name, got[0].data)
+		}
+	}
+}
+
+func TestKeepAttributes(t *testing.T) {
+	attrs := otelmetric.WithAttributes(
+		attribute.String("a", "1"),
+		attribute.String("b", "2"),
+		attribute.String("c", "3"),
+	)
+
+	tests := []struct {
+		name        string
+		keep        []string
+		aggregation string
+		want        []attribute.Key
+		wantType    string
+	}{
+		{name: "all", want: []attribute.Key{"a", "b", "c"}, wantType: "metricdata.Histogram[float64]"},
+		{name: "one", keep: []string{"a"}, want: []attribute.Key{"a"}, wantType: "metricdata.Histogram[float64]"},
+		{name: "several", keep: []string{"a", "c", "missing"}, want: []attribute.Key{"a", "c"}, wantType: "metricdata.Histogram[float64]"},
+		{
+			name:        "with an aggregation view",
+			keep:        []string{"b"},
+			aggregation: "exponential",
+			want:        []attribute.Key{"b"},
+			wantType:    "metricdata.ExponentialHistogram[float64]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var views []metric.View
+			if tt.aggregation != "" {
+				view, err := aggregationView(tt.aggregation, "test.histogram", nil, true)
+				if err != nil {
+					t.Fatal(err)
+				}
+				views = append(views, view)
+			}
+			rm := collectMeterProvider(t, &metrics.Config{KeepAttributes: tt.keep}, views, func(m otelmetric.Meter) {
+				histogram, err := m.Float64Histogram("test.histogram")
+				if err != nil {
+					t.Fatal(err)
+				}
+				histogram.Record(context.Background(), 1, attrs)
+			})
+
+			data := rm.ScopeMetrics[0].Metrics[0].Data
+			if got := reflect.TypeOf(data).String(); got != tt.wantType {
+				t.Errorf("exported %s, want %s", got, tt.wantType)
+			}
+			var set attribute.Set
+			switch d := data.(type) {
+			case metricdata.Histogram[float64]:
+				set = d.DataPoints[0].Attributes
+			case metricdata.ExponentialHistogram[float64]:
+				set = d.DataPoints[0].Attributes
+			}
+			var got []attribute.Key
+			for _, kv := range set.ToSlice() {
+				got = append(got, kv.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("exported attribute keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAttributePool(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantPool int
+		wantN    int
+		wantErr  bool
+	}{
+		{name: "none"},
+		{
+			name:     "subset",
+			args:     []string{"--attribute-pool", "region=eu", "--attribute-pool", "zone=a", "--attribute-pool", "shard:int=3", "--attributes-per-point", "2"},
+			wantPool: 3,
+			wantN:    2,
+		},
+		{name: "more than the pool", args: []string{"--attribute-pool", "region=eu", "--attributes-per-point", "2"}, wantErr: true},
+		{name: "negative", args: []string{"--attribute-pool", "region=eu", "--attributes-per-point", "-1"}, wantErr: true},
+		{name: "invalid attribute", args: []string{"--attribute-pool", "region"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags, nil, tt.args...)
+			var mc metrics.Config
+			err := setAttributePool(c, &mc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setAttributePool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(mc.AttributePool) != tt.wantPool || mc.AttributesPerPoint != tt.wantN {
+				t.Errorf("setAttributePool() = %d attributes, %d per point, want %d, %d", len(mc.AttributePool), mc.AttributesPerPoint, tt.wantPool, tt.wantN)
+			}
+		})
+	}
+}
+
+func TestSetAttributeRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		wantRows [][]attribute.KeyValue
+		wantErr  bool
+	}{
+		{
+			name: "two columns, three rows",
+			csv:  "region,tier\neu,gold\nus, silver\nap,bronze\n",
+			wantRows: [][]attribute.KeyValue{
+				{attribute.String("region", "eu"), attribute.String("tier", "gold")},
+				{attribute.String("region", "us"), attribute.String("tier", "silver")},
+				{attribute.String("region", "ap"), attribute.String("tier", "bronze")},
+			},
+		},
+		{name: "empty", csv: "", wantErr: true},
+		{name: "header only", csv: "region,tier\n", wantErr: true},
+		{name: "short row", csv: "region,tier\neu,gold\nus\n", wantErr: true},
+		{name: "long row", csv: "region,tier\neu,gold,extra\n", wantErr: true},
+		{name: "empty key", csv: "region,\neu,gold\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "attributes.csv")
+			if err := os.WriteFile(path, []byte(tt.csv), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags, nil, "--attributes-csv", path)
+			var mc metrics.Config
+			err := setAttributeRows(c, &mc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setAttributeRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(mc.AttributeRows, tt.wantRows) {
+				t.Errorf("setAttributeRows() = %v, want %v", mc.AttributeRows, tt.wantRows)
+			}
+		})
+	}
+
+	c := newMetricsContext(t, generateMetricsSumCommand.Flags, nil, "--attributes-csv", filepath.Join(t.TempDir(), "missing.csv"))
+	if err := setAttributeRows(c, &metrics.Config{}); err == nil {
+		t.Error("setAttributeRows() succeeded with a missing file, want an error")
+	}
+}
+
+func TestMeterProviderShutdownTimeout(t *testing.T) {
+	// The collector holds every export until the test ends
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	for _, timeout := range []time.Duration{time.Second, 2 * time.Second} {
+		t.Run(timeout.String(), func(t *testing.T) {
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags, []string{
+				"--otel-exporter-otlp-endpoint", srv.Listener.Addr().String(),
+				"--protocol", "http",
+				"--insecure",
+				"--shutdown-timeout", fmt.Sprint(int(timeout.Seconds())),
+			})
+			cfg, err := newMetricsConfig(c)
+			if err != nil {
+				t.Fatalf("newMetricsConfig() error = %v", err)
+			}
+			if cfg.ShutdownTimeout != timeout {
+				t.Errorf("ShutdownTimeout = %s, want %s", cfg.ShutdownTimeout, timeout)
+			}
+			provider, shutdown, err := newMeterProvider(c, cfg)
+			if err != nil {
+				t.Fatalf("newMeterProvider() error = %v", err)
+			}
+			counter, err := provider.Meter("test").Int64Counter("test.counter")
+			if err != nil {
+				t.Fatal(err)
+			}
+			counter.Add(context.Background(), 1)
+
+			start := time.Now()
+			shutdown()
+			if elapsed := time.Since(start); elapsed < timeout || elapsed > timeout+time.Second {
+				t.Errorf("shutdown took %s, want it bounded by the %s timeout", elapsed, timeout)
+			}
+		})
+	}
+
+	for _, seconds := range []string{"0", "-1"} {
+		c := newTestContext(t, getGlobalFlags(), "--shutdown-timeout", seconds)
+		if _, err := parseShutdownTimeout(c); err == nil {
+			t.Errorf("parseShutdownTimeout(%s) succeeded, want an error", seconds)
+		}
+	}
+}
+
+func TestParseAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   []string
+		want    []attribute.KeyValue
+		wantErr bool
+	}{
+		{name: "none", attrs: nil, want: nil},
+		{name: "string", attrs: []string{"region=eu"}, want: []attribute.KeyValue{attribute.String("region", "eu")}},
+		{name: "untyped number stays a string", attrs: []string{"shard=3"}, want: []attribute.KeyValue{attribute.String("shard", "3")}},
+		{name: "explicit string", attrs: []string{"shard:string=3"}, want: []attribute.KeyValue{attribute.String("shard", "3")}},
+		{name: "int", attrs: []string{"shard:int=3"}, want: []attribute.KeyValue{attribute.Int64("shard", 3)}},
+		{name: "float", attrs: []string{"ratio:float=0.25"}, want: []attribute.KeyValue{attribute.Float64("ratio", 0.25)}},
+		{name: "bool", attrs: []string{"canary:bool=true"}, want: []attribute.KeyValue{attribute.Bool("canary", true)}},
+		{name: "colon in the key", attrs: []string{"url:path=/v1"}, want: []attribute.KeyValue{attribute.String("url:path", "/v1")}},
+		{name: "spaces trimmed", attrs: []string{" shard:int = 3 "}, want: []attribute.KeyValue{attribute.Int64("shard", 3)}},
+		{
+			name:  "mixed",
+			attrs: []string{"region=eu", "shard:int=3", "canary:bool=false"},
+			want:  []attribute.KeyValue{attribute.String("region", "eu"), attribute.Int64("shard", 3), attribute.Bool("canary", false)},
+		},
+		{name: "string slice", attrs: []string{"tags=[a,b,c]"}, want: []attribute.KeyValue{attribute.StringSlice("tags", []string{"a", "b", "c"})}},
+		{name: "empty slice", attrs: []string{"tags=[]"}, want: []attribute.KeyValue{attribute.StringSlice("tags", []string{})}},
+		{name: "int slice", attrs: []string{"ports:int=[80, 443]"}, want: []attribute.KeyValue{attribute.Int64Slice("ports", []int64{80, 443})}},
+		{name: "bool slice", attrs: []string{"flags:bool=[true,false]"}, want: []attribute.KeyValue{attribute.BoolSlice("flags", []bool{true, false})}},
+		{name: "quoted comma", attrs: []string{`tags=["a,b",c]`}, want: []attribute.KeyValue{attribute.StringSlice("tags", []string{"a,b", "c"})}},
+		{name: "escaped comma", attrs: []string{`tags=[a\,b,c]`}, want: []attribute.KeyValue{attribute.StringSlice("tags", []string{"a,b", "c"})}},
+		{
+			name:  "slice split by the flag",
+			attrs: []string{"tags=[a", "b]", "region=eu"},
+			want:  []attribute.KeyValue{attribute.StringSlice("tags", []string{"a", "b"}), attribute.String("region", "eu")},
+		},
+		{name: "malformed bracket", attrs: []string{"tags=[a,b"}, wantErr: true},
+		{name: "closing bracket only", attrs: []string{"tags=a,b]"}, wantErr: true},
+		{name: "unterminated quote", attrs: []string{`tags=["a,b]`}, wantErr: true},
+		{name: "malformed int slice", attrs: []string{"ports:int=[80,http]"}, wantErr: true},
+		{name: "malformed int", attrs: []string{"shard:int=three"}, wantErr: true},
+		{name: "malformed float", attrs: []string{"ratio:float=a quarter"}, wantErr: true},
+		{name: "malformed bool", attrs: []string{"canary:bool=maybe"}, wantErr: true},
+		{name: "missing value", attrs: []string{"region"}, wantErr: true},
+		{name: "empty key", attrs: []string{":int=3"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAttributes(tt.attrs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAttributes(%q) error = %v, wantErr %v", tt.attrs, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAttributes(%q) = %v, want %v", tt.attrs, got, tt.want)
+			}
+		})
+	}
+
+	// A slice survives the flag splitting its value on commas
+	c := newMetricsContext(t, generateMetricsSumCommand.Flags, nil, "--attribute", "tags=[a,b]", "--attribute", "region=eu")
+	got, err := parseAttributes(c.StringSlice("attribute"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []attribute.KeyValue{attribute.StringSlice("tags", []string{"a", "b"}), attribute.String("region", "eu")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("--attribute tags=[a,b] = %v, want %v", got, want)
+	}
+}
+
+func TestCheckAttributeKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		globalArgs []string
+		attrs      []string
+		wantErr    bool
+	}{
+		{name: "service.name collision", globalArgs: []string{"--strict-attributes"}, attrs: []string{"service.name=checkout"}, wantErr: true},
+		{name: "host.name collision", globalArgs: []string{"--strict-attributes"}, attrs: []string{"region=eu", "host.name=node-1"}, wantErr: true},
+		{name: "no collision", globalArgs: []string{"--strict-attributes"}, attrs: []string{"region=eu", "service.tier=gold"}},
+		{name: "not strict", attrs: []string{"service.name=checkout"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags, tt.globalArgs)
+			attrs, err := parseAttributes(tt.attrs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkAttributeKeys(c, attrs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkAttributeKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), strings.SplitN(tt.attrs[len(tt.attrs)-1], "=", 2)[0]) {
+				t.Errorf("checkAttributeKeys() error = %v, want it to name the colliding key", err)
+			}
+		})
+	}
+}
+
+func TestConnections(t *testing.T) {
+	var mu sync.Mutex
+	conns := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		conns[r.RemoteAddr]++
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantConns int
+		wantErr   bool
+	}{
+		{name: "default", wantConns: 1},
+		{name: "three", args: []string{"--connections", "3"}, wantConns: 3},
+		{name: "zero", args: []string{"--connections", "0"}, wantErr: true},
+		{name: "not otlp", args: []string{"--connections", "2", "--output", "discard"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			clear(conns)
+			mu.Unlock()
+
+			c := newMetricsContext(t, generateMetricsSumCommand.Flags, append([]string{
+				"--otel-exporter-otlp-endpoint", srv.Listener.Addr().String(),
+				"--protocol", "http",
+				"--insecure",
+			}, tt.args...))
+			cfg, err := newMetricsConfig(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newMetricsConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			provider, shutdown, err := newMeterProvider(c, cfg)
+			if err != nil {
+				t.Fatalf("newMeterProvider() error = %v", err)
+			}
+			meter := provider.Meter("test")
+			for i := 0; i < tt.wantConns; i++ {
+				counter, err := meter.Int64Counter(fmt.Sprintf("test.counter.%d", i))
+				if err != nil {
+					t.Fatal(err)
+				}
+				counter.Add(context.Background(), 1)
+			}
+			shutdown()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(conns) != tt.wantConns {
+				t.Errorf("exported over %d connections, want %d: %v", len(conns), tt.wantConns, conns)
+			}
+		})
+	}
+}
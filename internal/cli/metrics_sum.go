@@ -1,13 +1,10 @@
 package cli
 
 import (
-	"context"
 	"errors"
-	"time"
 
 	"github.com/krzko/otelgen/internal/metrics"
 	"github.com/urfave/cli/v2"
-	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap"
 )
@@ -17,7 +14,7 @@ var generateMetricsSumCommand = &cli.Command{
 	Usage:       "generate metrics of type sum",
 	Description: "Sum demonstrates how to measure additive values over time",
 	Aliases:     []string{"s"},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "temporality",
 			Usage: "Temporality defines the window that an aggregation was calculated over, one of: delta, cumulative",
@@ -30,52 +27,71 @@ var generateMetricsSumCommand = &cli.Command{
 		},
 		&cli.StringSliceFlag{
 			Name:  "attribute",
-			Usage: "Attributes to add to the sum (format: key=value)",
+			Usage: "Attributes to add to the sum (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
 		},
 		&cli.BoolFlag{
 			Name:  "monotonic",
 			Usage: "Whether the sum is monotonic (always increasing)",
 			Value: true,
 		},
-	},
+		&cli.DurationFlag{
+			Name:  "reset-interval",
+			Usage: "Drop the cumulative total back to zero this often to simulate a process restart, e.g. 5m, 0 disables it (monotonic cumulative sums only)",
+			Value: 0,
+		},
+		&cli.StringSliceFlag{
+			Name:  "attribute-drift",
+			Usage: "Attribute whose value rotates across records (format: key=v1,v2,v3)",
+		},
+		&cli.IntFlag{
+			Name:  "attribute-drift-every",
+			Usage: "Number of records after which drifting attributes move to their next value",
+			Value: 1,
+		},
+		&cli.StringSliceFlag{
+			Name:  "attribute-pool",
+			Usage: "Attribute added to the pool that random subsets are drawn from for each record (format: key=value or key=[v1,v2], optionally typed as key:type=value with type one of: int, float, bool)",
+		},
+		&cli.IntFlag{
+			Name:  "attributes-per-point",
+			Usage: "Number of attributes drawn at random from the attribute pool for each record",
+			Value: 0,
+		},
+		&cli.StringFlag{
+			Name:  "attributes-csv",
+			Usage: "CSV file whose header row holds attribute keys and each further row a series that records cycle through",
+		},
+	}, commonMetricFlags()...),
 	Action: func(c *cli.Context) error {
 		return generateMetricsSumAction(c)
 	},
 }
 
 func generateMetricsSumAction(c *cli.Context) error {
-	if c.String("otel-exporter-otlp-endpoint") == "" {
-		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	metricsCfg, err := newMetricsConfig(c)
+	if err != nil {
+		return err
 	}
 
-	metricsCfg := &metrics.Config{
-		TotalDuration: time.Duration(c.Int("duration") * int(time.Second)),
-		Endpoint:      c.String("otel-exporter-otlp-endpoint"),
-		Rate:          c.Int64("rate"),
-		ServiceName:   c.String("service-name"),
+	if err := setAttributeDrift(c, metricsCfg); err != nil {
+		return err
 	}
 
-	configureLogging(c)
+	if err := setAttributePool(c, metricsCfg); err != nil {
+		return err
+	}
 
-	grpcExpOpt, httpExpOpt := getExporterOptions(c, metricsCfg)
+	if err := setAttributeRows(c, metricsCfg); err != nil {
+		return err
+	}
 
-	ctx := context.Background()
+	configureLogging(c)
 
-	exp, err := createExporter(ctx, c, grpcExpOpt, httpExpOpt)
+	provider, shutdown, err := newMeterProvider(c, metricsCfg)
 	if err != nil {
-		logger.Error("failed to obtain OTLP exporter", zap.Error(err))
 		return err
 	}
-	defer shutdownExporter(exp)
-
-	logger.Info("Starting metrics generation")
-
-	reader := metric.NewPeriodicReader(
-		exp,
-		metric.WithInterval(time.Duration(metricsCfg.Rate)*time.Second),
-	)
-
-	provider := createMeterProvider(reader, metricsCfg)
+	defer shutdown()
 
 	temporality := metricdata.CumulativeTemporality
 	if c.String("temporality") == "delta" {
@@ -89,6 +105,10 @@ func generateMetricsSumAction(c *cli.Context) error {
 		return err
 	}
 
+	if err := checkAttributeKeys(c, attributes); err != nil {
+		return err
+	}
+
 	sumConfig := metrics.SumConfig{
 		Name:        metricsCfg.ServiceName + ".metrics.sum",
 		Description: "Sum demonstrates how to measure additive values over time",
@@ -98,7 +118,18 @@ func generateMetricsSumAction(c *cli.Context) error {
 		IsMonotonic: c.Bool("monotonic"),
 	}
 
-	metrics.SimulateSum(provider, sumConfig, metricsCfg, logger)
+	if reset := c.Duration("reset-interval"); reset != 0 {
+		if reset < 0 {
+			return errors.New("'reset-interval' must be greater than or equal to 0")
+		}
+		if temporality != metricdata.CumulativeTemporality || !sumConfig.IsMonotonic {
+			return errors.New("'reset-interval' requires a monotonic sum with cumulative temporality")
+		}
+		sumConfig.ResetInterval = reset
+	}
 
-	return nil
+	return runCycles(c, func() error {
+		metrics.SimulateSum(c.Context, provider, sumConfig, metricsCfg, logger)
+		return nil
+	}, provider.ForceFlush)
 }
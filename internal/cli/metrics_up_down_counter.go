@@ -1,15 +1,12 @@
 package cli
 
 import (
-	"context"
-	"errors"
 	"fmt"
-	"time"
+	"os"
 
 	"github.com/krzko/otelgen/internal/metrics"
 	"github.com/urfave/cli/v2"
-	"go.opentelemetry.io/otel/sdk/metric"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 var generateMetricsUpDownCounterCommand = &cli.Command{
@@ -18,62 +15,51 @@ var generateMetricsUpDownCounterCommand = &cli.Command{
 	Description: "UpDownCounter demonstrates how to measure numbers that can go up and down",
 	Aliases:     []string{"udc"},
 	Hidden:      true,
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "temporality",
-			Usage: "Temporality defines the window that an aggregation was calculated over, one of: delta, cumulative",
+			Usage: "Temporality defines the window that an aggregation was calculated over, one of: delta, cumulative (applies to the synchronous up-down-counter, observable up-down-counters are always cumulative)",
 			Value: "delta",
 		},
-	},
+	}, commonMetricFlags()...),
 	Action: func(c *cli.Context) error {
 		return generateMetricsUpDownCounterAction(c)
 	},
 	Before: func(c *cli.Context) error {
-		fmt.Println("DEPRECATION WARNING: The 'counter' command is deprecated and will be removed in a future version.")
-		fmt.Println("Please use the 'guage' command instead.")
-		fmt.Println("Example: otelgen metrics guage")
-		fmt.Println()
+		fmt.Fprintln(os.Stderr, "DEPRECATION WARNING: The 'up-down-counter' command is deprecated and will be removed in a future version.")
+		fmt.Fprintln(os.Stderr, "Please use the 'gauge' command instead.")
+		fmt.Fprintln(os.Stderr, "Example: otelgen metrics gauge")
+		fmt.Fprintln(os.Stderr)
 		return nil
 	},
 }
 
 func generateMetricsUpDownCounterAction(c *cli.Context) error {
-	var err error
-
-	if c.String("otel-exporter-otlp-endpoint") == "" {
-		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	metricsCfg, err := newMetricsConfig(c)
+	if err != nil {
+		return err
 	}
 
-	metricsCfg := &metrics.Config{
-		TotalDuration: time.Duration(c.Int("duration") * int(time.Second)),
-		Endpoint:      c.String("otel-exporter-otlp-endpoint"),
-		Rate:          c.Int64("rate"),
-		ServiceName:   c.String("service-name"),
+	var temporality metricdata.Temporality
+	switch c.String("temporality") {
+	case "delta":
+		temporality = metricdata.DeltaTemporality
+	case "cumulative":
+		temporality = metricdata.CumulativeTemporality
+	default:
+		return fmt.Errorf("unsupported temporality: %s, use one of: delta, cumulative", c.String("temporality"))
 	}
 
 	configureLogging(c)
 
-	grpcExpOpt, httpExpOpt := getExporterOptions(c, metricsCfg)
-
-	ctx := context.Background()
-
-	exp, err := createExporter(ctx, c, grpcExpOpt, httpExpOpt)
+	provider, shutdown, err := newMeterProvider(c, metricsCfg)
 	if err != nil {
-		logger.Error("failed to obtain OTLP exporter", zap.Error(err))
 		return err
 	}
-	defer shutdownExporter(exp)
+	defer shutdown()
 
-	logger.Info("Starting metrics generation")
-
-	reader := metric.NewPeriodicReader(
-		exp,
-		metric.WithInterval(time.Duration(metricsCfg.Rate)),
-	)
-
-	provider := createMeterProvider(reader, metricsCfg)
-
-	metrics.SimulateUpDownCounter(provider, metricsCfg, logger)
-
-	return nil
+	return runCycles(c, func() error {
+		metrics.SimulateUpDownCounter(c.Context, provider, metrics.UpDownCounterConfig{Temporality: temporality}, metricsCfg, logger)
+		return nil
+	}, provider.ForceFlush)
 }
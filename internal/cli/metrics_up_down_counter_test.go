@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestUpDownCounterTemporality(t *testing.T) {
+	tests := []struct {
+		temporality string
+		want        metricdata.Temporality
+	}{
+		{temporality: "delta", want: metricdata.DeltaTemporality},
+		{temporality: "cumulative", want: metricdata.CumulativeTemporality},
+	}
+	for _, tt := range tests {
+		for _, protocol := range []string{"grpc", "http"} {
+			t.Run(tt.temporality+"/"+protocol, func(t *testing.T) {
+				c := newMetricsContext(t, generateMetricsUpDownCounterCommand.Flags,
+					[]string{"--otel-exporter-otlp-endpoint", "localhost:4317", "--protocol", protocol},
+					"--temporality", tt.temporality)
+				cfg, err := newMetricsConfig(c)
+				if err != nil {
+					t.Fatalf("newMetricsConfig() error = %v", err)
+				}
+
+				grpcExpOpt, httpExpOpt := getExporterOptions(c, cfg)
+				exp, err := createExporter(context.Background(), c, grpcExpOpt, httpExpOpt)
+				if err != nil {
+					t.Fatalf("createExporter() error = %v", err)
+				}
+				defer func() { _ = exp.Shutdown(context.Background()) }()
+
+				if got := exp.Temporality(metric.InstrumentKindUpDownCounter); got != tt.want {
+					t.Errorf("exporter temporality = %v, want %v", got, tt.want)
+				}
+				if got := temporalitySelector(c)(metric.InstrumentKindUpDownCounter); got != tt.want {
+					t.Errorf("temporalitySelector() = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestUpDownCounterRejectsUnknownTemporality(t *testing.T) {
+	c := newMetricsContext(t, generateMetricsUpDownCounterCommand.Flags,
+		[]string{"--otel-exporter-otlp-endpoint", "localhost:4317"},
+		"--temporality", "instant")
+	if err := generateMetricsUpDownCounterAction(c); err == nil {
+		t.Error("generateMetricsUpDownCounterAction() accepted temporality instant")
+	}
+}
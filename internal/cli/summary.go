@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/krzko/otelgen/internal/exportstats"
+	"github.com/krzko/otelgen/internal/summary"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+// runSummary counts the exports of the running command when --summary-file is set,
+// and is nil otherwise
+var runSummary *summary.Summary
+
+// withSummary wraps the action of cmd and its subcommands to write the run summary
+// to --summary-file once the action returns. An interrupt cancels the command's
+// context, so generation stops and the exporters flush, within --shutdown-timeout,
+// before the summary is written.
+func withSummary(cmd *cli.Command) *cli.Command {
+	for _, sub := range cmd.Subcommands {
+		withSummary(sub)
+	}
+	if cmd.Action == nil {
+		return cmd
+	}
+
+	action := cmd.Action
+	cmd.Action = func(c *cli.Context) error {
+		path := c.String("summary-file")
+		if path == "" {
+			return action(c)
+		}
+
+		runSummary = summary.New(commandPath(c))
+		ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		c.Context = ctx
+
+		err := action(c)
+
+		status := summary.Completed
+		switch {
+		case ctx.Err() != nil:
+			runSummary.Error(err)
+			status = summary.Interrupted
+		case err != nil:
+			runSummary.Error(err)
+			status = summary.Failed
+		}
+		writeSummary(path, status, c.Bool("pretty"))
+		if status == summary.Interrupted && err == nil {
+			return cli.Exit("", 130)
+		}
+		return err
+	}
+	return cmd
+}
+
+// newExportStats returns the stats of signal's exports, counted towards the run
+// summary and logged on shutdown when logStats is set
+func newExportStats(signal string, logStats bool) *exportstats.Stats {
+	var l *zap.Logger
+	if logStats {
+		l = logger
+	}
+	return runSummary.Track(exportstats.New(signal, l))
+}
+
+func writeSummary(path, status string, pretty bool) {
+	if err := runSummary.WriteFile(path, status, pretty); err != nil {
+		logger.Error("failed to write the run summary", zap.String("path", path), zap.Error(err))
+		return
+	}
+	logger.Info("wrote the run summary", zap.String("path", path), zap.String("status", status))
+}
+
+// commandPath returns the full name of the running command, e.g. "metrics gauge"
+func commandPath(c *cli.Context) string {
+	var names []string
+	for _, ctx := range c.Lineage() {
+		// The app's own root command isn't part of the path
+		if ctx.Command != nil && ctx.Command.Name != "" && ctx.Command.Name != c.App.Name {
+			names = append([]string{ctx.Command.Name}, names...)
+		}
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krzko/otelgen/internal/summary"
+	"github.com/urfave/cli/v2"
+)
+
+func TestWithSummary(t *testing.T) {
+	defer func(s *summary.Summary) { runSummary = s }(runSummary)
+	errFailed := errors.New("exporter unavailable")
+
+	tests := []struct {
+		name       string
+		action     func(c *cli.Context, cancel context.CancelFunc) error
+		wantStatus string
+		wantCode   int
+		wantErr    error
+	}{
+		{
+			name:       "completed",
+			action:     func(*cli.Context, context.CancelFunc) error { return nil },
+			wantStatus: summary.Completed,
+		},
+		{
+			name:       "failed",
+			action:     func(*cli.Context, context.CancelFunc) error { return errFailed },
+			wantStatus: summary.Failed,
+			wantErr:    errFailed,
+		},
+		{
+			name: "interrupted",
+			action: func(c *cli.Context, cancel context.CancelFunc) error {
+				cancel()
+				<-c.Context.Done()
+				return nil
+			},
+			wantStatus: summary.Interrupted,
+			wantCode:   130,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			path := filepath.Join(t.TempDir(), "summary.json")
+			app := &cli.App{
+				Name: "otelgen",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "summary-file"},
+					&cli.BoolFlag{Name: "pretty"},
+				},
+				Commands: []*cli.Command{withSummary(&cli.Command{
+					Name: "logs",
+					Subcommands: []*cli.Command{{
+						Name:   "single",
+						Action: func(c *cli.Context) error { return tt.action(c, cancel) },
+					}},
+				})},
+				ExitErrHandler: func(*cli.Context, error) {},
+			}
+
+			err := app.RunContext(ctx, []string{"otelgen", "--summary-file", path, "logs", "single"})
+			var exit cli.ExitCoder
+			switch {
+			case tt.wantCode != 0:
+				if !errors.As(err, &exit) || exit.ExitCode() != tt.wantCode {
+					t.Errorf("Run() error = %v, want exit code %d", err, tt.wantCode)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("the summary wasn't written: %v", err)
+			}
+			var r summary.Report
+			if err := json.Unmarshal(b, &r); err != nil {
+				t.Fatal(err)
+			}
+			if r.Command != "logs single" || r.Status != tt.wantStatus {
+				t.Errorf("command %q and status %q, want %q and %q", r.Command, r.Status, "logs single", tt.wantStatus)
+			}
+			if tt.wantErr != nil && (len(r.Errors) != 1 || r.Errors[0] != tt.wantErr.Error()) {
+				t.Errorf("errors = %q, want %q", r.Errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithSummaryWithoutFile(t *testing.T) {
+	defer func(s *summary.Summary) { runSummary = s }(runSummary)
+	runSummary = nil
+
+	app := &cli.App{
+		Flags: []cli.Flag{&cli.StringFlag{Name: "summary-file"}},
+		Commands: []*cli.Command{withSummary(&cli.Command{
+			Name:   "traces",
+			Action: func(*cli.Context) error { return nil },
+		})},
+	}
+	if err := app.Run([]string{"otelgen", "traces"}); err != nil {
+		t.Fatal(err)
+	}
+	if runSummary != nil {
+		t.Error("a summary was tracked without --summary-file")
+	}
+}
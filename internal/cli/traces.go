@@ -9,6 +9,7 @@ import (
 
 	grpcZap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
@@ -17,7 +18,17 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
 	"google.golang.org/grpc"
 
+	"github.com/krzko/otelgen/internal/chaos"
+	"github.com/krzko/otelgen/internal/discard"
+	"github.com/krzko/otelgen/internal/flush"
+	"github.com/krzko/otelgen/internal/idgen"
+	"github.com/krzko/otelgen/internal/kafka"
+	"github.com/krzko/otelgen/internal/resourcedetect"
+	"github.com/krzko/otelgen/internal/socket"
+	"github.com/krzko/otelgen/internal/tee"
+	"github.com/krzko/otelgen/internal/timeshift"
 	"github.com/krzko/otelgen/internal/traces"
+	"github.com/krzko/otelgen/internal/traces/scenarios"
 
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
@@ -29,11 +40,12 @@ func genTracesCommand() *cli.Command {
 		Usage:   "Generate traces",
 		Aliases: []string{"t"},
 		Subcommands: []*cli.Command{
+			genScenariosCommand(),
 			{
 				Name:    "single",
 				Usage:   "generate a single trace",
 				Aliases: []string{"s"},
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.BoolFlag{
 						Name:    "marshal",
 						Aliases: []string{"m"},
@@ -43,10 +55,10 @@ func genTracesCommand() *cli.Command {
 					&cli.StringFlag{
 						Name:    "scenario",
 						Aliases: []string{"s"},
-						Usage:   "The trace scenario to simulate (basic, eventing, microservices, web_mobile)",
+						Usage:   "The trace scenario to simulate (basic, clock_skew, deep, eventing, exception, fan_out, microservices, web_mobile)",
 						Value:   "basic",
 					},
-				},
+				}, getScenarioFlags()...),
 				Action: func(c *cli.Context) error {
 					return generateTraces(c, true)
 				},
@@ -55,11 +67,11 @@ func genTracesCommand() *cli.Command {
 				Name:    "multi",
 				Usage:   "generate multiple traces",
 				Aliases: []string{"m"},
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.StringSliceFlag{
 						Name:    "scenarios",
 						Aliases: []string{"s"},
-						Usage:   "The trace scenarios to simulate (basic, web_request, mobile_request, event_driven, pub_sub, microservices, database_operation)",
+						Usage:   "The trace scenarios to simulate (basic, clock_skew, deep, eventing, exception, fan_out, microservices, web_mobile)",
 						Value:   cli.NewStringSlice("basic"),
 					},
 					&cli.IntFlag{
@@ -71,10 +83,10 @@ func genTracesCommand() *cli.Command {
 					&cli.IntFlag{
 						Name:    "workers",
 						Aliases: []string{"w"},
-						Usage:   "number of workers (goroutines) to run",
+						Usage:   "number of workers (goroutines) to run, sharing --rate between them so it remains the total",
 						Value:   1,
 					},
-				},
+				}, getScenarioFlags()...),
 				Action: func(c *cli.Context) error {
 					return generateTraces(c, false)
 				},
@@ -83,16 +95,211 @@ func genTracesCommand() *cli.Command {
 	}
 }
 
+// getScenarioFlags returns the flags that tune the behaviour of the trace scenarios
+func getScenarioFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "span-count",
+			Usage: "number of child spans emitted by fan-out scenarios such as fan_out and microservices",
+			Value: scenarios.DefaultSpanCount,
+		},
+		&cli.IntFlag{
+			Name:  "max-trace-depth",
+			Usage: "number of nested spans emitted by the deep scenario",
+			Value: scenarios.DefaultMaxTraceDepth,
+		},
+		&cli.IntFlag{
+			Name:  "clock-skew",
+			Usage: "milliseconds by which the clock_skew scenario shifts child spans from their parent",
+			Value: int(scenarios.DefaultClockSkew.Milliseconds()),
+		},
+		&cli.StringFlag{
+			Name:  "status-message",
+			Usage: "status description set on error spans, {service} is replaced by the failing service name",
+			Value: scenarios.DefaultStatusMessage,
+		},
+		&cli.StringFlag{
+			Name:  "span-kind",
+			Usage: "kind of the basic scenario's root span, one of: client, server, producer, consumer, internal",
+			Value: "client",
+		},
+		&cli.StringFlag{
+			Name:  "link-mode",
+			Usage: "how the eventing scenario's consumer relates to its producer, one of: parent (same trace), link (new trace linked to the producer)",
+			Value: string(scenarios.LinkModeLink),
+		},
+		&cli.IntFlag{
+			Name:  "span-attribute-count",
+			Usage: "number of synthetic attr.N attributes added to every span",
+			Value: 0,
+		},
+		&cli.StringSliceFlag{
+			Name:  "baggage",
+			Usage: "baggage members set on the context of every scenario (format: key=value)",
+		},
+		&cli.BoolFlag{
+			Name:  "baggage-to-attributes",
+			Usage: "copy the baggage members of each span's context onto the span as attributes",
+			Value: false,
+		},
+		&cli.IntFlag{
+			Name:  "scenario-retries",
+			Usage: "number of times a failing scenario is run again before giving up",
+			Value: 0,
+		},
+		&cli.IntFlag{
+			Name:  "scenario-backoff",
+			Usage: "milliseconds to wait before retrying a failing scenario, doubling after each retry",
+			Value: int(traces.DefaultScenarioBackoff.Milliseconds()),
+		},
+		&cli.Float64Flag{
+			Name:  "span-rate",
+			Usage: "maximum spans started per second across all workers regardless of the trace rate, 0 is unthrottled",
+			Value: 0,
+		},
+		&cli.StringFlag{
+			Name:  "sampler",
+			Usage: "sampler used by the tracer provider, one of: always_on, always_off, traceidratio, parentbased_always_on, parentbased_always_off, parentbased_traceidratio",
+			Value: "traceidratio",
+		},
+		&cli.Float64Flag{
+			Name:  "sampling-ratio",
+			Usage: "fraction (0-1) of traces sampled by the tracer provider, 1 samples every trace",
+			Value: 1,
+		},
+	}
+}
+
 func generateTraces(c *cli.Context, isSingle bool) error {
-	if c.String("otel-exporter-otlp-endpoint") == "" {
-		return errors.New("'otel-exporter-otlp-endpoint' must be set")
+	output, err := parseOutput(c)
+	if err != nil {
+		return err
+	}
+
+	if err := requireNonSyslogOutput(output); err != nil {
+		return err
+	}
+
+	if err := requireNonRemoteWriteOutput(output); err != nil {
+		return err
+	}
+
+	if c.Int("span-count") < 1 {
+		return errors.New("'span-count' must be greater than or equal to 1")
+	}
+
+	if skew := time.Duration(c.Int("clock-skew")) * time.Millisecond; skew < time.Millisecond || skew > scenarios.MaxClockSkew {
+		return fmt.Errorf("'clock-skew' must be between 1 and %d milliseconds", scenarios.MaxClockSkew.Milliseconds())
+	}
+
+	spanKind, err := scenarios.ParseSpanKind(c.String("span-kind"))
+	if err != nil {
+		return err
+	}
+
+	linkMode, err := scenarios.ParseLinkMode(c.String("link-mode"))
+	if err != nil {
+		return err
+	}
+
+	if c.Float64("span-rate") < 0 {
+		return errors.New("'span-rate' must be greater than or equal to 0")
+	}
+
+	if c.Int("scenario-retries") < 0 {
+		return errors.New("'scenario-retries' must be greater than or equal to 0")
+	}
+
+	if c.Int("scenario-backoff") < 0 {
+		return errors.New("'scenario-backoff' must be greater than or equal to 0")
+	}
+
+	if c.Int("max-export-errors") < 0 {
+		return errors.New("'max-export-errors' must be greater than or equal to 0")
+	}
+
+	if c.Int("span-attribute-count") < 0 {
+		return errors.New("'span-attribute-count' must be greater than or equal to 0")
+	}
+
+	bag, err := parseBaggage(c.StringSlice("baggage"))
+	if err != nil {
+		return err
+	}
+
+	sampler, err := newSampler(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Int("max-trace-depth") < 1 {
+		return errors.New("'max-trace-depth' must be greater than or equal to 1")
 	}
 
 	tracesCfg := &traces.Config{
-		Endpoint:    c.String("otel-exporter-otlp-endpoint"),
-		ServiceName: c.String("service-name"),
-		Insecure:    c.Bool("insecure"),
-		UseHTTP:     c.String("protocol") == "http",
+		Endpoint:              c.String("otel-exporter-otlp-endpoint"),
+		ServiceName:           serviceName(c),
+		ServiceVersion:        c.String("service-version"),
+		ServiceInstanceID:     serviceInstanceID(c),
+		DetectResources:       c.Bool("detect-resources"),
+		ResourceFromEnv:       c.Bool("resource-detector-env"),
+		ResourceFromContainer: c.Bool("resource-detector-container"),
+		MaxExportErrors:       c.Int("max-export-errors"),
+		ExportStats:           c.Bool("export-stats"),
+		SyncExport:            c.Bool("sync-export"),
+		Discard:               output == outputDiscard,
+		Socket:                socketOutput(output),
+		Kafka:                 kafkaOutput(output),
+		TeeStdout:             c.Bool("tee-stdout"),
+		Pretty:                c.Bool("pretty"),
+		Insecure:              c.Bool("insecure"),
+		UseHTTP:               c.String("protocol") == "http",
+		SpanCount:             c.Int("span-count"),
+		MaxTraceDepth:         c.Int("max-trace-depth"),
+		ClockSkew:             time.Duration(c.Int("clock-skew")) * time.Millisecond,
+		StatusMessage:         c.String("status-message"),
+		SpanKind:              spanKind,
+		LinkMode:              linkMode,
+		SpanAttributeCount:    c.Int("span-attribute-count"),
+		SpanRate:              c.Float64("span-rate"),
+		Baggage:               bag,
+		BaggageToAttributes:   c.Bool("baggage-to-attributes"),
+		Chaos:                 c.Bool("chaos"),
+		ChaosSeed:             chaosSeed(c),
+		NamePrefix:            c.String("name-prefix"),
+		NameSuffix:            c.String("name-suffix"),
+		ScenarioRetries:       c.Int("scenario-retries"),
+		ScenarioBackoff:       time.Duration(c.Int("scenario-backoff")) * time.Millisecond,
+		IDGenerator:           idgen.NewCrypto(),
+	}
+
+	tracesCfg.ShutdownTimeout, err = parseShutdownTimeout(c)
+	if err != nil {
+		return err
+	}
+
+	tracesCfg.StartTimeOffset, err = parseStartTimeOffset(c)
+	if err != nil {
+		return err
+	}
+
+	tracesCfg.FlushInterval, err = parseFlushInterval(c)
+	if err != nil {
+		return err
+	}
+
+	tracesCfg.PayloadSize, err = parsePayloadSize(c)
+	if err != nil {
+		return err
+	}
+
+	tracesCfg.OutputBuffer, err = parseOutputBuffer(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("deterministic-ids") {
+		tracesCfg.IDGenerator = idgen.NewSeeded(c.Int64("seed"))
 	}
 
 	if isSingle {
@@ -102,88 +309,226 @@ func generateTraces(c *cli.Context, isSingle bool) error {
 		tracesCfg.PropagateContext = c.Bool("marshal")
 	} else {
 		tracesCfg.TotalDuration = time.Duration(c.Int("duration") * int(time.Second))
-		tracesCfg.Rate = c.Int64("rate")
+		tracesCfg.ProgressInterval = time.Duration(c.Int("progress-interval") * int(time.Second))
+		tracesCfg.Rate, err = parseRate(c)
+		if err != nil {
+			return err
+		}
+		rateProfile, err := parseRateProfile(c)
+		if err != nil {
+			return err
+		}
+		tracesCfg.RateProfile = rateProfile
 		tracesCfg.NumTraces = c.Int("number-traces")
 		tracesCfg.WorkerCount = c.Int("workers")
 		tracesCfg.Scenarios = c.StringSlice("scenarios")
 		tracesCfg.PropagateContext = c.Bool("marshal")
 	}
 
+	tracesCfg.Window, err = parseWindow(c, tracesCfg.TotalDuration)
+	if err != nil {
+		return err
+	}
+
 	if c.String("log-level") == "debug" {
 		grpcZap.ReplaceGrpcLoggerV2(logger.WithOptions(
 			zap.AddCallerSkip(3),
 		))
 	}
 
-	grpcExpOpt := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(tracesCfg.Endpoint),
-		otlptracegrpc.WithDialOption(
-			grpc.WithBlock(),
-		),
+	headers, err := parseHeaders(c)
+	if err != nil {
+		return err
 	}
+	tracesCfg.Headers = headers
 
-	httpExpOpt := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(tracesCfg.Endpoint),
+	tracesCfg.Endpoint, tracesCfg.Insecure, tracesCfg.URLPath, err = parseEndpoint(c, tracesCfg.Insecure)
+	if err != nil {
+		return err
 	}
 
-	if tracesCfg.Insecure {
-		grpcExpOpt = append(grpcExpOpt, otlptracegrpc.WithInsecure())
-		httpExpOpt = append(httpExpOpt, otlptracehttp.WithInsecure())
+	if err := printConfig(c, tracesCfg); err != nil {
+		return err
 	}
 
-	if len(c.StringSlice("header")) > 0 {
-		headers := make(map[string]string)
-		for _, h := range c.StringSlice("header") {
-			kv := strings.SplitN(h, "=", 2)
-			if len(kv) != 2 {
-				return fmt.Errorf("value should be of the format key=value")
-			}
-			headers[kv[0]] = kv[1]
+	var exp sdktrace.SpanExporter
+	if tracesCfg.Discard {
+		logger.Info("discarding spans instead of exporting them")
+		exp = discard.NewSpanExporter(logger)
+	} else if tracesCfg.Socket != "" {
+		logger.Info("streaming spans to a socket", zap.String("output", tracesCfg.Socket))
+		w, err := socket.NewWriter(tracesCfg.Socket, logger)
+		if err != nil {
+			return err
 		}
-		grpcExpOpt = append(grpcExpOpt, otlptracegrpc.WithHeaders(headers))
-		httpExpOpt = append(httpExpOpt, otlptracehttp.WithHeaders(headers))
-		tracesCfg.Headers = headers
-	}
-
-	var exp *otlptrace.Exporter
-	var err error
-	if tracesCfg.UseHTTP {
-		logger.Info("starting HTTP exporter")
-		exp, err = otlptracehttp.New(context.Background(), httpExpOpt...)
+		exp = socket.NewSpanExporter(w)
+	} else if tracesCfg.Kafka != "" {
+		logger.Info("producing spans to kafka", zap.String("output", tracesCfg.Kafka))
+		w, err := kafka.NewWriter(tracesCfg.Kafka, logger)
+		if err != nil {
+			return err
+		}
+		exp = socket.NewSpanExporter(w)
 	} else {
-		logger.Info("starting gRPC exporter")
-		exp, err = otlptracegrpc.New(context.Background(), grpcExpOpt...)
+		exp, err = createTraceExporter(context.Background(), tracesCfg)
+		if err != nil {
+			logger.Error("failed to obtain OTLP exporter", zap.Error(err))
+			return err
+		}
 	}
-
-	if err != nil {
-		logger.Error("failed to obtain OTLP exporter", zap.Error(err))
-		return err
+	if tracesCfg.TeeStdout {
+		exp = tee.SpanExporter(exp, socket.NewSpanExporter(socket.Stdout(tracesCfg.Pretty, tracesCfg.OutputBuffer)))
 	}
 	defer func() {
 		logger.Info("stopping the exporter")
-		if err = exp.Shutdown(context.Background()); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), tracesCfg.ShutdownTimeout)
+		defer cancel()
+		if err = exp.Shutdown(ctx); err != nil {
 			logger.Error("failed to stop the exporter", zap.Error(err))
 		}
 	}()
 
-	ssp := sdktrace.NewBatchSpanProcessor(exp, sdktrace.WithBatchTimeout(time.Second))
+	spanExp := timeshift.SpanExporter(exp, timeshift.New(tracesCfg.StartTimeOffset, tracesCfg.Window))
+	if tracesCfg.Chaos {
+		spanExp = chaos.New(tracesCfg.ChaosSeed).SpanExporter(spanExp)
+	}
+	spanExp = newExportGuard(c, tracesCfg.MaxExportErrors).SpanExporter(spanExp)
+	spanExp = newExportStats("traces", tracesCfg.ExportStats).SpanExporter(spanExp)
+
+	ssp := tracesCfg.SpanProcessor(spanExp)
 	defer func() {
-		logger.Info("stop the batch span processor")
-		if err := ssp.Shutdown(context.Background()); err != nil {
-			logger.Error("failed to stop the batch span processor", zap.Error(err))
+		logger.Info("stop the span processor")
+		ctx, cancel := context.WithTimeout(context.Background(), tracesCfg.ShutdownTimeout)
+		defer cancel()
+		if err := ssp.Shutdown(ctx); err != nil {
+			logger.Error("failed to stop the span processor", zap.Error(err))
 		}
 	}()
 
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(tracesCfg.ServiceName))),
-		sdktrace.WithSpanProcessor(ssp),
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(tracesCfg.ServiceName),
+		semconv.ServiceVersionKey.String(tracesCfg.ServiceVersion),
+		semconv.ServiceInstanceIDKey.String(tracesCfg.ServiceInstanceID),
 	)
+	if tracesCfg.DetectResources {
+		res, err = resourcedetect.Merge(context.Background(), res)
+		if err != nil {
+			return err
+		}
+	}
+	if tracesCfg.ResourceFromContainer {
+		res, err = resourcedetect.MergeContainer(context.Background(), res)
+		if err != nil {
+			return err
+		}
+	}
+	if tracesCfg.ResourceFromEnv {
+		res, err = resourcedetect.MergeEnv(context.Background(), res)
+		if err != nil {
+			return err
+		}
+	}
+
+	tpOpts := append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	}, tracesCfg.TracerProviderOptions()...)
+	tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(ssp))
+
+	tracerProvider := sdktrace.NewTracerProvider(tpOpts...)
+	stopFlush := flush.Start(logger, tracesCfg.FlushInterval, tracerProvider.ForceFlush)
+	defer stopFlush()
 
 	otel.SetTracerProvider(tracerProvider)
 
-	if err := traces.Run(tracesCfg, logger); err != nil {
-		logger.Error("failed to run traces", zap.Error(err))
+	return runCycles(c, func() error {
+		if err := traces.Run(c.Context, tracesCfg, logger); err != nil {
+			logger.Error("failed to run traces", zap.Error(err))
+		}
+		return nil
+	}, tracerProvider.ForceFlush)
+}
+
+// newSampler returns the sampler selected on the command line. The parentbased
+// samplers follow the decision of a sampled or unsampled parent, including a remote
+// one propagated with --marshal, and only apply their root sampler to new traces.
+func newSampler(c *cli.Context) (sdktrace.Sampler, error) {
+	ratio := c.Float64("sampling-ratio")
+	if ratio < 0 || ratio > 1 {
+		return nil, fmt.Errorf("'sampling-ratio' must be between 0 and 1, got %v", ratio)
+	}
+	ratioSampler := sdktrace.TraceIDRatioBased(ratio)
+	if ratio == 1 {
+		ratioSampler = sdktrace.AlwaysSample()
+	}
+
+	switch s := c.String("sampler"); s {
+	case "always_on":
+		return sdktrace.AlwaysSample(), nil
+	case "always_off":
+		return sdktrace.NeverSample(), nil
+	case "traceidratio":
+		return ratioSampler, nil
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(ratioSampler), nil
+	default:
+		return nil, fmt.Errorf("unsupported sampler: %s, use one of: always_on, always_off, traceidratio, parentbased_always_on, parentbased_always_off, parentbased_traceidratio", s)
 	}
+}
+
+// parseBaggage returns the baggage holding the given key=value members
+func parseBaggage(members []string) (baggage.Baggage, error) {
+	parsed := make([]baggage.Member, 0, len(members))
+	for _, m := range members {
+		kv := strings.SplitN(m, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return baggage.Baggage{}, fmt.Errorf("invalid baggage member %q, use the format key=value", m)
+		}
+		member, err := baggage.NewMemberRaw(kv[0], kv[1])
+		if err != nil {
+			return baggage.Baggage{}, fmt.Errorf("invalid baggage member %q: %w", m, err)
+		}
+		parsed = append(parsed, member)
+	}
+	return baggage.New(parsed...)
+}
 
-	return nil
+// createTraceExporter creates a new OTLP trace exporter based on the traces config
+func createTraceExporter(ctx context.Context, tracesCfg *traces.Config) (*otlptrace.Exporter, error) {
+	grpcExpOpt := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(tracesCfg.Endpoint),
+		otlptracegrpc.WithDialOption(
+			grpc.WithBlock(),
+		),
+	}
+
+	httpExpOpt := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(tracesCfg.Endpoint),
+	}
+
+	if tracesCfg.Insecure {
+		grpcExpOpt = append(grpcExpOpt, otlptracegrpc.WithInsecure())
+		httpExpOpt = append(httpExpOpt, otlptracehttp.WithInsecure())
+	}
+
+	if tracesCfg.URLPath != "" {
+		httpExpOpt = append(httpExpOpt, otlptracehttp.WithURLPath(tracesCfg.URLPath))
+	}
+
+	if len(tracesCfg.Headers) > 0 {
+		grpcExpOpt = append(grpcExpOpt, otlptracegrpc.WithHeaders(tracesCfg.Headers))
+		httpExpOpt = append(httpExpOpt, otlptracehttp.WithHeaders(tracesCfg.Headers))
+	}
+
+	if tracesCfg.UseHTTP {
+		logger.Info("starting HTTP exporter")
+		return otlptracehttp.New(ctx, httpExpOpt...)
+	}
+	logger.Info("starting gRPC exporter")
+	return otlptracegrpc.New(ctx, grpcExpOpt...)
 }
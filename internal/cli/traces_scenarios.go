@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/krzko/otelgen/internal/traces"
+	"github.com/urfave/cli/v2"
+)
+
+// genScenariosCommand documents the trace scenarios, so backend queries can be
+// written against the spans and attributes they emit
+func genScenariosCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "scenarios",
+		Usage: "list the trace scenarios and describe what they emit",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "list the trace scenarios with a summary of each",
+				Action: func(c *cli.Context) error {
+					for _, name := range scenarioNames() {
+						fmt.Fprintf(c.App.Writer, "%-15s %s\n", name, traces.Descriptions[name].Summary)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "describe",
+				Usage:     "describe the span tree and key attributes of a scenario",
+				ArgsUsage: "<scenario>",
+				Action: func(c *cli.Context) error {
+					name := c.Args().First()
+					d, ok := traces.Descriptions[name]
+					if !ok {
+						return fmt.Errorf("unknown scenario: %q, use one of: %v", name, scenarioNames())
+					}
+					fmt.Fprintf(c.App.Writer, "scenario: %s\n%s", name, d)
+					fmt.Fprintf(c.App.Writer, "\nEach trace is wrapped in a span named %q by the traces command.\n", name)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// scenarioNames returns the names of the documented scenarios in order
+func scenarioNames() []string {
+	names := make([]string, 0, len(traces.Descriptions))
+	for name := range traces.Descriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestScenariosCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "list", args: []string{"list"}, want: scenarioNames()},
+		{name: "describe basic", args: []string{"describe", "basic"}, want: []string{"scenario: basic", "ping", "pong"}},
+		{name: "describe fan_out", args: []string{"describe", "fan_out"}, want: []string{"GET /dashboard", "fetch_widget", "widget.index"}},
+		{name: "describe unknown", args: []string{"describe", "nope"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			app := &cli.App{Writer: &out, Commands: []*cli.Command{genScenariosCommand()}}
+			err := app.Run(append([]string{"otelgen", "scenarios"}, tt.args...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output doesn't contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordedSpans starts spans root spans, under parent when it's valid, with the
+// sampler selected by args and returns how many of them were recorded
+func recordedSpans(t *testing.T, args []string, parent trace.SpanContext, spans int) int {
+	t.Helper()
+	sampler, err := newSampler(newTestContext(t, getScenarioFlags(), args...))
+	if err != nil {
+		t.Fatalf("newSampler() error = %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	ctx := context.Background()
+	if parent.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, parent)
+	}
+	for i := 0; i < spans; i++ {
+		_, span := tp.Tracer("test").Start(ctx, "span")
+		span.End()
+	}
+	return len(recorder.Ended())
+}
+
+func TestSamplingRatio(t *testing.T) {
+	const spans = 100
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "default", want: spans},
+		{name: "ratio 1", args: []string{"--sampling-ratio", "1"}, want: spans},
+		{name: "ratio 0", args: []string{"--sampling-ratio", "0"}, want: 0},
+		{name: "always off", args: []string{"--sampler", "always_off"}, want: 0},
+		{name: "always on ignores the ratio", args: []string{"--sampler", "always_on", "--sampling-ratio", "0"}, want: spans},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recordedSpans(t, tt.args, trace.SpanContext{}, spans); got != tt.want {
+				t.Errorf("recorded %d of %d spans, want %d", got, spans, tt.want)
+			}
+		})
+	}
+
+	for _, args := range [][]string{
+		{"--sampling-ratio", "-0.1"},
+		{"--sampling-ratio", "1.5"},
+		{"--sampler", "sometimes"},
+	} {
+		if _, err := newSampler(newTestContext(t, getScenarioFlags(), args...)); err == nil {
+			t.Errorf("newSampler(%v) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestParentBasedSampler(t *testing.T) {
+	remoteParent := func(flags trace.TraceFlags) trace.SpanContext {
+		return trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{0x01},
+			SpanID:     trace.SpanID{0x02},
+			TraceFlags: flags,
+			Remote:     true,
+		})
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		parent trace.SpanContext
+		want   bool
+	}{
+		{name: "sampled parent overrides ratio 0", args: []string{"--sampler", "parentbased_traceidratio", "--sampling-ratio", "0"}, parent: remoteParent(trace.FlagsSampled), want: true},
+		{name: "unsampled parent overrides ratio 1", args: []string{"--sampler", "parentbased_traceidratio", "--sampling-ratio", "1"}, parent: remoteParent(0), want: false},
+		{name: "root follows ratio 1", args: []string{"--sampler", "parentbased_traceidratio", "--sampling-ratio", "1"}, want: true},
+		{name: "root follows ratio 0", args: []string{"--sampler", "parentbased_traceidratio", "--sampling-ratio", "0"}, want: false},
+		{name: "sampled parent overrides always off", args: []string{"--sampler", "parentbased_always_off"}, parent: remoteParent(trace.FlagsSampled), want: true},
+		{name: "unsampled parent overrides always on", args: []string{"--sampler", "parentbased_always_on"}, parent: remoteParent(0), want: false},
+		{name: "without parentbased the parent is ignored", args: []string{"--sampler", "traceidratio", "--sampling-ratio", "1"}, parent: remoteParent(0), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recordedSpans(t, tt.args, tt.parent, 1) == 1; got != tt.want {
+				t.Errorf("child recorded = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package clock abstracts the passing of time so the simulators can be driven by a
+// fake clock, making their rate, duration and temporality behaviour deterministic.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and schedules ticks
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	// After delivers the time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker delivers ticks at an interval that can be changed after each tick
+type Ticker interface {
+	C() <-chan time.Time
+	Reset(d time.Duration)
+	Stop()
+}
+
+// Real returns the clock backed by the time package
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// Fake is a clock that only moves when advanced, firing the ticks and timers that
+// fall due on the way
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	timers  []*fakeTimer
+}
+
+// NewFake returns a fake clock set to now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for clock.Fake.NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTicker{clock: f, c: make(chan time.Time, 1), interval: d, next: f.now.Add(d)}
+	f.tickers = append(f.tickers, t)
+	return t
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTimer{c: make(chan time.Time, 1), at: f.now.Add(d)}
+	if d <= 0 {
+		t.c <- f.now
+		return t.c
+	}
+	f.timers = append(f.timers, t)
+	return t.c
+}
+
+// Waiters returns how many running tickers and pending timers the clock holds, so a
+// test can wait for the code it drives to schedule them before advancing
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.timers)
+	for _, t := range f.tickers {
+		if !t.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+// Advance moves the clock forward by d, firing every tick and timer that falls due
+// in order. Like time.Ticker, a tick is dropped when the previous one hasn't been
+// read, so tests advance by one interval at a time to observe every tick.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	end := f.now.Add(d)
+	f.mu.Unlock()
+
+	for {
+		f.mu.Lock()
+		at, fire := f.nextEvent(end)
+		if fire == nil {
+			f.now = end
+			f.mu.Unlock()
+			return
+		}
+		f.now = at
+		fire()
+		f.mu.Unlock()
+	}
+}
+
+// nextEvent returns the earliest tick or timer due by end and the function that
+// fires it, or a nil function when nothing is due
+func (f *Fake) nextEvent(end time.Time) (time.Time, func()) {
+	var at time.Time
+	var fire func()
+	for _, t := range f.tickers {
+		if t.stopped || t.next.After(end) || (fire != nil && !t.next.Before(at)) {
+			continue
+		}
+		t := t
+		at = t.next
+		fire = func() {
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.interval)
+		}
+	}
+	for i, t := range f.timers {
+		if t.at.After(end) || (fire != nil && !t.at.Before(at)) {
+			continue
+		}
+		i, t := i, t
+		at = t.at
+		fire = func() {
+			t.c <- t.at
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+		}
+	}
+	return at, fire
+}
+
+type fakeTicker struct {
+	clock    *Fake
+	c        chan time.Time
+	interval time.Duration
+	next     time.Time
+	stopped  bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for clock.Fake ticker Reset")
+	}
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.interval = d
+	t.next = t.clock.now.Add(d)
+	t.stopped = false
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
+
+type fakeTimer struct {
+	c  chan time.Time
+	at time.Time
+}
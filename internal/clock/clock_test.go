@@ -0,0 +1,155 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ticks drains the ticks waiting on c
+func ticks(c <-chan time.Time) []time.Time {
+	var got []time.Time
+	for {
+		select {
+		case t := <-c:
+			got = append(got, t)
+		default:
+			return got
+		}
+	}
+}
+
+func TestFakeNow(t *testing.T) {
+	f := NewFake(epoch)
+	f.Advance(90 * time.Second)
+	if got, want := f.Now(), epoch.Add(90*time.Second); !got.Equal(want) {
+		t.Fatalf("Now() = %s, want %s", got, want)
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance []time.Duration
+		want    []time.Duration
+	}{
+		{"before the first tick", []time.Duration{999 * time.Millisecond}, nil},
+		{"on the first tick", []time.Duration{time.Second}, []time.Duration{time.Second}},
+		{"one interval at a time", []time.Duration{time.Second, time.Second, time.Second}, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}},
+		{"unread ticks are dropped", []time.Duration{3 * time.Second}, []time.Duration{time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFake(epoch)
+			tk := f.NewTicker(time.Second)
+			var got []time.Time
+			for _, d := range tt.advance {
+				f.Advance(d)
+				got = append(got, ticks(tk.C())...)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d ticks %v, want %d", len(got), got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !got[i].Equal(epoch.Add(w)) {
+					t.Errorf("tick %d at %s, want %s", i, got[i], epoch.Add(w))
+				}
+			}
+		})
+	}
+}
+
+func TestFakeTickerResetAndStop(t *testing.T) {
+	f := NewFake(epoch)
+	tk := f.NewTicker(time.Second)
+
+	f.Advance(500 * time.Millisecond)
+	tk.Reset(2 * time.Second)
+	f.Advance(time.Second)
+	if got := ticks(tk.C()); len(got) != 0 {
+		t.Fatalf("ticked at %v before the reset interval elapsed", got)
+	}
+	f.Advance(time.Second)
+	if got := ticks(tk.C()); len(got) != 1 || !got[0].Equal(epoch.Add(2500*time.Millisecond)) {
+		t.Fatalf("got ticks %v, want one at 2.5s", got)
+	}
+
+	tk.Stop()
+	if n := f.Waiters(); n != 0 {
+		t.Fatalf("Waiters() = %d after Stop, want 0", n)
+	}
+	f.Advance(10 * time.Second)
+	if got := ticks(tk.C()); len(got) != 0 {
+		t.Fatalf("stopped ticker ticked at %v", got)
+	}
+}
+
+func TestFakeAfter(t *testing.T) {
+	f := NewFake(epoch)
+	c := f.After(time.Minute)
+	if n := f.Waiters(); n != 1 {
+		t.Fatalf("Waiters() = %d, want 1", n)
+	}
+
+	f.Advance(59 * time.Second)
+	if got := ticks(c); len(got) != 0 {
+		t.Fatalf("fired early at %v", got)
+	}
+	f.Advance(time.Second)
+	if got := ticks(c); len(got) != 1 || !got[0].Equal(epoch.Add(time.Minute)) {
+		t.Fatalf("got %v, want a single fire at 1m", got)
+	}
+	if n := f.Waiters(); n != 0 {
+		t.Fatalf("Waiters() = %d after firing, want 0", n)
+	}
+
+	if got := ticks(f.After(0)); len(got) != 1 {
+		t.Fatalf("After(0) fired %d times, want straight away", len(got))
+	}
+}
+
+func TestFakeFiresInOrder(t *testing.T) {
+	f := NewFake(epoch)
+	tk := f.NewTicker(2 * time.Second)
+	timer := f.After(3 * time.Second)
+
+	// Reading as each event fires shows the order they fall due in
+	var order []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for len(order) < 3 {
+			select {
+			case <-tk.C():
+				order = append(order, "tick")
+			case <-timer:
+				order = append(order, "timer")
+			}
+		}
+	}()
+	for i := 0; i < 4; i++ {
+		f.Advance(time.Second)
+		waitFor(t, func() bool { return len(tk.C()) == 0 && len(timer) == 0 })
+	}
+	<-done
+
+	want := []string{"tick", "timer", "tick"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("fired in order %v, want %v", order, want)
+		}
+	}
+}
+
+// waitFor polls cond until it holds, failing the test after a second
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,152 @@
+// Package discard provides exporters that drop telemetry after counting it, isolating
+// the cost of generation from serialisation and network overhead.
+package discard
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// counter tallies the items handed to a discarding exporter
+type counter struct {
+	signal string
+	logger *zap.Logger
+	items  atomic.Int64
+	once   sync.Once
+}
+
+// Count returns the number of items discarded so far
+func (c *counter) Count() int64 {
+	return c.items.Load()
+}
+
+// summarise logs the count once, however many times the exporter is shut down
+func (c *counter) summarise() {
+	c.once.Do(func() {
+		c.logger.Info("discarded telemetry",
+			zap.String("signal", c.signal),
+			zap.Int64("items_discarded", c.items.Load()),
+		)
+	})
+}
+
+// SpanExporter counts and drops every span it is given
+type SpanExporter struct {
+	counter
+}
+
+var _ sdktrace.SpanExporter = (*SpanExporter)(nil)
+
+// NewSpanExporter returns a span exporter that counts spans instead of exporting them
+func NewSpanExporter(logger *zap.Logger) *SpanExporter {
+	return &SpanExporter{counter: counter{signal: "traces", logger: logger}}
+}
+
+func (e *SpanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.items.Add(int64(len(spans)))
+	return nil
+}
+
+func (e *SpanExporter) Shutdown(context.Context) error {
+	e.summarise()
+	return nil
+}
+
+// MetricExporter counts and drops every data point it is given
+type MetricExporter struct {
+	counter
+	temporality sdkmetric.TemporalitySelector
+}
+
+var _ sdkmetric.Exporter = (*MetricExporter)(nil)
+
+// NewMetricExporter returns a metric exporter that counts data points instead of
+// exporting them, reporting the temporality chosen by temporality
+func NewMetricExporter(temporality sdkmetric.TemporalitySelector, logger *zap.Logger) *MetricExporter {
+	return &MetricExporter{counter: counter{signal: "metrics", logger: logger}, temporality: temporality}
+}
+
+func (e *MetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return e.temporality(kind)
+}
+
+func (e *MetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *MetricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	var points int
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			points += dataPoints(m.Data)
+		}
+	}
+	e.items.Add(int64(points))
+	return nil
+}
+
+func (e *MetricExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *MetricExporter) Shutdown(context.Context) error {
+	e.summarise()
+	return nil
+}
+
+// dataPoints returns the number of data points held by an aggregation
+func dataPoints(data metricdata.Aggregation) int {
+	switch d := data.(type) {
+	case metricdata.Gauge[int64]:
+		return len(d.DataPoints)
+	case metricdata.Gauge[float64]:
+		return len(d.DataPoints)
+	case metricdata.Sum[int64]:
+		return len(d.DataPoints)
+	case metricdata.Sum[float64]:
+		return len(d.DataPoints)
+	case metricdata.Histogram[int64]:
+		return len(d.DataPoints)
+	case metricdata.Histogram[float64]:
+		return len(d.DataPoints)
+	case metricdata.ExponentialHistogram[int64]:
+		return len(d.DataPoints)
+	case metricdata.ExponentialHistogram[float64]:
+		return len(d.DataPoints)
+	default:
+		return 0
+	}
+}
+
+// LogExporter counts and drops every log record it is given
+type LogExporter struct {
+	counter
+}
+
+var _ sdklog.Exporter = (*LogExporter)(nil)
+
+// NewLogExporter returns a log exporter that counts records instead of exporting them
+func NewLogExporter(logger *zap.Logger) *LogExporter {
+	return &LogExporter{counter: counter{signal: "logs", logger: logger}}
+}
+
+func (e *LogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.items.Add(int64(len(records)))
+	return nil
+}
+
+func (e *LogExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *LogExporter) Shutdown(context.Context) error {
+	e.summarise()
+	return nil
+}
@@ -0,0 +1,104 @@
+package discard
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestExportersCountGeneratedItems(t *testing.T) {
+	const items = 7
+
+	tests := []struct {
+		name string
+		// generate makes items spans, data points or log records with an exporter
+		// logging to logger, shuts it down and returns its count
+		generate func(t *testing.T, logger *zap.Logger) int64
+	}{
+		{
+			name: "traces",
+			generate: func(t *testing.T, logger *zap.Logger) int64 {
+				exp := NewSpanExporter(logger)
+				tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp))
+				for i := 0; i < items; i++ {
+					_, span := tp.Tracer("test").Start(context.Background(), "span")
+					span.End()
+				}
+				if err := tp.Shutdown(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				return exp.Count()
+			},
+		},
+		{
+			name: "metrics",
+			generate: func(t *testing.T, logger *zap.Logger) int64 {
+				exp := NewMetricExporter(sdkmetric.DefaultTemporalitySelector, logger)
+				mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp)))
+				meter := mp.Meter("test")
+				counter, err := meter.Int64Counter("test.counter")
+				if err != nil {
+					t.Fatal(err)
+				}
+				histogram, err := meter.Float64Histogram("test.histogram")
+				if err != nil {
+					t.Fatal(err)
+				}
+				// One data point per attribute set, split across two instruments
+				for i := 0; i < items; i++ {
+					attrs := metric.WithAttributes(attribute.Int("series", i))
+					if i%2 == 0 {
+						counter.Add(context.Background(), 1, attrs)
+					} else {
+						histogram.Record(context.Background(), 1, attrs)
+					}
+				}
+				if err := mp.Shutdown(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				return exp.Count()
+			},
+		},
+		{
+			name: "logs",
+			generate: func(t *testing.T, logger *zap.Logger) int64 {
+				exp := NewLogExporter(logger)
+				lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exp)))
+				for i := 0; i < items; i++ {
+					var r log.Record
+					r.SetBody(log.IntValue(i))
+					lp.Logger("test").Emit(context.Background(), r)
+				}
+				if err := lp.Shutdown(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				return exp.Count()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			if got := tt.generate(t, zap.New(core)); got != items {
+				t.Errorf("Count() = %d, want %d", got, items)
+			}
+
+			summaries := logs.FilterMessage("discarded telemetry").All()
+			if len(summaries) != 1 {
+				t.Fatalf("got %d summaries, want 1", len(summaries))
+			}
+			if fields := summaries[0].ContextMap(); fields["signal"] != tt.name || fields["items_discarded"] != int64(items) {
+				t.Errorf("summary = %v, want %d %s items discarded", fields, items, tt.name)
+			}
+		})
+	}
+}
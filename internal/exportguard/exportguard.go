@@ -0,0 +1,113 @@
+package exportguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// ErrAborted is the cause of a run cancelled by its guard
+var ErrAborted = errors.New("aborted after consecutive export failures")
+
+// Guard counts consecutive export failures and aborts the run once they reach a limit
+type Guard struct {
+	max         int64
+	consecutive atomic.Int64
+	cancel      context.CancelCauseFunc
+	logger      *zap.Logger
+}
+
+// New returns a guard that aborts the run after max consecutive failures by calling
+// cancel with an ErrAborted cause, 0 disables it
+func New(max int, cancel context.CancelCauseFunc, logger *zap.Logger) *Guard {
+	return &Guard{max: int64(max), cancel: cancel, logger: logger}
+}
+
+// Err returns the error a guard aborted the run of ctx with, or nil when it wasn't
+func Err(ctx context.Context) error {
+	if err := context.Cause(ctx); errors.Is(err, ErrAborted) {
+		return err
+	}
+	return nil
+}
+
+// observe records the outcome of an export, resetting the count on success
+func (g *Guard) observe(signal string, err error) {
+	if err == nil {
+		g.consecutive.Store(0)
+		return
+	}
+	n := g.consecutive.Add(1)
+	if g.max > 0 && n == g.max {
+		g.logger.Error("aborting after consecutive export failures",
+			zap.String("signal", signal),
+			zap.Int64("failures", n),
+			zap.Error(err),
+		)
+		g.cancel(fmt.Errorf("%w: %d %s exports failed in a row: %v", ErrAborted, n, signal, err))
+	}
+}
+
+type spanExporter struct {
+	sdktrace.SpanExporter
+	guard *Guard
+}
+
+// SpanExporter wraps exp so its export failures count towards the guard
+func (g *Guard) SpanExporter(exp sdktrace.SpanExporter) sdktrace.SpanExporter {
+	if g.max <= 0 {
+		return exp
+	}
+	return &spanExporter{SpanExporter: exp, guard: g}
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.guard.observe("traces", err)
+	return err
+}
+
+type metricExporter struct {
+	sdkmetric.Exporter
+	guard *Guard
+}
+
+// MetricExporter wraps exp so its export failures count towards the guard
+func (g *Guard) MetricExporter(exp sdkmetric.Exporter) sdkmetric.Exporter {
+	if g.max <= 0 {
+		return exp
+	}
+	return &metricExporter{Exporter: exp, guard: g}
+}
+
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.guard.observe("metrics", err)
+	return err
+}
+
+type logExporter struct {
+	sdklog.Exporter
+	guard *Guard
+}
+
+// LogExporter wraps exp so its export failures count towards the guard
+func (g *Guard) LogExporter(exp sdklog.Exporter) sdklog.Exporter {
+	if g.max <= 0 {
+		return exp
+	}
+	return &logExporter{Exporter: exp, guard: g}
+}
+
+func (e *logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.guard.observe("logs", err)
+	return err
+}
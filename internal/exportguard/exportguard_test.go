@@ -0,0 +1,78 @@
+package exportguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+var errExport = errors.New("collector unavailable")
+
+// failingExporter fails the exports whose outcome in fails is true, and every
+// export past the end of fails
+type failingExporter struct {
+	sdktrace.SpanExporter
+	fails []bool
+	calls int
+}
+
+func (e *failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	e.calls++
+	if e.calls > len(e.fails) || e.fails[e.calls-1] {
+		return errExport
+	}
+	return nil
+}
+
+func TestGuardAbortsAfterConsecutiveFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		fails   []bool
+		exports int
+		// wantAbortAt is the export the run is aborted after, 0 when it isn't
+		wantAbortAt int
+	}{
+		{name: "always failing", max: 3, exports: 5, wantAbortAt: 3},
+		{name: "aborts on the first failure", max: 1, exports: 2, wantAbortAt: 1},
+		{name: "success resets the count", max: 3, fails: []bool{true, true, false, true, true, false}, exports: 6},
+		{name: "failures after a success", max: 2, fails: []bool{true, false, true}, exports: 5, wantAbortAt: 4},
+		{name: "disabled", max: 0, exports: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+			mock := &failingExporter{fails: tt.fails}
+			exp := New(tt.max, cancel, zap.NewNop()).SpanExporter(mock)
+
+			abortedAt := 0
+			for i := 1; i <= tt.exports; i++ {
+				_ = exp.ExportSpans(ctx, nil)
+				if abortedAt == 0 && Err(ctx) != nil {
+					abortedAt = i
+				}
+			}
+			if abortedAt != tt.wantAbortAt {
+				t.Errorf("aborted after export %d, want %d", abortedAt, tt.wantAbortAt)
+			}
+			if mock.calls != tt.exports {
+				t.Errorf("got %d exports, want %d", mock.calls, tt.exports)
+			}
+			if err := Err(ctx); tt.wantAbortAt > 0 && !errors.Is(err, ErrAborted) {
+				t.Errorf("Err() = %v, want %v", err, ErrAborted)
+			}
+		})
+	}
+
+	t.Run("cancelled for another reason", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(context.Canceled)
+		if err := Err(ctx); err != nil {
+			t.Errorf("Err() = %v, want nil", err)
+		}
+	})
+}
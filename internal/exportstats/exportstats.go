@@ -0,0 +1,152 @@
+package exportstats
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// Counts are the export outcomes of a signal
+type Counts struct {
+	Succeeded int64
+	Failed    int64
+	Items     int64
+}
+
+// Stats counts the outcome of every export made by the exporters it wraps
+type Stats struct {
+	signal    string
+	logger    *zap.Logger
+	onError   func(error)
+	succeeded atomic.Int64
+	failed    atomic.Int64
+	items     atomic.Int64
+	once      sync.Once
+}
+
+// New returns the stats of signal's exports. When logger is set they are logged once
+// a wrapped exporter shuts down.
+func New(signal string, logger *zap.Logger) *Stats {
+	return &Stats{signal: signal, logger: logger}
+}
+
+// OnError passes the error of every failed export to fn
+func (s *Stats) OnError(fn func(error)) *Stats {
+	s.onError = fn
+	return s
+}
+
+// Signal returns the signal the stats count the exports of
+func (s *Stats) Signal() string {
+	return s.signal
+}
+
+// Counts returns the export outcomes counted so far
+func (s *Stats) Counts() Counts {
+	return Counts{
+		Succeeded: s.succeeded.Load(),
+		Failed:    s.failed.Load(),
+		Items:     s.items.Load(),
+	}
+}
+
+func (s *Stats) record(items int, err error) {
+	if err != nil {
+		s.failed.Add(1)
+		if s.onError != nil {
+			s.onError(err)
+		}
+		return
+	}
+	s.succeeded.Add(1)
+	s.items.Add(int64(items))
+}
+
+// summarise logs the counts once, however many times the exporter is shut down
+func (s *Stats) summarise() {
+	if s.logger == nil {
+		return
+	}
+	s.once.Do(func() {
+		s.logger.Info("export summary",
+			zap.String("signal", s.signal),
+			zap.Int64("exports_succeeded", s.succeeded.Load()),
+			zap.Int64("exports_failed", s.failed.Load()),
+			zap.Int64("items_exported", s.items.Load()),
+		)
+	})
+}
+
+type spanExporter struct {
+	sdktrace.SpanExporter
+	stats *Stats
+}
+
+// SpanExporter wraps exp to count its exports
+func (s *Stats) SpanExporter(exp sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return &spanExporter{SpanExporter: exp, stats: s}
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.stats.record(len(spans), err)
+	return err
+}
+
+func (e *spanExporter) Shutdown(ctx context.Context) error {
+	e.stats.summarise()
+	return e.SpanExporter.Shutdown(ctx)
+}
+
+type metricExporter struct {
+	sdkmetric.Exporter
+	stats *Stats
+}
+
+// MetricExporter wraps exp to count its exports. Each metric stream in an export
+// counts as one item.
+func (s *Stats) MetricExporter(exp sdkmetric.Exporter) sdkmetric.Exporter {
+	return &metricExporter{Exporter: exp, stats: s}
+}
+
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	var items int
+	for _, sm := range rm.ScopeMetrics {
+		items += len(sm.Metrics)
+	}
+	e.stats.record(items, err)
+	return err
+}
+
+func (e *metricExporter) Shutdown(ctx context.Context) error {
+	e.stats.summarise()
+	return e.Exporter.Shutdown(ctx)
+}
+
+type logExporter struct {
+	sdklog.Exporter
+	stats *Stats
+}
+
+// LogExporter wraps exp to count its exports
+func (s *Stats) LogExporter(exp sdklog.Exporter) sdklog.Exporter {
+	return &logExporter{Exporter: exp, stats: s}
+}
+
+func (e *logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.stats.record(len(records), err)
+	return err
+}
+
+func (e *logExporter) Shutdown(ctx context.Context) error {
+	e.stats.summarise()
+	return e.Exporter.Shutdown(ctx)
+}
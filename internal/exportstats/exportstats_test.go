@@ -0,0 +1,96 @@
+package exportstats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var errExport = errors.New("collector unavailable")
+
+// mockExporter fails every export while failing is set
+type mockExporter struct {
+	sdklog.Exporter
+	failing   bool
+	shutdowns int
+}
+
+func (e *mockExporter) Export(context.Context, []sdklog.Record) error {
+	if e.failing {
+		return errExport
+	}
+	return nil
+}
+
+func (e *mockExporter) Shutdown(context.Context) error {
+	e.shutdowns++
+	return nil
+}
+
+func TestStatsCountExports(t *testing.T) {
+	type export struct {
+		records int
+		fail    bool
+	}
+	tests := []struct {
+		name    string
+		exports []export
+		want    Counts
+	}{
+		{name: "none"},
+		{name: "succeeded", exports: []export{{records: 3}, {records: 5}}, want: Counts{Succeeded: 2, Items: 8}},
+		{name: "failed", exports: []export{{records: 3, fail: true}}, want: Counts{Failed: 1}},
+		{
+			name:    "mixed",
+			exports: []export{{records: 2}, {records: 4, fail: true}, {records: 1}, {records: 6, fail: true}},
+			want:    Counts{Succeeded: 2, Failed: 2, Items: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			var errs []error
+			stats := New("logs", zap.New(core)).OnError(func(err error) { errs = append(errs, err) })
+			mock := &mockExporter{}
+			exp := stats.LogExporter(mock)
+
+			for _, e := range tt.exports {
+				mock.failing = e.fail
+				err := exp.Export(context.Background(), make([]sdklog.Record, e.records))
+				if e.fail != errors.Is(err, errExport) {
+					t.Errorf("Export() error = %v, want the mock's error returned as is", err)
+				}
+			}
+			if got := stats.Counts(); got != tt.want {
+				t.Errorf("Counts() = %+v, want %+v", got, tt.want)
+			}
+			if int64(len(errs)) != tt.want.Failed {
+				t.Errorf("OnError was called %d times, want %d", len(errs), tt.want.Failed)
+			}
+
+			// The summary is logged once however often the exporter is shut down
+			for i := 0; i < 2; i++ {
+				if err := exp.Shutdown(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if mock.shutdowns != 2 {
+				t.Errorf("the wrapped exporter was shut down %d times, want 2", mock.shutdowns)
+			}
+			summaries := logs.FilterMessage("export summary").All()
+			if len(summaries) != 1 {
+				t.Fatalf("got %d export summaries, want 1", len(summaries))
+			}
+			fields := summaries[0].ContextMap()
+			if fields["signal"] != "logs" || fields["exports_succeeded"] != tt.want.Succeeded ||
+				fields["exports_failed"] != tt.want.Failed || fields["items_exported"] != tt.want.Items {
+				t.Errorf("export summary = %v, want the counts %+v", fields, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package flush
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Start calls flush every interval, pushing buffered telemetry out without waiting
+// for the batch timeout, until the returned stop function is called. stop waits for
+// a flush in progress so none follows it. Each flush is bounded by the interval so a
+// slow exporter can't stack up calls. An interval of 0 disables flushing.
+func Start(logger *zap.Logger, interval time.Duration, flush func(context.Context) error) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := flush(ctx); err != nil {
+					logger.Warn("failed to flush telemetry", zap.Error(err))
+				}
+				cancel()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
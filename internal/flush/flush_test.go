@@ -0,0 +1,96 @@
+package flush
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// mockExporter counts the flushes reaching it through a meter provider
+type mockExporter struct {
+	sdkmetric.Exporter
+	flushes atomic.Int64
+}
+
+func (e *mockExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(kind)
+}
+
+func (e *mockExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *mockExporter) Export(context.Context, *metricdata.ResourceMetrics) error { return nil }
+
+func (e *mockExporter) ForceFlush(context.Context) error {
+	e.flushes.Add(1)
+	return nil
+}
+
+func (e *mockExporter) Shutdown(context.Context) error { return nil }
+
+func TestStart(t *testing.T) {
+	const window = 500 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		min, max int64
+	}{
+		{"disabled", 0, 0, 0},
+		{"every 100ms", 100 * time.Millisecond, 3, 5},
+		{"every 50ms", 50 * time.Millisecond, 7, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := &mockExporter{}
+			// The reader's own interval is far longer than the test
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(time.Hour))))
+			defer func() { _ = mp.Shutdown(context.Background()) }()
+
+			stop := Start(zap.NewNop(), tt.interval, mp.ForceFlush)
+			time.Sleep(window)
+			stop()
+			got := exp.flushes.Load()
+			if got < tt.min || got > tt.max {
+				t.Errorf("got %d flushes in %s, want between %d and %d", got, window, tt.min, tt.max)
+			}
+
+			// No flushes follow stop
+			time.Sleep(2 * tt.interval)
+			if after := exp.flushes.Load(); after != got {
+				t.Errorf("got %d flushes after stopping, want none", after-got)
+			}
+		})
+	}
+}
+
+func TestStartLogsFailures(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	failed := make(chan struct{}, 1)
+	stop := Start(zap.New(core), 10*time.Millisecond, func(context.Context) error {
+		select {
+		case failed <- struct{}{}:
+		default:
+		}
+		return errors.New("collector unavailable")
+	})
+	<-failed
+	stop()
+
+	deadline := time.Now().Add(time.Second)
+	for logs.FilterMessage("failed to flush telemetry").Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the failed flush wasn't logged")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
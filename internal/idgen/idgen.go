@@ -0,0 +1,103 @@
+package idgen
+
+import (
+	"context"
+	crand "crypto/rand"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// cryptoGenerator draws trace and span IDs from crypto/rand
+type cryptoGenerator struct{}
+
+// NewCrypto returns the default IDGenerator, backed by crypto/rand
+func NewCrypto() sdktrace.IDGenerator {
+	return cryptoGenerator{}
+}
+
+func (cryptoGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var tid trace.TraceID
+	for !tid.IsValid() {
+		_, _ = crand.Read(tid[:])
+	}
+	return tid, cryptoSpanID()
+}
+
+func (cryptoGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	return cryptoSpanID()
+}
+
+func cryptoSpanID() trace.SpanID {
+	var sid trace.SpanID
+	for !sid.IsValid() {
+		_, _ = crand.Read(sid[:])
+	}
+	return sid
+}
+
+// seeded derives trace and span IDs from a seeded pseudo-random source, so runs
+// with the same seed and a single worker emit the same IDs
+type seeded struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewSeeded returns an IDGenerator whose IDs are derived from seed
+func NewSeeded(seed int64) sdktrace.IDGenerator {
+	return &seeded{r: rand.New(rand.NewSource(seed))}
+}
+
+func (g *seeded) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var tid trace.TraceID
+	for !tid.IsValid() {
+		g.r.Read(tid[:])
+	}
+	return tid, g.spanID()
+}
+
+func (g *seeded) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.spanID()
+}
+
+// spanID returns the next valid span ID, callers must hold mu
+func (g *seeded) spanID() trace.SpanID {
+	var sid trace.SpanID
+	for !sid.IsValid() {
+		g.r.Read(sid[:])
+	}
+	return sid
+}
+
+// pool reuses a fixed set of trace IDs round-robin, so many requests share a trace
+type pool struct {
+	base     sdktrace.IDGenerator
+	traceIDs []trace.TraceID
+	next     atomic.Uint64
+}
+
+// NewPool returns an IDGenerator that hands out size trace IDs drawn from base
+// round-robin, while span IDs still come from base
+func NewPool(base sdktrace.IDGenerator, size int) sdktrace.IDGenerator {
+	p := &pool{base: base, traceIDs: make([]trace.TraceID, size)}
+	for i := range p.traceIDs {
+		p.traceIDs[i], _ = base.NewIDs(context.Background())
+	}
+	return p
+}
+
+func (p *pool) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	tid := p.traceIDs[(p.next.Add(1)-1)%uint64(len(p.traceIDs))]
+	return tid, p.base.NewSpanID(ctx, tid)
+}
+
+func (p *pool) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	return p.base.NewSpanID(ctx, traceID)
+}
@@ -0,0 +1,91 @@
+package idgen
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// emittedIDs returns the trace and span IDs of the spans of a few nested traces
+// started on a tracer provider using ids
+func emittedIDs(t *testing.T, ids sdktrace.IDGenerator) []trace.SpanContext {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithIDGenerator(ids), sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	tracer := tp.Tracer("test")
+	for i := 0; i < 3; i++ {
+		ctx, root := tracer.Start(context.Background(), "root")
+		_, child := tracer.Start(ctx, "child")
+		child.End()
+		root.End()
+	}
+
+	var got []trace.SpanContext
+	for _, s := range recorder.Ended() {
+		if !s.SpanContext().IsValid() {
+			t.Errorf("%s has an invalid span context %v", s.Name(), s.SpanContext())
+		}
+		got = append(got, s.SpanContext())
+	}
+	return got
+}
+
+func equalIDs(a, b []trace.SpanContext) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].TraceID() != b[i].TraceID() || a[i].SpanID() != b[i].SpanID() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSeededIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      sdktrace.IDGenerator
+		wantEqual bool
+	}{
+		{"same seed", NewSeeded(42), NewSeeded(42), true},
+		{"different seeds", NewSeeded(42), NewSeeded(43), false},
+		{"crypto", NewCrypto(), NewCrypto(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := emittedIDs(t, tt.a), emittedIDs(t, tt.b)
+			if got := equalIDs(a, b); got != tt.wantEqual {
+				t.Errorf("the runs emitted identical IDs = %t, want %t\n%v\n%v", got, tt.wantEqual, a, b)
+			}
+		})
+	}
+}
+
+func TestPool(t *testing.T) {
+	for _, size := range []int{1, 2, 5} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			ids := NewPool(NewSeeded(1), size)
+			traces := map[trace.TraceID]bool{}
+			spans := map[trace.SpanID]bool{}
+			const draws = 20
+			for i := 0; i < draws; i++ {
+				tid, sid := ids.NewIDs(context.Background())
+				traces[tid] = true
+				spans[sid] = true
+			}
+			if len(traces) != size {
+				t.Errorf("drew %d distinct trace IDs, want the %d in the pool", len(traces), size)
+			}
+			if len(spans) != draws {
+				t.Errorf("drew %d distinct span IDs in %d draws, want a fresh one each time", len(spans), draws)
+			}
+		})
+	}
+}
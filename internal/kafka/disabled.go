@@ -0,0 +1,21 @@
+//go:build !kafka
+
+package kafka
+
+import (
+	"errors"
+
+	"github.com/krzko/otelgen/internal/socket"
+	"go.uber.org/zap"
+)
+
+// Enabled reports whether otelgen was built with the Kafka producer
+const Enabled = false
+
+// NewWriter fails, as otelgen was built without the kafka tag
+func NewWriter(output string, _ *zap.Logger) (socket.Sink, error) {
+	if _, _, err := ParseAddress(output); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("kafka output isn't supported by this build, add github.com/segmentio/kafka-go with go get and rebuild otelgen with -tags kafka")
+}
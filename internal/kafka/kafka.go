@@ -0,0 +1,97 @@
+// Package kafka produces telemetry to a Kafka topic as ndjson records, one message
+// per span, metric data point or log record. The producer is only compiled in with
+// the kafka build tag, so default builds don't carry the Kafka client. The client
+// isn't required by go.mod either and has to be added with go get before building
+// with the tag.
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/krzko/otelgen/internal/socket"
+)
+
+// writeTimeout bounds how long producing a batch may take when the context of the
+// export has no deadline
+const writeTimeout = 10 * time.Second
+
+// producer sends messages to the topic it was created for, the Kafka client in
+// builds with the kafka tag
+type producer interface {
+	Produce(ctx context.Context, values [][]byte) error
+	Close() error
+}
+
+// Writer produces every frame it's given as a message on the topic. It's safe for
+// concurrent use and shared by the signal exporters.
+type Writer struct {
+	p     producer
+	topic string
+}
+
+var _ socket.Sink = (*Writer)(nil)
+
+func newWriter(p producer, topic string) *Writer {
+	return &Writer{p: p, topic: topic}
+}
+
+// WriteFrames produces each frame as a message, without its trailing newline
+func (w *Writer) WriteFrames(ctx context.Context, frames [][]byte) error {
+	if len(frames) == 0 {
+		return nil
+	}
+	values := make([][]byte, len(frames))
+	for i, f := range frames {
+		values[i] = bytes.TrimSuffix(f, []byte("\n"))
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, writeTimeout)
+		defer cancel()
+	}
+	if err := w.p.Produce(ctx, values); err != nil {
+		return fmt.Errorf("failed to produce to kafka topic %s: %w", w.topic, err)
+	}
+	return nil
+}
+
+// Close flushes pending messages and closes the connections to the brokers
+func (w *Writer) Close() error {
+	return w.p.Close()
+}
+
+// ParseAddress splits a kafka://broker[,broker...]/topic output into the brokers to
+// bootstrap from and the topic messages are produced to
+func ParseAddress(output string) (brokers []string, topic string, err error) {
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid kafka output %q: %w", output, err)
+	}
+	if u.Scheme != "kafka" {
+		return nil, "", fmt.Errorf("unsupported kafka scheme: %s, use kafka", u.Scheme)
+	}
+	topic = strings.Trim(u.Path, "/")
+	if u.Host == "" || topic == "" || strings.Contains(topic, "/") {
+		return nil, "", fmt.Errorf("kafka output %q must be of the form kafka://host:port/topic", output)
+	}
+	for _, b := range strings.Split(u.Host, ",") {
+		if b == "" {
+			return nil, "", fmt.Errorf("kafka output %q has an empty broker", output)
+		}
+		if !strings.Contains(b, ":") {
+			b += ":9092"
+		}
+		brokers = append(brokers, b)
+	}
+	return brokers, topic, nil
+}
+
+// IsOutput reports whether output names a Kafka topic
+func IsOutput(output string) bool {
+	return strings.HasPrefix(output, "kafka://")
+}
@@ -0,0 +1,186 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/socket"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// fakeProducer keeps the messages produced to it, failing every batch with err
+type fakeProducer struct {
+	err error
+
+	mu       sync.Mutex
+	messages [][]byte
+	deadline bool
+	closed   bool
+}
+
+func (p *fakeProducer) Produce(ctx context.Context, values [][]byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, p.deadline = ctx.Deadline()
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, values...)
+	return nil
+}
+
+func (p *fakeProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestWriterFramesMessages(t *testing.T) {
+	tests := []struct {
+		name   string
+		export func(w socket.Sink) error
+		// want is the value of each message's signal field
+		want []string
+	}{
+		{
+			name: "spans",
+			export: func(w socket.Sink) error {
+				tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(socket.NewSpanExporter(w)))
+				for _, name := range []string{"first", "second"} {
+					_, span := tp.Tracer("test").Start(context.Background(), name)
+					span.End()
+				}
+				return tp.Shutdown(context.Background())
+			},
+			want: []string{"span", "span"},
+		},
+		{
+			name: "metrics",
+			export: func(w socket.Sink) error {
+				rm := &metricdata.ResourceMetrics{
+					Resource: resource.Empty(),
+					ScopeMetrics: []metricdata.ScopeMetrics{{
+						Metrics: []metricdata.Metrics{{
+							Name: "otelgen.gauge",
+							Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{
+								{Time: time.Unix(0, 0), Value: 1},
+								{Time: time.Unix(1, 0), Value: 2},
+								{Time: time.Unix(2, 0), Value: 3},
+							}},
+						}},
+					}},
+				}
+				return socket.NewMetricExporter(w, sdkmetric.DefaultTemporalitySelector).Export(context.Background(), rm)
+			},
+			want: []string{"metric", "metric", "metric"},
+		},
+		{
+			name: "logs",
+			export: func(w socket.Sink) error {
+				lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(socket.NewLogExporter(w))))
+				var r log.Record
+				r.SetBody(log.StringValue("otelgen.log"))
+				lp.Logger("test").Emit(context.Background(), r)
+				return lp.Shutdown(context.Background())
+			},
+			want: []string{"log"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &fakeProducer{}
+			if err := tt.export(newWriter(p, "otel")); err != nil {
+				t.Fatalf("export error = %v", err)
+			}
+
+			var got []string
+			for _, m := range p.messages {
+				if strings.ContainsAny(string(m), "\n") {
+					t.Errorf("message %q isn't a single compact line", m)
+				}
+				var line struct {
+					Signal string `json:"signal"`
+				}
+				if err := json.Unmarshal(m, &line); err != nil {
+					t.Fatalf("message %q isn't JSON: %v", m, err)
+				}
+				got = append(got, line.Signal)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("produced %q, want one message per item %q", got, tt.want)
+			}
+			if !p.deadline {
+				t.Error("produced without a deadline")
+			}
+		})
+	}
+}
+
+func TestWriterReportsTopic(t *testing.T) {
+	errBroker := errors.New("broker unavailable")
+	p := &fakeProducer{err: errBroker}
+	w := newWriter(p, "otel")
+
+	err := w.WriteFrames(context.Background(), [][]byte{[]byte("{}\n")})
+	if !errors.Is(err, errBroker) || !strings.Contains(err.Error(), "otel") {
+		t.Errorf("WriteFrames() error = %v, want the broker's error naming the topic", err)
+	}
+	if err := w.WriteFrames(context.Background(), nil); err != nil {
+		t.Errorf("WriteFrames() with no frames error = %v", err)
+	}
+	if err := w.Close(); err != nil || !p.closed {
+		t.Errorf("Close() error = %v, closed the producer = %t", err, p.closed)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		output      string
+		wantBrokers []string
+		wantTopic   string
+		wantErr     bool
+	}{
+		{output: "kafka://localhost:9092/otel", wantBrokers: []string{"localhost:9092"}, wantTopic: "otel"},
+		{output: "kafka://a,b:9093/otel/", wantBrokers: []string{"a:9092", "b:9093"}, wantTopic: "otel"},
+		{output: "kafka://localhost:9092", wantErr: true},
+		{output: "kafka://localhost:9092/a/b", wantErr: true},
+		{output: "kafka://a,,b/otel", wantErr: true},
+		{output: "tcp://localhost:9092/otel", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			brokers, topic, err := ParseAddress(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(brokers, tt.wantBrokers) || topic != tt.wantTopic {
+				t.Errorf("ParseAddress() = %v, %q, want %v, %q", brokers, topic, tt.wantBrokers, tt.wantTopic)
+			}
+		})
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	if _, err := NewWriter("kafka://localhost:9092", nil); err == nil {
+		t.Error("NewWriter() accepted an output without a topic")
+	}
+	w, err := NewWriter("kafka://localhost:9092/otel", nil)
+	if Enabled {
+		if err != nil {
+			t.Fatalf("NewWriter() error = %v", err)
+		}
+		_ = w.Close()
+	} else if err == nil || !strings.Contains(err.Error(), "-tags kafka") {
+		t.Errorf("NewWriter() error = %v, want the tagged build explained", err)
+	}
+}
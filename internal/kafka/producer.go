@@ -0,0 +1,52 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/krzko/otelgen/internal/socket"
+	kafkago "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// Enabled reports whether otelgen was built with the Kafka producer
+const Enabled = true
+
+// clientProducer produces messages with the kafka-go client
+type clientProducer struct {
+	w *kafkago.Writer
+}
+
+func (p *clientProducer) Produce(ctx context.Context, values [][]byte) error {
+	msgs := make([]kafkago.Message, len(values))
+	for i, v := range values {
+		msgs[i] = kafkago.Message{Value: v}
+	}
+	return p.w.WriteMessages(ctx, msgs...)
+}
+
+func (p *clientProducer) Close() error {
+	return p.w.Close()
+}
+
+// NewWriter returns a writer for the kafka:// output. Brokers are dialled lazily, on
+// the first write.
+func NewWriter(output string, _ *zap.Logger) (socket.Sink, error) {
+	brokers, topic, err := ParseAddress(output)
+	if err != nil {
+		return nil, err
+	}
+	return newWriter(&clientProducer{
+		w: &kafkago.Writer{
+			Addr:         kafkago.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafkago.LeastBytes{},
+			RequiredAcks: kafkago.RequireOne,
+			// Exports are already batched, so each one is produced as it arrives
+			BatchTimeout: time.Millisecond,
+			WriteTimeout: writeTimeout,
+		},
+	}, topic), nil
+}
@@ -5,22 +5,115 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/krzko/otelgen/internal/rateprofile"
+	"github.com/krzko/otelgen/internal/summary"
+	"github.com/krzko/otelgen/internal/timeshift"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type Config struct {
-	WorkerCount   int
-	NumLogs       int
-	Rate          float64
-	TotalDuration time.Duration
-	ServiceName   string
+	WorkerCount       int
+	NumLogs           int
+	Rate              float64
+	RateProfile       rateprofile.Profile
+	TotalDuration     time.Duration
+	ProgressInterval  time.Duration
+	ServiceName       string
+	ServiceVersion    string
+	ServiceInstanceID string
+
+	DetectResources bool
+	ResourceFromEnv bool
+	// ResourceFromContainer merges the detected container.id into the resource
+	ResourceFromContainer bool
+	BodyFormat            string
+
+	// MaxExportErrors aborts the run after this many consecutive export failures, 0 disables it
+	MaxExportErrors int
+	// ExportStats logs a summary of export outcomes on shutdown
+	ExportStats bool
+	// Summary, when set, tracks the stats of every exporter for the run summary
+	Summary *summary.Summary
+	// FlushInterval is how often buffered telemetry is force flushed, 0 disables it
+	FlushInterval time.Duration
+	// ShutdownTimeout bounds how long exporters and providers may take to drain on shutdown
+	ShutdownTimeout time.Duration
+	// Discard counts telemetry in place of exporting it
+	Discard bool
+	// Socket, when set, streams telemetry as ndjson to this tcp:// or udp:// address in place of exporting it
+	Socket string
+	// Kafka, when set, produces telemetry as ndjson to this kafka://host:port/topic in place of exporting it
+	Kafka string
+	// TeeStdout additionally prints every exported item to stdout as ndjson
+	TeeStdout bool
+	// Pretty indents the JSON TeeStdout prints, which is compact ndjson otherwise
+	Pretty bool
+	// OutputBuffer is the size in bytes of the buffer TeeStdout batches its writes in, 0 disables it
+	OutputBuffer int
+	// Syslog, when set, sends records as RFC 5424 messages to this syslog:// or syslog+tcp:// receiver
+	Syslog string
+	// StartTimeOffset shifts the timestamps of exported telemetry this far into the past
+	StartTimeOffset time.Duration
+	// Window, when set, replays the run's telemetry evenly across a historical time range
+	Window *timeshift.Window
+	// SyncExport exports every record as it ends instead of batching them
+	SyncExport bool
+
+	// EmitSpans records a real trace per request, with a span per phase that the
+	// phase's records reference, exported to the same output as the records
+	EmitSpans bool
+
+	// IDGenerator supplies the trace and span IDs logs are correlated with,
+	// crypto/rand is used when nil
+	IDGenerator sdktrace.IDGenerator
+
+	// Attributes are added to every log record
+	Attributes []attribute.KeyValue
+	// NoDefaultAttributes omits the default k8s attributes from each log record
+	NoDefaultAttributes bool
+	// K8SNamespace, K8SPod and K8SContainer are reported in the k8s resource and record
+	// attributes, a pod name is generated when K8SPod is empty
+	K8SNamespace string
+	K8SPod       string
+	K8SContainer string
+	// RandomizePodName generates a new pod name for every record instead of one per worker
+	RandomizePodName bool
+	// PayloadSize is the size in bytes of a synthetic attribute added to every log record, 0 disables it
+	PayloadSize int
+	// MinBodySize pads the message of every log body to at least this many bytes, 0 disables it
+	MinBodySize int
+	// MinSeverity is the lowest severity records are given, every level is used when unset
+	MinSeverity log.Severity
 
 	// OTLP config
 	Endpoint string
 	Insecure bool
 	UseHTTP  bool
 	Headers  HeaderValue
+	// URLPath overrides the default signal path used by the HTTP exporter
+	URLPath string
 }
 
+const (
+	// BodyFormatString emits the log body as a formatted string
+	BodyFormatString = "string"
+	// BodyFormatStructured emits the log body as a map of fields
+	BodyFormatStructured = "structured"
+)
+
+// BodyPaddingMarker separates a log message from the filler padding it to MinBodySize
+const BodyPaddingMarker = " [otelgen.padding] "
+
+const (
+	// DefaultK8SNamespace is the k8s.namespace.name reported when none is configured
+	DefaultK8SNamespace = "default"
+	// DefaultK8SContainer is the k8s.container.name reported when none is configured
+	DefaultK8SContainer = "otelgen"
+)
+
 type HeaderValue map[string]string
 
 var _ flag.Value = (*HeaderValue)(nil)
@@ -0,0 +1,51 @@
+package logs
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestConfigProcessors(t *testing.T) {
+	tests := []struct {
+		name     string
+		sync     bool
+		wantSync bool
+	}{
+		{"sync export", true, true},
+		{"batched", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{SyncExport: tt.sync}
+
+			logExp := &memoryExporter{}
+			lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(c.logProcessor(logExp)))
+			defer func() { _ = lp.Shutdown(context.Background()) }()
+			var r log.Record
+			r.SetBody(log.StringValue("request"))
+			lp.Logger("test").Emit(context.Background(), r)
+
+			spanExp := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(c.spanProcessor(spanExp)))
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+			_, span := tp.Tracer("test").Start(context.Background(), "span")
+			span.End()
+
+			// A simple processor has exported the record and span by the time Emit and End return
+			logExp.mu.Lock()
+			exportedLogs := len(logExp.records)
+			logExp.mu.Unlock()
+			if got := exportedLogs == 1; got != tt.wantSync {
+				t.Errorf("record exported as it was emitted = %t, want %t", got, tt.wantSync)
+			}
+			if got := len(spanExp.GetSpans()) == 1; got != tt.wantSync {
+				t.Errorf("span exported as it ended = %t, want %t", got, tt.wantSync)
+			}
+		})
+	}
+}
@@ -6,10 +6,26 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/krzko/otelgen/internal/discard"
+	"github.com/krzko/otelgen/internal/exportguard"
+	"github.com/krzko/otelgen/internal/exportstats"
+	"github.com/krzko/otelgen/internal/flush"
+	"github.com/krzko/otelgen/internal/idgen"
+	"github.com/krzko/otelgen/internal/kafka"
+	"github.com/krzko/otelgen/internal/payload"
+	"github.com/krzko/otelgen/internal/progress"
+	"github.com/krzko/otelgen/internal/resourcedetect"
+	"github.com/krzko/otelgen/internal/socket"
+	"github.com/krzko/otelgen/internal/syslog"
+	"github.com/krzko/otelgen/internal/tee"
+	"github.com/krzko/otelgen/internal/timeshift"
+
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/log"
@@ -21,33 +37,51 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// Run initialises log generation based on the provided configuration.
-func Run(c *Config, logger *zap.Logger) error {
+// Run initialises log generation based on the provided configuration, generating
+// until ctx is cancelled or the configured number or duration is reached. It returns
+// an exportguard.ErrAborted error when consecutive export failures abort the run.
+func Run(ctx context.Context, c *Config, logger *zap.Logger) error {
 	logger.Debug("Log generation config", zap.Any("Config", c))
 
-	if c.NumLogs == 0 && c.TotalDuration == 0 {
-		// Log without using zap.Error, which logs stack traces
-		logger.Warn("No log number or duration specified. Log generation will continue indefinitely.")
-	}
-
-	// Configure rate limiter
-	limit := rate.Limit(c.Rate)
-	if c.Rate == 0 {
-		limit = rate.Inf
-		logger.Info("Generation of logs isn't being throttled")
+	// Create OTLP exporter, or one that only counts records when discarding them
+	var exporter sdklog.Exporter
+	var err error
+	if c.Discard {
+		logger.Info("Discarding logs instead of exporting them")
+		exporter = discard.NewLogExporter(logger)
+	} else if c.Syslog != "" {
+		logger.Info("Sending logs to syslog", zap.String("output", c.Syslog))
+		exporter, err = syslog.NewLogExporter(c.Syslog, logger)
+		if err != nil {
+			return err
+		}
+	} else if c.Socket != "" {
+		logger.Info("Streaming logs to a socket", zap.String("output", c.Socket))
+		w, err := socket.NewWriter(c.Socket, logger)
+		if err != nil {
+			return err
+		}
+		exporter = socket.NewLogExporter(w)
+	} else if c.Kafka != "" {
+		logger.Info("Producing logs to Kafka", zap.String("output", c.Kafka))
+		w, err := kafka.NewWriter(c.Kafka, logger)
+		if err != nil {
+			return err
+		}
+		exporter = socket.NewLogExporter(w)
 	} else {
-		logger.Info("Generation of logs is limited", zap.Float64("per-second", float64(limit)))
+		exporter, err = NewExporter(c)
+		if err != nil {
+			// Log the error as a string without the stack trace
+			logger.Error("Failed to create exporter", zap.String("error", err.Error()))
+			return fmt.Errorf("failed to create exporter: %w", err)
+		}
 	}
-
-	// Create OTLP exporter
-	exporter, err := createExporter(c)
-	if err != nil {
-		// Log the error as a string without the stack trace
-		logger.Error("Failed to create exporter", zap.String("error", err.Error()))
-		return fmt.Errorf("failed to create exporter: %w", err)
+	if c.TeeStdout {
+		exporter = tee.LogExporter(exporter, socket.NewLogExporter(socket.Stdout(c.Pretty, c.OutputBuffer)))
 	}
 	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		ctx, cancel := context.WithTimeout(context.Background(), c.ShutdownTimeout)
 		defer cancel()
 		if err := exporter.Shutdown(ctx); err != nil {
 			// Log the error as a string without the stack trace
@@ -55,37 +89,104 @@ func Run(c *Config, logger *zap.Logger) error {
 		}
 	}()
 
-	// Define resource attributes
-	res := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceNameKey.String(c.ServiceName),
-		semconv.K8SNamespaceNameKey.String("default"),
-		semconv.K8SContainerNameKey.String("otelgen"),
-		semconv.K8SPodNameKey.String(generatePodName()),
-		semconv.HostNameKey.String("node-1"),
-	)
+	res, err := newResource(c, logger)
+	if err != nil {
+		return err
+	}
 	logger.Debug("Resource attributes set", zap.String("Resource", res.String()))
 
-	// Set up a BatchProcessor and pass it to the LoggerProvider
-	batchProcessor := sdklog.NewBatchProcessor(exporter,
-		sdklog.WithMaxQueueSize(2048),
-		sdklog.WithExportMaxBatchSize(512),
-		sdklog.WithExportInterval(1*time.Second),
-	)
+	// Set up a BatchProcessor, or a SimpleProcessor when exporting synchronously,
+	// and pass it to the LoggerProvider
+	logExp := timeshift.LogExporter(exporter, timeshift.New(c.StartTimeOffset, c.Window))
+	ctx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+	guard := exportguard.New(c.MaxExportErrors, abort, logger)
+	logExp = guard.LogExporter(logExp)
+	logExp = c.exportStats("logs", logger).LogExporter(logExp)
 
-	// Initialise LoggerProvider with BatchProcessor and Resource
+	// Initialise LoggerProvider with the processor and Resource
 	loggerProvider := sdklog.NewLoggerProvider(
-		sdklog.WithProcessor(batchProcessor),
+		sdklog.WithProcessor(c.logProcessor(logExp)),
 		sdklog.WithResource(res),
 	)
 	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		ctx, cancel := context.WithTimeout(context.Background(), c.ShutdownTimeout)
 		defer cancel()
 		if err := loggerProvider.Shutdown(ctx); err != nil {
 			// Log the error as a string without the stack trace
 			logger.Error("Failed to shutdown logger provider", zap.String("error", err.Error()))
 		}
 	}()
+	stopFlush := flush.Start(logger, c.FlushInterval, loggerProvider.ForceFlush)
+	defer stopFlush()
+
+	g := NewGenerator(loggerProvider, c, logger)
+	if c.EmitSpans {
+		tracerProvider, err := newTracerProvider(c, res, guard, logger)
+		if err != nil {
+			logger.Error("Failed to create tracer provider", zap.String("error", err.Error()))
+			return err
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), c.ShutdownTimeout)
+			defer cancel()
+			if err := tracerProvider.Shutdown(ctx); err != nil {
+				logger.Error("Failed to shutdown tracer provider", zap.String("error", err.Error()))
+			}
+		}()
+		g.WithTracerProvider(tracerProvider)
+	}
+
+	if err := g.Run(ctx); err != nil {
+		return err
+	}
+	return exportguard.Err(ctx)
+}
+
+// Generator emits log records on a logger provider built by the caller
+type Generator struct {
+	provider log.LoggerProvider
+	tracers  trace.TracerProvider
+	config   *Config
+	logger   *zap.Logger
+}
+
+// NewGenerator returns a generator that emits log records on lp
+func NewGenerator(lp log.LoggerProvider, c *Config, logger *zap.Logger) *Generator {
+	return &Generator{provider: lp, config: c, logger: logger}
+}
+
+// WithTracerProvider makes the generator start a real trace on tp for every request,
+// with a span per phase that the phase's records reference, in place of the
+// fabricated trace and span IDs
+func (g *Generator) WithTracerProvider(tp trace.TracerProvider) *Generator {
+	g.tracers = tp
+	return g
+}
+
+// Run generates logs until the configured number or duration is reached, or ctx is cancelled
+func (g *Generator) Run(ctx context.Context) error {
+	c, logger := g.config, g.logger
+
+	if c.NumLogs == 0 && c.TotalDuration == 0 {
+		// Log without using zap.Error, which logs stack traces
+		logger.Warn("No log number or duration specified. Log generation will continue indefinitely.")
+	}
+
+	// A single limiter shared by the workers keeps the rate an aggregate across them
+	limit := rate.Limit(c.Rate)
+	if c.Rate == 0 {
+		limit = rate.Inf
+		logger.Info("Generation of logs isn't being throttled")
+	} else {
+		logger.Info("Generation of logs is limited", zap.Float64("per-second", c.Rate))
+	}
+	limiter := rate.NewLimiter(limit, 1)
+	start := time.Now()
+
+	// Cancelling releases the workers queued on the shared limiter once the run ends
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	// Initialise wait group for workers
 	wg := sync.WaitGroup{}
@@ -94,18 +195,29 @@ func Run(c *Config, logger *zap.Logger) error {
 
 	totalLogs := atomic.Int64{}
 
+	stopProgress := progress.Start(logger, "logs", c.ProgressInterval, c.TotalDuration, totalLogs.Load)
+	defer stopProgress()
+
 	logger.Debug("Worker count", zap.Int("WorkerCount", c.WorkerCount))
 
 	for i := 0; i < c.WorkerCount; i++ {
 		wg.Add(1)
 		logger.Debug("Starting worker", zap.Int("Worker", i))
-		go generateLogs(c, loggerProvider, limit, logger.With(zap.Int("worker", i)), &wg, res, running, &totalLogs)
+		var tracer trace.Tracer
+		if g.tracers != nil {
+			tracer = g.tracers.Tracer(c.ServiceName)
+		}
+		go generateLogs(ctx, c, g.provider, tracer, limiter, limit, start, logger.With(zap.Int("worker", i)), &wg, running, &totalLogs)
 	}
 
-	// Handle total duration if specified, otherwise run indefinitely
+	// Handle total duration if specified, otherwise run until cancelled
 	if c.TotalDuration > 0 {
-		time.Sleep(c.TotalDuration)
+		select {
+		case <-time.After(c.TotalDuration):
+		case <-ctx.Done():
+		}
 		running.Store(false)
+		cancel()
 	}
 
 	// Wait for all workers to finish
@@ -116,8 +228,8 @@ func Run(c *Config, logger *zap.Logger) error {
 	return nil
 }
 
-// createExporter initialises the OTLP exporter based on the configuration.
-func createExporter(c *Config) (sdklog.Exporter, error) {
+// NewExporter initialises the OTLP exporter based on the configuration.
+func NewExporter(c *Config) (sdklog.Exporter, error) {
 	ctx := context.Background()
 	var exp sdklog.Exporter
 	var err error
@@ -132,6 +244,9 @@ func createExporter(c *Config) (sdklog.Exporter, error) {
 		if len(c.Headers) > 0 {
 			opts = append(opts, otlploghttp.WithHeaders(c.Headers))
 		}
+		if c.URLPath != "" {
+			opts = append(opts, otlploghttp.WithURLPath(c.URLPath))
+		}
 		exp, err = otlploghttp.New(ctx, opts...)
 	} else {
 		opts := []otlploggrpc.Option{
@@ -153,16 +268,26 @@ func createExporter(c *Config) (sdklog.Exporter, error) {
 	return exp, nil
 }
 
-// generateLogs handles the log generation for a single worker.
-// generateLogs handles the log generation for a single worker.
-func generateLogs(c *Config, loggerProvider *sdklog.LoggerProvider, limit rate.Limit, logger *zap.Logger, wg *sync.WaitGroup, res *resource.Resource, running *atomic.Bool, totalLogs *atomic.Int64) {
+// generateLogs handles the log generation for a single worker, tracing each request
+// on tracer when it's set. limiter paces every worker together, limit being its rate
+// before the profile is applied from start.
+func generateLogs(ctx context.Context, c *Config, loggerProvider log.LoggerProvider, tracer trace.Tracer, limiter *rate.Limiter, limit rate.Limit, start time.Time, logger *zap.Logger, wg *sync.WaitGroup, running *atomic.Bool, totalLogs *atomic.Int64) {
 	defer wg.Done()
 
-	limiter := rate.NewLimiter(limit, 1)
 	otelLogger := loggerProvider.Logger(c.ServiceName)
+	ids := c.IDGenerator
+	if ids == nil {
+		ids = idgen.NewCrypto()
+	}
+	// A worker stands in for one pod, so its records share a pod name
+	podName := c.k8sPod()
+	var padding string
+	if c.PayloadSize > 0 {
+		padding = payload.New(c.PayloadSize)
+	}
 
 	for i := 0; c.NumLogs == 0 || i < c.NumLogs; i++ {
-		if !running.Load() {
+		if !running.Load() || ctx.Err() != nil {
 			break
 		}
 
@@ -171,56 +296,117 @@ func generateLogs(c *Config, loggerProvider *sdklog.LoggerProvider, limit rate.L
 			logger.Debug("Generating log", zap.Int("log_index", i))
 		}
 
-		traceID := generateTraceID()
-		spanID := generateSpanID()
-
 		// Simulate the web request phases: start, processing, finish
 		logPhases := []string{"start", "processing", "finish"}
 		httpMethods := []string{"GET", "POST", "PUT", "DELETE"}
 		httpMethod := httpMethods[cryptoRandIntn(len(httpMethods))]
+		target := fmt.Sprintf("/api/v1/resource/%d", i)
+
+		// A traced request takes its IDs from the tracer provider, which shares the
+		// IDGenerator, so only fabricated IDs are drawn here
+		var traceID trace.TraceID
+		var spanID trace.SpanID
+		requestCtx := context.Background()
+		var requestSpan trace.Span
+		if tracer == nil {
+			traceID, spanID = ids.NewIDs(context.Background())
+		} else {
+			requestCtx, requestSpan = tracer.Start(requestCtx, fmt.Sprintf("%s %s", httpMethod, target),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(httpMethod),
+					semconv.URLPath(target),
+				),
+			)
+		}
 
 		for _, phase := range logPhases {
 			phaseDuration := randomDuration(100, 500)
 
 			// Randomize severity and text
-			severity, severityText := randomSeverity()
+			severity, severityText := randomSeverity(c.MinSeverity)
 
 			record := log.Record{}
 			record.SetTimestamp(time.Now())
 			record.SetObservedTimestamp(time.Now())
 			record.SetSeverity(severity)
 			record.SetSeverityText(severityText)
-			record.SetBody(log.StringValue(fmt.Sprintf("Log %d: %s phase: %s", i, severityText, phase)))
+			statusCode := randomHTTPStatusCode()
+			message := padBody(fmt.Sprintf("Log %d: %s phase: %s", i, severityText, phase), c.MinBodySize)
+			if c.BodyFormat == BodyFormatStructured {
+				record.SetBody(log.MapValue(
+					log.String("message", message),
+					log.String("phase", phase),
+					log.String("method", httpMethod),
+					log.Int("status_code", statusCode),
+				))
+			} else {
+				record.SetBody(log.StringValue(message))
+			}
 
 			attrs := []log.KeyValue{
 				log.String("worker_id", fmt.Sprintf("%d", i)),
 				log.String("service.name", c.ServiceName),
-				log.String("trace_id", traceID.String()),
-				log.String("span_id", spanID.String()),
-				log.String("trace_flags", "01"),
 				log.String("phase", phase),
 				log.String("http.method", httpMethod),
-				log.Int("http.status_code", randomHTTPStatusCode()),
-				log.String("http.target", fmt.Sprintf("/api/v1/resource/%d", i)),
-				log.String("k8s.pod.name", generatePodName()),
-				log.String("k8s.namespace.name", "default"),
-				log.String("k8s.container.name", "otelgen"),
+				log.Int("http.status_code", statusCode),
+				log.String("http.target", target),
+			}
+			if !c.NoDefaultAttributes {
+				attrs = append(attrs,
+					log.String("k8s.pod.name", c.recordPodName(podName)),
+					log.String("k8s.namespace.name", c.k8sNamespace()),
+					log.String("k8s.container.name", c.k8sContainer()),
+				)
+			}
+			for _, kv := range c.Attributes {
+				attrs = append(attrs, logKeyValue(kv))
+			}
+			if padding != "" {
+				attrs = append(attrs, log.String(payload.Key, padding))
 			}
 			record.AddAttributes(attrs...)
 
-			// Emit the log record
-			otelLogger.Emit(context.Background(), record)
+			// Emit the log record within the span context so the exported record
+			// carries the trace id, span id and trace flags
+			if tracer != nil {
+				phaseCtx, phaseSpan := tracer.Start(requestCtx, phase,
+					trace.WithAttributes(attribute.String("phase", phase)),
+				)
+				otelLogger.Emit(phaseCtx, record)
+				time.Sleep(phaseDuration)
+				phaseSpan.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+				phaseSpan.End()
+				continue
+			}
+
+			spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+			})
+			otelLogger.Emit(trace.ContextWithSpanContext(context.Background(), spanCtx), record)
 
 			// Simulate the time spent in each phase
 			time.Sleep(phaseDuration)
 
 			// Generate a new span ID for each phase
-			spanID = generateSpanID()
+			spanID = ids.NewSpanID(context.Background(), traceID)
+		}
+		if requestSpan != nil {
+			requestSpan.End()
 		}
 
 		totalLogs.Add(int64(len(logPhases)))
 
-		if err := limiter.Wait(context.Background()); err != nil {
+		if limit != rate.Inf {
+			limiter.SetLimit(rate.Limit(c.RateProfile.Pace(float64(limit), time.Since(start), c.TotalDuration)))
+		}
+
+		if err := limiter.Wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
 			logger.Error("failed to wait for rate limiter", zap.Error(err))
 			continue
 		}
@@ -229,24 +415,32 @@ func generateLogs(c *Config, loggerProvider *sdklog.LoggerProvider, limit rate.L
 	logger.Debug("Worker completed log generation", zap.Int64("total_logs", totalLogs.Load()))
 }
 
-// generateTraceID generates a new trace ID using crypto/rand.
-func generateTraceID() trace.TraceID {
-	var tid [16]byte
-	_, err := rand.Read(tid[:])
-	if err != nil {
-		panic(fmt.Sprintf("failed to generate trace ID: %v", err))
+// logKeyValue converts an attribute.KeyValue into its log.KeyValue equivalent.
+func logKeyValue(kv attribute.KeyValue) log.KeyValue {
+	key := string(kv.Key)
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		return log.Bool(key, kv.Value.AsBool())
+	case attribute.INT64:
+		return log.Int64(key, kv.Value.AsInt64())
+	case attribute.FLOAT64:
+		return log.Float64(key, kv.Value.AsFloat64())
+	default:
+		return log.String(key, kv.Value.Emit())
 	}
-	return trace.TraceID(tid)
 }
 
-// generateSpanID generates a new span ID using crypto/rand.
-func generateSpanID() trace.SpanID {
-	var sid [8]byte
-	_, err := rand.Read(sid[:])
-	if err != nil {
-		panic(fmt.Sprintf("failed to generate span ID: %v", err))
+// padBody extends message to at least size bytes with BodyPaddingMarker followed by
+// filler, leaving messages that are long enough as they are
+func padBody(message string, size int) string {
+	if len(message) >= size {
+		return message
+	}
+	padded := message + BodyPaddingMarker
+	if len(padded) < size {
+		padded += strings.Repeat("x", size-len(padded))
 	}
-	return trace.SpanID(sid)
+	return padded
 }
 
 // randomDuration generates a random duration between min and max milliseconds using crypto/rand.
@@ -262,6 +456,105 @@ func randomHTTPStatusCode() int {
 	return httpStatusCodes[cryptoRandIntn(len(httpStatusCodes))]
 }
 
+// exportStats returns the stats of signal's exports, counted towards the Summary and
+// logged on shutdown when ExportStats is set.
+func (c *Config) exportStats(signal string, logger *zap.Logger) *exportstats.Stats {
+	if !c.ExportStats {
+		logger = nil
+	}
+	return c.Summary.Track(exportstats.New(signal, logger))
+}
+
+// logProcessor returns the processor exporting records to exp, a SimpleProcessor
+// exporting each record as it's emitted when SyncExport is set and a BatchProcessor otherwise.
+func (c *Config) logProcessor(exp sdklog.Exporter) sdklog.Processor {
+	if c.SyncExport {
+		return sdklog.NewSimpleProcessor(exp)
+	}
+	return sdklog.NewBatchProcessor(exp,
+		sdklog.WithMaxQueueSize(2048),
+		sdklog.WithExportMaxBatchSize(512),
+		sdklog.WithExportInterval(1*time.Second),
+	)
+}
+
+// newResource returns the resource of c's service and k8s workload, merged with
+// whatever detectors c enables
+func newResource(c *Config, logger *zap.Logger) (*resource.Resource, error) {
+	resAttrs := []attribute.KeyValue{
+		semconv.ServiceNameKey.String(c.ServiceName),
+		semconv.ServiceVersionKey.String(c.ServiceVersion),
+		semconv.ServiceInstanceIDKey.String(c.ServiceInstanceID),
+		semconv.K8SNamespaceNameKey.String(c.k8sNamespace()),
+		semconv.K8SContainerNameKey.String(c.k8sContainer()),
+		semconv.K8SPodNameKey.String(c.k8sPod()),
+	}
+	if !c.DetectResources {
+		// Keep a synthetic host name so runs are reproducible
+		resAttrs = append(resAttrs, semconv.HostNameKey.String("node-1"))
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL, resAttrs...)
+	var err error
+	if c.DetectResources {
+		res, err = resourcedetect.Merge(context.Background(), res)
+		if err != nil {
+			// Log the error as a string without the stack trace
+			logger.Error("Failed to detect resource attributes", zap.String("error", err.Error()))
+			return nil, err
+		}
+	}
+	if c.ResourceFromContainer {
+		res, err = resourcedetect.MergeContainer(context.Background(), res)
+		if err != nil {
+			// Log the error as a string without the stack trace
+			logger.Error("Failed to detect container resource attributes", zap.String("error", err.Error()))
+			return nil, err
+		}
+	}
+	if c.ResourceFromEnv {
+		res, err = resourcedetect.MergeEnv(context.Background(), res)
+		if err != nil {
+			// Log the error as a string without the stack trace
+			logger.Error("Failed to read resource attributes from the environment", zap.String("error", err.Error()))
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// k8sNamespace returns the configured namespace, falling back to the default.
+func (c *Config) k8sNamespace() string {
+	if c.K8SNamespace == "" {
+		return DefaultK8SNamespace
+	}
+	return c.K8SNamespace
+}
+
+// k8sContainer returns the configured container name, falling back to the default.
+func (c *Config) k8sContainer() string {
+	if c.K8SContainer == "" {
+		return DefaultK8SContainer
+	}
+	return c.K8SContainer
+}
+
+// k8sPod returns the configured pod name, or a generated one when unset.
+func (c *Config) k8sPod() string {
+	if c.K8SPod == "" {
+		return generatePodName()
+	}
+	return c.K8SPod
+}
+
+// recordPodName returns the pod name of a single record, a fresh one when
+// RandomizePodName is set and no pod is configured, otherwise the worker's.
+func (c *Config) recordPodName(worker string) string {
+	if c.RandomizePodName && c.K8SPod == "" {
+		return generatePodName()
+	}
+	return worker
+}
+
 // generatePodName simulates a unique pod name using crypto/rand.
 func generatePodName() string {
 	podNameSuffix := make([]byte, 4)
@@ -269,20 +562,37 @@ func generatePodName() string {
 	return fmt.Sprintf("otelgen-pod-%s", hex.EncodeToString(podNameSuffix))
 }
 
-// randomSeverity generates a random severity level and text.
-func randomSeverity() (log.Severity, string) {
-	severities := []struct {
-		level log.Severity
-		text  string
-	}{
-		{log.SeverityTrace1, "Trace"},
-		{log.SeverityDebug, "Debug"},
-		{log.SeverityInfo, "Info"},
-		{log.SeverityWarn, "Warn"},
-		{log.SeverityError, "Error"},
-		{log.SeverityFatal, "Fatal"},
+// severities are the levels log records are given, from lowest to highest
+var severities = []struct {
+	level log.Severity
+	text  string
+}{
+	{log.SeverityTrace1, "Trace"},
+	{log.SeverityDebug, "Debug"},
+	{log.SeverityInfo, "Info"},
+	{log.SeverityWarn, "Warn"},
+	{log.SeverityError, "Error"},
+	{log.SeverityFatal, "Fatal"},
+}
+
+// ParseSeverity returns the severity named by level, one of: trace, debug, info,
+// warn, error, fatal
+func ParseSeverity(level string) (log.Severity, error) {
+	for _, s := range severities {
+		if strings.EqualFold(s.text, level) {
+			return s.level, nil
+		}
+	}
+	return log.SeverityUndefined, fmt.Errorf("unsupported severity: %s, use one of: trace, debug, info, warn, error, fatal", level)
+}
+
+// randomSeverity generates a random severity level and text, no lower than floor.
+func randomSeverity(floor log.Severity) (log.Severity, string) {
+	first := 0
+	for first < len(severities)-1 && severities[first].level < floor {
+		first++
 	}
-	randomIdx := cryptoRandIntn(len(severities))
+	randomIdx := first + cryptoRandIntn(len(severities)-first)
 	return severities[randomIdx].level, severities[randomIdx].text
 }
 
@@ -0,0 +1,582 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/idgen"
+	"github.com/krzko/otelgen/internal/payload"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// memoryExporter keeps the log records exported to it
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryExporter) ForceFlush(context.Context) error { return nil }
+
+// emitLogs runs a generator configured by c and returns the records it exported
+func emitLogs(t *testing.T, c *Config, tp trace.TracerProvider) []sdklog.Record {
+	t.Helper()
+	exp := &memoryExporter{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	defer func() { _ = lp.Shutdown(context.Background()) }()
+
+	g := NewGenerator(lp, c, zap.NewNop())
+	if tp != nil {
+		g.WithTracerProvider(tp)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	return exp.records
+}
+
+// recordAttributes returns the attributes of r by key
+func recordAttributes(r sdklog.Record) map[string]log.Value {
+	attrs := map[string]log.Value{}
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestGeneratorSharesRateAcrossWorkers(t *testing.T) {
+	const (
+		workers  = 4
+		perSec   = 1
+		duration = 4 * time.Second
+		phases   = 3
+	)
+
+	core, logs := observer.New(zap.InfoLevel)
+	c := &Config{
+		WorkerCount:   workers,
+		Rate:          perSec,
+		TotalDuration: duration,
+		ServiceName:   "test",
+	}
+	if err := NewGenerator(noop.NewLoggerProvider(), c, zap.New(core)).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	completed := logs.FilterMessage("Log generation completed").All()
+	if len(completed) != 1 {
+		t.Fatalf("got %d completion logs, want 1", len(completed))
+	}
+	requests := completed[0].ContextMap()["total_logs"].(int64) / phases
+
+	// Every worker starts a request straight away, then the shared limiter allows its
+	// burst and rate a second, where a limiter per worker would allow four times that
+	limit := int64(workers + 1 + perSec*duration/time.Second)
+	if requests < perSec*int64(duration/time.Second) || requests > limit {
+		t.Errorf("got %d requests in %v, want between %d and %d", requests, duration, perSec*int64(duration/time.Second), limit)
+	}
+}
+
+func TestEmittedSpansDrawFromTracePool(t *testing.T) {
+	const poolSize = 2
+
+	ids := idgen.NewPool(idgen.NewSeeded(1), poolSize)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithIDGenerator(ids),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	c := &Config{
+		WorkerCount: 1,
+		NumLogs:     poolSize,
+		ServiceName: "test",
+		IDGenerator: ids,
+	}
+	g := NewGenerator(noop.NewLoggerProvider(), c, zap.NewNop()).WithTracerProvider(tp)
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	traces := map[trace.TraceID]bool{}
+	for _, s := range recorder.Ended() {
+		if !s.Parent().IsValid() {
+			traces[s.SpanContext().TraceID()] = true
+		}
+	}
+	// Drawing a fabricated trace ID as well as the span's would skip every other
+	// pool entry, leaving the requests on a single trace
+	if len(traces) != poolSize {
+		t.Errorf("requests used %d trace IDs, want every one of the %d in the pool", len(traces), poolSize)
+	}
+}
+
+func TestRecordsDrawFromTracePool(t *testing.T) {
+	for _, poolSize := range []int{1, 3} {
+		t.Run(fmt.Sprint(poolSize), func(t *testing.T) {
+			c := &Config{
+				WorkerCount: 2,
+				NumLogs:     2,
+				ServiceName: "test",
+				IDGenerator: idgen.NewPool(idgen.NewSeeded(1), poolSize),
+			}
+			traces := map[trace.TraceID]bool{}
+			for _, r := range emitLogs(t, c, nil) {
+				traces[r.TraceID()] = true
+			}
+			if len(traces) > poolSize {
+				t.Errorf("records reference %d trace IDs, want at most %d", len(traces), poolSize)
+			}
+		})
+	}
+}
+
+func TestRecordsCarryTraceContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		traced bool
+	}{
+		{name: "fabricated ids"},
+		{name: "emitted spans", traced: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{WorkerCount: 1, NumLogs: 1, ServiceName: "test", IDGenerator: idgen.NewSeeded(1)}
+			var recorder *tracetest.SpanRecorder
+			var tp trace.TracerProvider
+			if tt.traced {
+				recorder = tracetest.NewSpanRecorder()
+				sdktp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+				defer func() { _ = sdktp.Shutdown(context.Background()) }()
+				tp = sdktp
+			}
+
+			records := emitLogs(t, c, tp)
+			if len(records) != 3 {
+				t.Fatalf("got %d records, want one per phase", len(records))
+			}
+			spans := map[trace.SpanID]bool{}
+			for _, r := range records {
+				if !r.TraceID().IsValid() || !r.SpanID().IsValid() {
+					t.Errorf("record has trace id %s and span id %s, want both set", r.TraceID(), r.SpanID())
+				}
+				if !r.TraceFlags().IsSampled() {
+					t.Errorf("record trace flags = %s, want sampled", r.TraceFlags())
+				}
+				if r.TraceID() != records[0].TraceID() {
+					t.Errorf("record trace id = %s, want the request's %s", r.TraceID(), records[0].TraceID())
+				}
+				spans[r.SpanID()] = true
+				attrs := recordAttributes(r)
+				for _, key := range []string{"trace_id", "span_id"} {
+					if _, ok := attrs[key]; ok {
+						t.Errorf("record has a %s attribute, want it on the trace context only", key)
+					}
+				}
+			}
+			if len(spans) != len(records) {
+				t.Errorf("records used %d span ids, want one per phase", len(spans))
+			}
+			if !tt.traced {
+				return
+			}
+			for _, s := range recorder.Ended() {
+				if s.Parent().IsValid() && !spans[s.SpanContext().SpanID()] {
+					t.Errorf("no record references the %s phase span", s.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestRecordsReferenceEmittedSpans(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			c := &Config{WorkerCount: workers, NumLogs: 2, ServiceName: "test"}
+			records := emitLogs(t, c, tp)
+			if len(records) == 0 {
+				t.Fatal("no records were exported")
+			}
+
+			spans := map[trace.SpanID]trace.TraceID{}
+			for _, s := range recorder.Ended() {
+				spans[s.SpanContext().SpanID()] = s.SpanContext().TraceID()
+			}
+			for _, r := range records {
+				traceID, ok := spans[r.SpanID()]
+				if !ok {
+					t.Errorf("record references span %s, which wasn't emitted", r.SpanID())
+					continue
+				}
+				if r.TraceID() != traceID {
+					t.Errorf("record of span %s has trace id %s, want the span's %s", r.SpanID(), r.TraceID(), traceID)
+				}
+			}
+		})
+	}
+}
+
+func TestBodyFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		wantKind log.Kind
+		wantKeys []string
+	}{
+		{format: "", wantKind: log.KindString},
+		{format: BodyFormatString, wantKind: log.KindString},
+		{format: BodyFormatStructured, wantKind: log.KindMap, wantKeys: []string{"message", "phase", "method", "status_code"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			c := &Config{WorkerCount: 1, NumLogs: 1, ServiceName: "test", BodyFormat: tt.format}
+			for _, r := range emitLogs(t, c, nil) {
+				body := r.Body()
+				if body.Kind() != tt.wantKind {
+					t.Fatalf("body kind = %s, want %s", body.Kind(), tt.wantKind)
+				}
+				if tt.wantKind != log.KindMap {
+					continue
+				}
+				fields := map[string]log.Value{}
+				for _, kv := range body.AsMap() {
+					fields[kv.Key] = kv.Value
+				}
+				if len(fields) != len(tt.wantKeys) {
+					t.Errorf("body has %d fields, want %v", len(fields), tt.wantKeys)
+				}
+				for _, key := range tt.wantKeys {
+					if _, ok := fields[key]; !ok {
+						t.Errorf("body has no %s field", key)
+					}
+				}
+				if phase := recordAttributes(r)["phase"].AsString(); fields["phase"].AsString() != phase {
+					t.Errorf("body phase = %q, want the record's %q", fields["phase"].AsString(), phase)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordAttributes(t *testing.T) {
+	defaults := []string{"k8s.pod.name", "k8s.namespace.name", "k8s.container.name"}
+	custom := []attribute.KeyValue{attribute.String("team", "payments"), attribute.Int("shard", 3)}
+
+	tests := []struct {
+		name         string
+		attributes   []attribute.KeyValue
+		noDefaults   bool
+		wantDefaults bool
+	}{
+		{name: "defaults", wantDefaults: true},
+		{name: "custom", attributes: custom, wantDefaults: true},
+		{name: "custom without defaults", attributes: custom, noDefaults: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				WorkerCount:         1,
+				NumLogs:             1,
+				ServiceName:         "test",
+				Attributes:          tt.attributes,
+				NoDefaultAttributes: tt.noDefaults,
+			}
+			for _, r := range emitLogs(t, c, nil) {
+				attrs := recordAttributes(r)
+				for _, kv := range tt.attributes {
+					got, ok := attrs[string(kv.Key)]
+					if !ok {
+						t.Errorf("record has no %s attribute", kv.Key)
+						continue
+					}
+					if want := logKeyValue(kv).Value; !got.Equal(want) {
+						t.Errorf("record %s = %s, want %s", kv.Key, got, want)
+					}
+				}
+				for _, key := range defaults {
+					if _, ok := attrs[key]; ok != tt.wantDefaults {
+						t.Errorf("record has %s = %v, want %v", key, ok, tt.wantDefaults)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNewResourceK8SAttributes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want map[attribute.Key]string
+	}{
+		{
+			name: "defaults",
+			cfg:  Config{ServiceName: "test"},
+			want: map[attribute.Key]string{
+				semconv.K8SNamespaceNameKey: DefaultK8SNamespace,
+				semconv.K8SContainerNameKey: DefaultK8SContainer,
+			},
+		},
+		{
+			name: "overridden",
+			cfg:  Config{ServiceName: "test", K8SNamespace: "checkout", K8SPod: "checkout-7d9f8", K8SContainer: "api"},
+			want: map[attribute.Key]string{
+				semconv.K8SNamespaceNameKey: "checkout",
+				semconv.K8SPodNameKey:       "checkout-7d9f8",
+				semconv.K8SContainerNameKey: "api",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := newResource(&tt.cfg, zap.NewNop())
+			if err != nil {
+				t.Fatalf("newResource() error = %v", err)
+			}
+			for key, want := range tt.want {
+				if got, _ := res.Set().Value(key); got.AsString() != want {
+					t.Errorf("resource %s = %q, want %q", key, got.AsString(), want)
+				}
+			}
+			if pod, _ := res.Set().Value(semconv.K8SPodNameKey); pod.AsString() == "" {
+				t.Errorf("resource has no %s", semconv.K8SPodNameKey)
+			}
+		})
+	}
+}
+
+func TestRecordPodName(t *testing.T) {
+	tests := []struct {
+		name      string
+		randomize bool
+		wantNames int
+	}{
+		{name: "stable per worker", wantNames: 1},
+		{name: "randomized per record", randomize: true, wantNames: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{WorkerCount: 1, NumLogs: 1, ServiceName: "test", RandomizePodName: tt.randomize}
+			records := emitLogs(t, c, nil)
+			if len(records) < tt.wantNames {
+				t.Fatalf("got %d records, want at least %d", len(records), tt.wantNames)
+			}
+			names := map[string]bool{}
+			for _, r := range records {
+				names[recordAttributes(r)["k8s.pod.name"].AsString()] = true
+			}
+			if tt.randomize {
+				if len(names) < tt.wantNames {
+					t.Errorf("got %d pod names across %d records, want at least %d", len(names), len(records), tt.wantNames)
+				}
+			} else if len(names) != tt.wantNames {
+				t.Errorf("got pod names %v across %d records, want one", names, len(records))
+			}
+		})
+	}
+}
+
+func TestRecordPayload(t *testing.T) {
+	for _, size := range []int{0, 1, 4096} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			c := &Config{WorkerCount: 1, NumLogs: 1, ServiceName: "test", PayloadSize: size}
+			records := emitLogs(t, c, nil)
+			if len(records) == 0 {
+				t.Fatal("no records were exported")
+			}
+			for _, r := range records {
+				got, ok := recordAttributes(r)[payload.Key]
+				if ok != (size > 0) {
+					t.Fatalf("record has %s = %v, want %v", payload.Key, ok, size > 0)
+				}
+				if ok && len(got.AsString()) != size {
+					t.Errorf("record %s length = %d, want %d", payload.Key, len(got.AsString()), size)
+				}
+			}
+		})
+	}
+}
+
+func TestPadBody(t *testing.T) {
+	const message = "Log 1: Info phase: start"
+
+	tests := []struct {
+		name    string
+		size    int
+		wantLen int
+	}{
+		{name: "disabled", size: 0, wantLen: len(message)},
+		{name: "already long enough", size: len(message), wantLen: len(message)},
+		{name: "room for the marker only", size: len(message) + 1, wantLen: len(message) + len(BodyPaddingMarker)},
+		{name: "padded", size: 4096, wantLen: 4096},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := padBody(message, tt.size)
+			if len(got) != tt.wantLen {
+				t.Errorf("len(padBody(%d)) = %d, want %d", tt.size, len(got), tt.wantLen)
+			}
+			if !strings.HasPrefix(got, message) {
+				t.Errorf("padBody(%d) = %q, want it to start with the message", tt.size, got)
+			}
+			if padded := len(got) > len(message); padded && !strings.HasPrefix(got[len(message):], BodyPaddingMarker) {
+				t.Errorf("padBody(%d) has no %q marker after the message", tt.size, BodyPaddingMarker)
+			}
+		})
+	}
+}
+
+func TestMinBodySize(t *testing.T) {
+	const size = 2048
+
+	for _, format := range []string{BodyFormatString, BodyFormatStructured} {
+		t.Run(format, func(t *testing.T) {
+			c := &Config{WorkerCount: 1, NumLogs: 1, ServiceName: "test", BodyFormat: format, MinBodySize: size}
+			for _, r := range emitLogs(t, c, nil) {
+				message := r.Body()
+				if format == BodyFormatStructured {
+					for _, kv := range r.Body().AsMap() {
+						if kv.Key == "message" {
+							message = kv.Value
+						}
+					}
+				}
+				if got := len(message.AsString()); got < size {
+					t.Errorf("body message is %d bytes, want at least %d", got, size)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    log.Severity
+		wantErr bool
+	}{
+		{level: "trace", want: log.SeverityTrace1},
+		{level: "debug", want: log.SeverityDebug},
+		{level: "info", want: log.SeverityInfo},
+		{level: "WARN", want: log.SeverityWarn},
+		{level: "Error", want: log.SeverityError},
+		{level: "fatal", want: log.SeverityFatal},
+		{level: "warning", wantErr: true},
+		{level: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			got, err := ParseSeverity(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeverity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSeverity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandomSeverityFloor(t *testing.T) {
+	tests := []struct {
+		name  string
+		floor log.Severity
+		want  int
+	}{
+		{name: "unset", floor: log.SeverityUndefined, want: 6},
+		{name: "trace", floor: log.SeverityTrace1, want: 6},
+		{name: "warn", floor: log.SeverityWarn, want: 3},
+		{name: "between levels", floor: log.SeverityInfo2, want: 3},
+		{name: "fatal", floor: log.SeverityFatal, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[log.Severity]string{}
+			for i := 0; i < 1000; i++ {
+				level, text := randomSeverity(tt.floor)
+				if level < tt.floor {
+					t.Fatalf("randomSeverity() = %v, below the %v floor", level, tt.floor)
+				}
+				seen[level] = text
+			}
+			if len(seen) != tt.want {
+				t.Errorf("randomSeverity() gave the levels %v, want %d of them", seen, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinSeverity(t *testing.T) {
+	c := &Config{WorkerCount: 1, NumLogs: 2, ServiceName: "test", MinSeverity: log.SeverityError}
+	records := emitLogs(t, c, nil)
+	if len(records) == 0 {
+		t.Fatal("no records were emitted")
+	}
+	for _, r := range records {
+		if r.Severity() < log.SeverityError {
+			t.Errorf("record has severity %v (%s), below the error floor", r.Severity(), r.SeverityText())
+		}
+	}
+}
+
+func TestGeneratorReportsProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		wantAny  bool
+	}{
+		{name: "disabled"},
+		{name: "every 100ms", interval: 100 * time.Millisecond, wantAny: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			c := &Config{
+				WorkerCount:      1,
+				TotalDuration:    500 * time.Millisecond,
+				ProgressInterval: tt.interval,
+				ServiceName:      "test",
+			}
+			if err := NewGenerator(noop.NewLoggerProvider(), c, zap.New(core)).Run(context.Background()); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			heartbeats := logs.FilterMessage("generation progress").All()
+			if (len(heartbeats) > 0) != tt.wantAny {
+				t.Fatalf("got %d heartbeats, want any %v", len(heartbeats), tt.wantAny)
+			}
+			for _, h := range heartbeats {
+				if got := h.ContextMap()["item"]; got != "logs" {
+					t.Errorf("heartbeat item = %v, want logs", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/krzko/otelgen/internal/discard"
+	"github.com/krzko/otelgen/internal/exportguard"
+	"github.com/krzko/otelgen/internal/kafka"
+	"github.com/krzko/otelgen/internal/socket"
+	"github.com/krzko/otelgen/internal/tee"
+	"github.com/krzko/otelgen/internal/timeshift"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// newTracerProvider builds the tracer provider the spans of EmitSpans are recorded on,
+// exporting them to the same output as the log records under the same guard
+func newTracerProvider(c *Config, res *resource.Resource, guard *exportguard.Guard, logger *zap.Logger) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	if c.Discard {
+		exporter = discard.NewSpanExporter(logger)
+	} else if c.Syslog != "" {
+		return nil, fmt.Errorf("spans can't be sent to a syslog output")
+	} else if c.Socket != "" {
+		w, err := socket.NewWriter(c.Socket, logger)
+		if err != nil {
+			return nil, err
+		}
+		exporter = socket.NewSpanExporter(w)
+	} else if c.Kafka != "" {
+		w, err := kafka.NewWriter(c.Kafka, logger)
+		if err != nil {
+			return nil, err
+		}
+		exporter = socket.NewSpanExporter(w)
+	} else {
+		exporter, err = createSpanExporter(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create span exporter: %w", err)
+		}
+	}
+	if c.TeeStdout {
+		exporter = tee.SpanExporter(exporter, socket.NewSpanExporter(socket.Stdout(c.Pretty, c.OutputBuffer)))
+	}
+
+	spanExp := timeshift.SpanExporter(exporter, timeshift.New(c.StartTimeOffset, c.Window))
+	spanExp = guard.SpanExporter(spanExp)
+	spanExp = c.exportStats("traces", logger).SpanExporter(spanExp)
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSpanProcessor(c.spanProcessor(spanExp)),
+		sdktrace.WithResource(res),
+	}
+	if c.IDGenerator != nil {
+		opts = append(opts, sdktrace.WithIDGenerator(c.IDGenerator))
+	}
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+// spanProcessor returns the processor exporting the spans logs are correlated with
+// to exp, exporting each span as it ends when SyncExport is set.
+func (c *Config) spanProcessor(exp sdktrace.SpanExporter) sdktrace.SpanProcessor {
+	if c.SyncExport {
+		return sdktrace.NewSimpleSpanProcessor(exp)
+	}
+	return sdktrace.NewBatchSpanProcessor(exp, sdktrace.WithBatchTimeout(time.Second))
+}
+
+// createSpanExporter initialises the OTLP span exporter based on the configuration,
+// using the default traces path since URLPath names the logs one.
+func createSpanExporter(c *Config) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+
+	if c.UseHTTP {
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(c.Endpoint),
+		}
+		if c.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(c.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(c.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(c.Endpoint),
+	}
+	if c.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(c.Headers))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
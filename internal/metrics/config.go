@@ -3,22 +3,147 @@ package metrics
 import (
 	"flag"
 	"fmt"
+	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	"github.com/krzko/otelgen/internal/rateprofile"
+	"github.com/krzko/otelgen/internal/timeshift"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Config struct {
-	WorkerCount   int
-	NumMetrics    int
-	Rate          int64
-	TotalDuration time.Duration
-	ServiceName   string
+	WorkerCount       int
+	NumMetrics        int
+	Rate              int64
+	TotalDuration     time.Duration
+	RateProfile       rateprofile.Profile
+	TimingJitter      float64
+	ExemplarCount     int
+	ExemplarFilter    ExemplarFilter
+	ServiceName       string
+	ServiceVersion    string
+	ServiceInstanceID string
+	// NamePrefix and NameSuffix wrap the service name and the name of every instrument
+	NamePrefix string
+	NameSuffix string
+
+	DetectResources bool
+	ResourceFromEnv bool
+	// ResourceFromContainer merges the detected container.id into the resource
+	ResourceFromContainer bool
+
+	// MaxExportErrors aborts the run after this many consecutive export failures, 0 disables it
+	MaxExportErrors int
+	// ExportStats logs a summary of export outcomes on shutdown
+	ExportStats bool
+	// PointsPerExport is how many records each instrument accumulates between exports
+	PointsPerExport int
+	// Connections is how many OTLP exporters, each with its own connection, exports are split across
+	Connections int
+	// FlushInterval is how often buffered telemetry is force flushed, 0 disables it
+	FlushInterval time.Duration
+	// ShutdownTimeout bounds how long exporters and providers may take to drain on shutdown
+	ShutdownTimeout time.Duration
+	// Discard counts telemetry in place of exporting it
+	Discard bool
+	// Socket, when set, streams telemetry as ndjson to this tcp:// or udp:// address in place of exporting it
+	Socket string
+	// Kafka, when set, produces telemetry as ndjson to this kafka://host:port/topic in place of exporting it
+	Kafka string
+	// RemoteWrite, when set, sends data points to this promrw:// Prometheus remote-write receiver in place of exporting them
+	RemoteWrite string
+	// TeeStdout additionally prints every exported item to stdout as ndjson
+	TeeStdout bool
+	// Pretty indents the JSON TeeStdout prints, which is compact ndjson otherwise
+	Pretty bool
+	// OutputBuffer is the size in bytes of the buffer TeeStdout batches its writes in, 0 disables it
+	OutputBuffer int
+	// StartTimeOffset shifts the timestamps of exported telemetry this far into the past
+	StartTimeOffset time.Duration
+	// Window, when set, replays the run's telemetry evenly across a historical time range
+	Window *timeshift.Window
+	// Chaos gives some exported telemetry odd but valid edge cases, chosen from ChaosSeed
+	Chaos     bool
+	ChaosSeed int64
+
+	AttributeDrift      []AttributeDrift
+	AttributeDriftEvery int
+
+	// AttributePool holds the attributes from which AttributesPerPoint are drawn for each recording
+	AttributePool      []attribute.KeyValue
+	AttributesPerPoint int
+
+	// AttributeRows are cycled through record by record, producing one series per row
+	AttributeRows [][]attribute.KeyValue
+
+	// KeepAttributes, when set, drops every recorded attribute whose key isn't listed
+	KeepAttributes []string
+
+	// Clock drives the simulators, the real clock is used when nil
+	Clock clock.Clock
 
 	// OTLP config
 	Endpoint string
 	Insecure bool
 	UseHTTP  bool
 	Headers  HeaderValue
+	// URLPath overrides the default signal path used by the HTTP exporter
+	URLPath string
+}
+
+// minInterval is the shortest time between emissions, used when the rate is 0
+const minInterval = time.Millisecond
+
+// clock returns the configured clock, falling back to the real one.
+func (c Config) clock() clock.Clock {
+	if c.Clock == nil {
+		return clock.Real()
+	}
+	return c.Clock
+}
+
+// deadline returns a channel that fires once TotalDuration has elapsed on the
+// config's clock, or nil, which never fires, when no duration is set.
+func (c Config) deadline() <-chan time.Time {
+	if c.TotalDuration <= 0 {
+		return nil
+	}
+	return c.clock().After(c.TotalDuration)
+}
+
+// interval returns how long to wait before the next emission, honouring the rate profile.
+func (c Config) interval(runStart time.Time) time.Duration {
+	d := c.RateProfile.Interval(time.Duration(c.Rate)*time.Second, c.clock().Now().Sub(runStart), c.TotalDuration)
+	if d < minInterval {
+		return minInterval
+	}
+	return d
+}
+
+// ExportInterval returns how often the reader exports, long enough for each instrument
+// to record PointsPerExport times at the configured rate. A rate of 0 returns 0, which
+// leaves the reader on its default interval.
+func (c Config) ExportInterval() time.Duration {
+	points := c.PointsPerExport
+	if points < 1 {
+		points = 1
+	}
+	return time.Duration(c.Rate) * time.Second * time.Duration(points)
+}
+
+// jitter randomises d by up to ±TimingJitter of its length.
+func (c Config) jitter(r *rand.Rand, d time.Duration) time.Duration {
+	if c.TimingJitter <= 0 {
+		return d
+	}
+	offset := (r.Float64()*2 - 1) * c.TimingJitter
+	jittered := time.Duration(float64(d) * (1 + offset))
+	if jittered < minInterval {
+		return minInterval
+	}
+	return jittered
 }
 
 type HeaderValue map[string]string
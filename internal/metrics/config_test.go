@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	"github.com/krzko/otelgen/internal/rateprofile"
+)
+
+func TestConfigRateIsInterval(t *testing.T) {
+	tests := []struct {
+		name            string
+		rate            int64
+		points          int
+		wantInterval    time.Duration
+		wantExportEvery time.Duration
+	}{
+		{name: "unthrottled", rate: 0, points: 1, wantInterval: minInterval, wantExportEvery: 0},
+		{name: "every second", rate: 1, points: 1, wantInterval: time.Second, wantExportEvery: time.Second},
+		{name: "every five seconds", rate: 5, points: 10, wantInterval: 5 * time.Second, wantExportEvery: 50 * time.Second},
+		{name: "every minute", rate: 60, points: 2, wantInterval: time.Minute, wantExportEvery: 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewFake(time.Unix(0, 0))
+			c := Config{Rate: tt.rate, PointsPerExport: tt.points, Clock: clk}
+
+			if got := c.interval(clk.Now()); got != tt.wantInterval {
+				t.Errorf("interval() = %v, want %v", got, tt.wantInterval)
+			}
+			if got := c.ExportInterval(); got != tt.wantExportEvery {
+				t.Errorf("ExportInterval() = %v, want %v", got, tt.wantExportEvery)
+			}
+		})
+	}
+}
+
+func TestConfigIntervalFollowsRateProfile(t *testing.T) {
+	runStart := time.Unix(0, 0)
+
+	tests := []struct {
+		name    string
+		profile rateprofile.Profile
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{name: "constant", profile: rateprofile.Constant, elapsed: 0, want: time.Second},
+		{name: "linear at the start", profile: rateprofile.Linear, elapsed: 0, want: 4472 * time.Millisecond},
+		{name: "linear half way", profile: rateprofile.Linear, elapsed: 5 * time.Second, want: 1708 * time.Millisecond},
+		{name: "linear at the end", profile: rateprofile.Linear, elapsed: 10 * time.Second, want: time.Second},
+		{name: "spike before", profile: rateprofile.Spike, elapsed: 0, want: 5500 * time.Millisecond},
+		{name: "spike", profile: rateprofile.Spike, elapsed: 5 * time.Second, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewFake(runStart)
+			c := Config{Rate: 1, RateProfile: tt.profile, TotalDuration: 10 * time.Second, Clock: clk}
+			clk.Advance(tt.elapsed)
+
+			got := c.interval(runStart)
+			if diff := got - tt.want; diff < -tt.want/100 || diff > tt.want/100 {
+				t.Errorf("interval() after %v = %v, want %v", tt.elapsed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigJitter(t *testing.T) {
+	const period = time.Second
+
+	tests := []struct {
+		name   string
+		jitter float64
+	}{
+		{name: "exact cadence", jitter: 0},
+		{name: "a fifth", jitter: 0.2},
+		{name: "half", jitter: 0.5},
+		{name: "whole period", jitter: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{TimingJitter: tt.jitter}
+			r := rand.New(rand.NewSource(1))
+			low := time.Duration(float64(period) * (1 - tt.jitter))
+			high := time.Duration(float64(period) * (1 + tt.jitter))
+			if low < minInterval {
+				low = minInterval
+			}
+
+			varied := false
+			for i := 0; i < 1000; i++ {
+				got := c.jitter(r, period)
+				if got < low || got > high {
+					t.Fatalf("jitter() = %v, want between %v and %v", got, low, high)
+				}
+				varied = varied || got != period
+			}
+			if varied != (tt.jitter > 0) {
+				t.Errorf("intervals varied = %v, want %v", varied, tt.jitter > 0)
+			}
+		})
+	}
+}
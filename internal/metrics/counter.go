@@ -3,16 +3,14 @@ package metrics
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
 // Counter demonstrates how to measure non-decreasing int64s
-func SimulateCounter(mp metric.MeterProvider, conf *Config, logger *zap.Logger) {
-	c := *conf
-	err := run(conf, logger, counter(mp, c, logger))
+func SimulateCounter(ctx context.Context, mp metric.MeterProvider, conf *Config, logger *zap.Logger) {
+	err := NewCounterGenerator(mp, conf, logger).Run(ctx)
 	if err != nil {
 		logger.Error("failed to run counter", zap.Error(err))
 	}
@@ -29,26 +27,21 @@ func counter(mp metric.MeterProvider, c Config, logger *zap.Logger) WorkerFunc {
 			metric.WithDescription("Counter demonstrates how to measure non-decreasing numbers"),
 		)
 
+		clk := c.clock()
+		deadline := c.deadline()
+
 		var i int64
-		if c.TotalDuration > 0 {
-		loop:
-			for timeout := time.After(c.TotalDuration); ; {
-				select {
-				case <-timeout:
-					break loop
-				default:
-				}
-				i++
-				logger.Info("generating", zap.String("name", name))
-				counter.Add(ctx, i)
-				time.Sleep(time.Duration(c.Rate) * time.Second)
-			}
-		} else {
-			for {
-				i++
-				logger.Info("generating", zap.String("name", name))
-				counter.Add(ctx, i)
-				time.Sleep(time.Duration(c.Rate) * time.Second)
+		runStart := clk.Now()
+		for {
+			i++
+			logger.Info("generating", zap.String("name", name))
+			counter.Add(ctx, i)
+			select {
+			case <-ctx.Done():
+				return
+			case <-deadline:
+				return
+			case <-clk.After(c.interval(runStart)):
 			}
 		}
 	}
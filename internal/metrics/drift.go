@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// AttributeDrift describes an attribute whose value rotates through Values as records are emitted
+type AttributeDrift struct {
+	Key    string
+	Values []string
+}
+
+// withDrift returns attrs extended with the drifting attributes for the given record number.
+// The drifting values advance every AttributeDriftEvery records.
+func (c Config) withDrift(attrs []attribute.KeyValue, record int64) []attribute.KeyValue {
+	if len(c.AttributeDrift) == 0 {
+		return attrs
+	}
+
+	every := int64(c.AttributeDriftEvery)
+	if every < 1 {
+		every = 1
+	}
+	step := record / every
+
+	result := make([]attribute.KeyValue, 0, len(attrs)+len(c.AttributeDrift))
+	result = append(result, attrs...)
+	for _, d := range c.AttributeDrift {
+		result = append(result, attribute.String(d.Key, d.Values[step%int64(len(d.Values))]))
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestWithDrift(t *testing.T) {
+	base := []attribute.KeyValue{attribute.String("service", "otelgen")}
+	pods := AttributeDrift{Key: "pod.name", Values: []string{"a", "b", "c"}}
+	zones := AttributeDrift{Key: "zone", Values: []string{"x", "y"}}
+
+	tests := []struct {
+		name  string
+		drift []AttributeDrift
+		every int
+		// want holds the drifting values of the successive records, one slice per attribute
+		want [][]string
+	}{
+		{name: "none", want: nil},
+		{name: "every record", drift: []AttributeDrift{pods}, every: 1, want: [][]string{{"a", "b", "c", "a", "b", "c"}}},
+		{name: "every two records", drift: []AttributeDrift{pods}, every: 2, want: [][]string{{"a", "a", "b", "b", "c", "c"}}},
+		{name: "unset every", drift: []AttributeDrift{pods}, want: [][]string{{"a", "b", "c", "a", "b", "c"}}},
+		{name: "several attributes", drift: []AttributeDrift{pods, zones}, every: 1, want: [][]string{{"a", "b", "c", "a", "b", "c"}, {"x", "y", "x", "y", "x", "y"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{AttributeDrift: tt.drift, AttributeDriftEvery: tt.every}
+			got := make([][]string, len(tt.drift))
+			for record := int64(0); record < 6; record++ {
+				attrs := c.withDrift(base, record)
+				if !reflect.DeepEqual(attrs[:len(base)], base) {
+					t.Fatalf("record %d attributes = %v, want them to start with %v", record, attrs, base)
+				}
+				if len(attrs) != len(base)+len(tt.drift) {
+					t.Fatalf("record %d has %d attributes, want %d", record, len(attrs), len(base)+len(tt.drift))
+				}
+				for i, d := range tt.drift {
+					kv := attrs[len(base)+i]
+					if string(kv.Key) != d.Key {
+						t.Fatalf("record %d attribute %d key = %s, want %s", record, i, kv.Key, d.Key)
+					}
+					got[i] = append(got[i], kv.Value.AsString())
+				}
+			}
+			if len(tt.drift) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("drifting values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -29,6 +31,87 @@ func generateExemplar(r *rand.Rand, value float64, timestamp time.Time) Exemplar
 	}
 }
 
+// ExemplarFilter decides which measurements the SDK offers to its exemplar reservoirs
+type ExemplarFilter string
+
+const (
+	// ExemplarFilterAlwaysOn offers every measurement
+	ExemplarFilterAlwaysOn ExemplarFilter = "always_on"
+	// ExemplarFilterAlwaysOff offers none, disabling the SDK's exemplars
+	ExemplarFilterAlwaysOff ExemplarFilter = "always_off"
+	// ExemplarFilterTraceBased offers measurements recorded within a sampled span
+	ExemplarFilterTraceBased ExemplarFilter = "trace_based"
+)
+
+// ParseExemplarFilter returns the exemplar filter named s
+func ParseExemplarFilter(s string) (ExemplarFilter, error) {
+	switch f := ExemplarFilter(s); f {
+	case ExemplarFilterAlwaysOn, ExemplarFilterAlwaysOff, ExemplarFilterTraceBased:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported exemplar filter: %s, use one of: always_on, always_off, trace_based", s)
+}
+
+// Option returns the meter provider option that applies the filter, taking precedence
+// over OTEL_METRICS_EXEMPLAR_FILTER
+func (f ExemplarFilter) Option() sdkmetric.Option {
+	switch f {
+	case ExemplarFilterAlwaysOn:
+		return sdkmetric.WithExemplarFilter(exemplar.AlwaysOnFilter)
+	case ExemplarFilterAlwaysOff:
+		return sdkmetric.WithExemplarFilter(exemplar.AlwaysOffFilter)
+	default:
+		return sdkmetric.WithExemplarFilter(exemplar.TraceBasedFilter)
+	}
+}
+
+// DefaultExemplarCount is the number of exemplars kept per instrument when none is configured
+const DefaultExemplarCount = 10
+
+// exemplarReservoir keeps a fixed-size, uniformly random sample of the exemplars
+// offered to it using reservoir sampling, so memory stays bounded however many
+// measurements are taken.
+type exemplarReservoir struct {
+	r       *rand.Rand
+	seen    int64
+	samples []Exemplar
+}
+
+func newExemplarReservoir(r *rand.Rand, size int) *exemplarReservoir {
+	if size < 0 {
+		size = 0
+	}
+	return &exemplarReservoir{r: r, samples: make([]Exemplar, 0, size)}
+}
+
+// offer considers e for the reservoir, replacing a random sample once it is full
+func (res *exemplarReservoir) offer(e Exemplar) {
+	res.seen++
+	if len(res.samples) < cap(res.samples) {
+		res.samples = append(res.samples, e)
+		return
+	}
+	if j := res.r.Int63n(res.seen); j < int64(len(res.samples)) {
+		res.samples[j] = e
+	}
+}
+
+// len returns the number of exemplars currently held
+func (res *exemplarReservoir) len() int {
+	return len(res.samples)
+}
+
+// snapshot returns a copy of the held exemplars
+func (res *exemplarReservoir) snapshot() []Exemplar {
+	return append([]Exemplar(nil), res.samples...)
+}
+
+// reset empties the reservoir, starting a new sampling window
+func (res *exemplarReservoir) reset() {
+	res.samples = res.samples[:0]
+	res.seen = 0
+}
+
 func generateSpanID(r *rand.Rand) trace.SpanID {
 	var spanID trace.SpanID
 	r.Read(spanID[:])
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestParseExemplarFilter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ExemplarFilter
+		wantErr bool
+	}{
+		{value: "always_on", want: ExemplarFilterAlwaysOn},
+		{value: "always_off", want: ExemplarFilterAlwaysOff},
+		{value: "trace_based", want: ExemplarFilterTraceBased},
+		{value: "", wantErr: true},
+		{value: "sometimes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseExemplarFilter(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExemplarFilter(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseExemplarFilter(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExemplarFilterOption(t *testing.T) {
+	tests := []struct {
+		filter        ExemplarFilter
+		env           string
+		wantExemplars bool
+	}{
+		{filter: ExemplarFilterAlwaysOn, wantExemplars: true},
+		{filter: ExemplarFilterAlwaysOn, env: "always_off", wantExemplars: true},
+		{filter: ExemplarFilterAlwaysOff, env: "always_on", wantExemplars: false},
+		// Measurements are taken outside of any span, so none are sampled
+		{filter: ExemplarFilterTraceBased, env: "always_on", wantExemplars: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.filter)+"/env="+tt.env, func(t *testing.T) {
+			t.Setenv("OTEL_METRICS_EXEMPLAR_FILTER", tt.env)
+
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), tt.filter.Option())
+			defer func() { _ = mp.Shutdown(context.Background()) }()
+
+			counter, err := mp.Meter("test").Int64Counter("requests")
+			if err != nil {
+				t.Fatal(err)
+			}
+			counter.Add(context.Background(), 1)
+
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(context.Background(), &rm); err != nil {
+				t.Fatal(err)
+			}
+			sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+			if got := len(sum.DataPoints[0].Exemplars) > 0; got != tt.wantExemplars {
+				t.Errorf("exemplars recorded = %v, want %v", got, tt.wantExemplars)
+			}
+		})
+	}
+}
+
+func TestExemplarReservoirBounded(t *testing.T) {
+	tests := []struct {
+		size   int
+		offers int
+		want   int
+	}{
+		{size: 0, offers: 100, want: 0},
+		{size: -1, offers: 100, want: 0},
+		{size: 10, offers: 3, want: 3},
+		{size: 10, offers: 10, want: 10},
+		{size: 10, offers: 10000, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("size %d/offers %d", tt.size, tt.offers), func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			res := newExemplarReservoir(r, tt.size)
+			for i := 0; i < tt.offers; i++ {
+				res.offer(generateExemplar(r, float64(i), time.Unix(0, 0)))
+				if res.len() > max(tt.size, 0) {
+					t.Fatalf("reservoir holds %d exemplars after %d offers, want at most %d", res.len(), i+1, max(tt.size, 0))
+				}
+			}
+			if got := res.len(); got != tt.want {
+				t.Errorf("reservoir holds %d exemplars, want %d", got, tt.want)
+			}
+			if got := len(res.snapshot()); got != tt.want {
+				t.Errorf("snapshot has %d exemplars, want %d", got, tt.want)
+			}
+			res.reset()
+			if got := res.len(); got != 0 {
+				t.Errorf("reservoir holds %d exemplars after reset, want 0", got)
+			}
+		})
+	}
+}
+
+func TestExemplarReservoirUniform(t *testing.T) {
+	const (
+		size   = 5
+		offers = 20
+		trials = 4000
+	)
+	r := rand.New(rand.NewSource(1))
+	res := newExemplarReservoir(r, size)
+	kept := make([]int, offers)
+	for trial := 0; trial < trials; trial++ {
+		res.reset()
+		for i := 0; i < offers; i++ {
+			res.offer(Exemplar{Value: float64(i)})
+		}
+		for _, e := range res.snapshot() {
+			kept[int(e.Value)]++
+		}
+	}
+
+	// Every offer is kept with probability size/offers, early ones no more than late ones
+	want := float64(trials * size / offers)
+	for i, n := range kept {
+		if d := float64(n) - want; d < -0.1*want || d > 0.1*want {
+			t.Errorf("offer %d was kept %d times over %d trials, want about %.0f", i, n, trials, want)
+		}
+	}
+}
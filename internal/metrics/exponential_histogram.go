@@ -5,15 +5,26 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"time"
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap"
 )
 
+const (
+	// MinExponentialHistogramScale is the lowest scale permitted by OTLP
+	MinExponentialHistogramScale int32 = -10
+	// MaxExponentialHistogramScale is the highest scale permitted by OTLP
+	MaxExponentialHistogramScale int32 = 20
+	// DefaultZeroFraction is the share of generated values that are zero unless configured
+	DefaultZeroFraction = 0.05
+	// DefaultNegativeFraction is the share of generated values that are negative unless configured
+	DefaultNegativeFraction = 0.3
+)
+
 type ExponentialHistogramConfig struct {
 	Name          string
 	Description   string
@@ -24,6 +35,11 @@ type ExponentialHistogramConfig struct {
 	MaxSize       float64
 	RecordMinMax  bool
 	ZeroThreshold float64
+	// ZeroFraction is the probability a generated value is zero, landing in the zero
+	// bucket. Every other value is kept above ZeroThreshold.
+	ZeroFraction float64
+	// NegativeFraction is the probability a generated value is negative
+	NegativeFraction float64
 }
 
 type ExponentialHistogramDataPoint struct {
@@ -42,10 +58,45 @@ type ExponentialHistogramDataPoint struct {
 	Exemplars       []Exemplar
 }
 
-func SimulateExponentialHistogram(mp metric.MeterProvider, config ExponentialHistogramConfig, conf *Config, logger *zap.Logger) {
-	c := *conf
-	err := run(conf, logger, exponentialHistogram(mp, config, c, logger))
+// Validate checks the exponential histogram configuration is within the OTLP limits
+// and its fractions are probabilities
+func (config ExponentialHistogramConfig) Validate() error {
+	if config.Scale < MinExponentialHistogramScale || config.Scale > MaxExponentialHistogramScale {
+		return fmt.Errorf("exponential histogram scale %d is out of range, must be between %d and %d",
+			config.Scale, MinExponentialHistogramScale, MaxExponentialHistogramScale)
+	}
+	if config.ZeroFraction < 0 || config.ZeroFraction > 1 {
+		return fmt.Errorf("exponential histogram zero fraction %v is out of range, must be between 0 and 1", config.ZeroFraction)
+	}
+	if config.NegativeFraction < 0 || config.NegativeFraction > 1 {
+		return fmt.Errorf("exponential histogram negative fraction %v is out of range, must be between 0 and 1", config.NegativeFraction)
+	}
+	return nil
+}
+
+// ExponentialHistogramView returns a view that aggregates the exponential histogram
+// instrument into base2 exponential buckets rather than the default explicit buckets
+func ExponentialHistogramView(config ExponentialHistogramConfig) sdkmetric.View {
+	return sdkmetric.NewView(
+		sdkmetric.Instrument{Name: config.Name},
+		sdkmetric.Stream{
+			Aggregation: sdkmetric.AggregationBase2ExponentialHistogram{
+				MaxSize:  160,
+				MaxScale: config.Scale,
+				NoMinMax: !config.RecordMinMax,
+			},
+		},
+	)
+}
+
+func SimulateExponentialHistogram(ctx context.Context, mp metric.MeterProvider, config ExponentialHistogramConfig, conf *Config, logger *zap.Logger) {
+	g, err := NewExponentialHistogramGenerator(mp, config, conf, logger)
 	if err != nil {
+		logger.Error("invalid exponential histogram config", zap.Error(err))
+		return
+	}
+
+	if err := g.Run(ctx); err != nil {
 		logger.Error("failed to run exponential histogram", zap.Error(err))
 	}
 }
@@ -65,33 +116,33 @@ func exponentialHistogram(mp metric.MeterProvider, config ExponentialHistogramCo
 			return
 		}
 
-		ticker := time.NewTicker(time.Duration(c.Rate) * time.Second)
+		clk := c.clock()
+		runStart := clk.Now()
+		ticker := clk.NewTicker(c.interval(runStart))
 		defer ticker.Stop()
+		deadline := c.deadline()
 
-		var cancel context.CancelFunc
-		if c.TotalDuration > 0 {
-			ctx, cancel = context.WithTimeout(ctx, c.TotalDuration)
-			defer cancel()
-		}
-
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		r := rand.New(rand.NewSource(clk.Now().UnixNano()))
 
-		startTime := time.Now()
+		startTime := clk.Now()
 		var min, max float64
 		var zeroCount, totalCount uint64
 		positiveBuckets := make(map[int32]uint64)
 		negativeBuckets := make(map[int32]uint64)
 		var sum float64
-		var exemplars []Exemplar
+		exemplars := newExemplarReservoir(r, c.ExemplarCount)
 
 		for {
 			select {
 			case <-ctx.Done():
 				logger.Info("Stopping exponential histogram generation due to context cancellation")
 				return
-			case <-ticker.C:
-				value := generateExponentialHistogramValue(r, config.MaxSize, config.ZeroThreshold)
-				currentTime := time.Now()
+			case <-deadline:
+				logger.Info("Stopping exponential histogram generation as the duration has elapsed")
+				return
+			case <-ticker.C():
+				value := generateExponentialHistogramValue(r, config.MaxSize, config.ZeroThreshold, config.ZeroFraction, config.NegativeFraction)
+				currentTime := clk.Now()
 
 				if config.RecordMinMax {
 					if value < min || totalCount == 0 {
@@ -115,14 +166,9 @@ func exponentialHistogram(mp metric.MeterProvider, config ExponentialHistogramCo
 				totalCount++
 				sum += value
 
-				// Generate an exemplar
+				// Offer an exemplar to the reservoir
 				exemplar := generateExemplar(r, value, currentTime)
-				exemplars = append(exemplars, exemplar)
-
-				// Limit the number of exemplars to keep memory usage in check
-				if len(exemplars) > 10 {
-					exemplars = exemplars[1:]
-				}
+				exemplars.offer(exemplar)
 
 				histogram.Record(ctx, value, metric.WithAttributes(config.Attributes...))
 				logger.Info("generating",
@@ -137,7 +183,7 @@ func exponentialHistogram(mp metric.MeterProvider, config ExponentialHistogramCo
 					zap.Float64("max", max),
 					zap.Int("positive_buckets", len(positiveBuckets)),
 					zap.Int("negative_buckets", len(negativeBuckets)),
-					zap.Int("exemplars_count", len(exemplars)),
+					zap.Int("exemplars_count", exemplars.len()),
 				)
 
 				dataPoint := ExponentialHistogramDataPoint{
@@ -153,7 +199,7 @@ func exponentialHistogram(mp metric.MeterProvider, config ExponentialHistogramCo
 					NegativeBuckets: negativeBuckets,
 					Min:             min,
 					Max:             max,
-					Exemplars:       exemplars,
+					Exemplars:       exemplars.snapshot(),
 				}
 
 				if value < min || totalCount == 0 {
@@ -173,44 +219,52 @@ func exponentialHistogram(mp metric.MeterProvider, config ExponentialHistogramCo
 					zeroCount = 0
 					positiveBuckets = make(map[int32]uint64)
 					negativeBuckets = make(map[int32]uint64)
-					exemplars = nil
+					exemplars.reset()
 				}
 
 				processExponentialHistogramDataPoint(dataPoint, logger)
+				ticker.Reset(c.jitter(r, c.interval(runStart)))
 			}
 		}
 	}
 }
 
-func generateExponentialHistogramValue(r *rand.Rand, maxSize, zeroThreshold float64) float64 {
+func generateExponentialHistogramValue(r *rand.Rand, maxSize, zeroThreshold, zeroFraction, negativeFraction float64) float64 {
+	// The SDK's zero bucket only counts values that are exactly zero
+	if r.Float64() < zeroFraction {
+		return 0
+	}
+
 	// Generate a value using exponential distribution
 	value := r.ExpFloat64() * maxSize / 10
 
-	// Occasionally generate values near zero or maxSize
-	if r.Float64() < 0.1 {
-		if r.Float64() < 0.5 {
-			value = r.Float64() * zeroThreshold
-		} else {
-			value = maxSize - r.Float64()*(maxSize/100)
-		}
+	// Occasionally generate values near maxSize
+	if r.Float64() < 0.05 {
+		value = maxSize - r.Float64()*(maxSize/100)
+	}
+
+	// Keep the value out of the zero bucket so zeros occur at zeroFraction
+	if value <= zeroThreshold {
+		value = math.Nextafter(zeroThreshold, math.Inf(1))
 	}
 
 	// Randomly make some values negative
-	if r.Float64() < 0.3 {
+	if r.Float64() < negativeFraction {
 		value = -value
 	}
 
 	return value
 }
 
+// mapToIndex returns the bucket index for value, where bucket i covers the
+// range (base^i, base^(i+1)] and base is 2^(2^-scale).
 func mapToIndex(value float64, scale int32) int32 {
 	if value == 0 {
 		return 0
 	}
 	absValue := math.Abs(value)
-	// Calculate the base: 2^(2^(-scale))
-	base := math.Exp2(math.Exp2(-float64(scale)))
-	return int32(math.Floor(math.Log(absValue) / math.Log(base)))
+	// log_base(v) is log2(v) * 2^scale, Ldexp keeps this exact across the valid scale range
+	return int32(math.Ceil(math.Ldexp(math.Log2(absValue), int(scale)))) - 1
 }
 
 func processExponentialHistogramDataPoint(dataPoint ExponentialHistogramDataPoint, logger *zap.Logger) {
@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+func TestMapToIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		scale int32
+		want  int32
+	}{
+		{name: "zero", value: 0, scale: 0, want: 0},
+		{name: "one is the upper bound of bucket -1", value: 1, scale: 0, want: -1},
+		{name: "power of two at scale 0", value: 2, scale: 0, want: 0},
+		{name: "between powers of two at scale 0", value: 3, scale: 0, want: 1},
+		{name: "next power of two at scale 0", value: 4, scale: 0, want: 1},
+		{name: "below one at scale 0", value: 0.5, scale: 0, want: -2},
+		{name: "negative uses the magnitude", value: -3, scale: 0, want: 1},
+		{name: "power of two at scale 1", value: 2, scale: 1, want: 1},
+		{name: "between buckets at scale 1", value: 3, scale: 1, want: 3},
+		{name: "bucket bound at scale -1", value: 4, scale: -1, want: 0},
+		{name: "above bucket bound at scale -1", value: 5, scale: -1, want: 1},
+		{name: "upper bound at scale -1", value: 16, scale: -1, want: 1},
+		{name: "power of two at max scale", value: 2, scale: MaxExponentialHistogramScale, want: 1<<MaxExponentialHistogramScale - 1},
+		{name: "power of two at min scale", value: 2, scale: MinExponentialHistogramScale, want: 0},
+		{name: "largest power of two at min scale", value: math.Ldexp(1, 1023), scale: MinExponentialHistogramScale, want: 0},
+		{name: "smallest normal at min scale", value: math.Ldexp(1, -1022), scale: MinExponentialHistogramScale, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapToIndex(tt.value, tt.scale); got != tt.want {
+				t.Errorf("mapToIndex(%v, %d) = %d, want %d", tt.value, tt.scale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponentialHistogramConfigValidate(t *testing.T) {
+	valid := ExponentialHistogramConfig{
+		Scale:            0,
+		ZeroFraction:     DefaultZeroFraction,
+		NegativeFraction: DefaultNegativeFraction,
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*ExponentialHistogramConfig)
+		wantErr bool
+	}{
+		{name: "defaults", modify: func(*ExponentialHistogramConfig) {}},
+		{name: "min scale", modify: func(c *ExponentialHistogramConfig) { c.Scale = MinExponentialHistogramScale }},
+		{name: "max scale", modify: func(c *ExponentialHistogramConfig) { c.Scale = MaxExponentialHistogramScale }},
+		{name: "scale too small", modify: func(c *ExponentialHistogramConfig) { c.Scale = MinExponentialHistogramScale - 1 }, wantErr: true},
+		{name: "scale too large", modify: func(c *ExponentialHistogramConfig) { c.Scale = MaxExponentialHistogramScale + 1 }, wantErr: true},
+		{name: "no zeros", modify: func(c *ExponentialHistogramConfig) { c.ZeroFraction = 0 }},
+		{name: "all zeros", modify: func(c *ExponentialHistogramConfig) { c.ZeroFraction = 1 }},
+		{name: "negative zero fraction", modify: func(c *ExponentialHistogramConfig) { c.ZeroFraction = -0.1 }, wantErr: true},
+		{name: "zero fraction above one", modify: func(c *ExponentialHistogramConfig) { c.ZeroFraction = 1.5 }, wantErr: true},
+		{name: "no negatives", modify: func(c *ExponentialHistogramConfig) { c.NegativeFraction = 0 }},
+		{name: "all negatives", modify: func(c *ExponentialHistogramConfig) { c.NegativeFraction = 1 }},
+		{name: "negative negative fraction", modify: func(c *ExponentialHistogramConfig) { c.NegativeFraction = -0.1 }, wantErr: true},
+		{name: "negative fraction above one", modify: func(c *ExponentialHistogramConfig) { c.NegativeFraction = 1.5 }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			tt.modify(&c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateExponentialHistogramValueZeroFraction(t *testing.T) {
+	const (
+		samples       = 20000
+		zeroThreshold = 0.5
+	)
+
+	for _, fraction := range []float64{0, 0.05, 0.5, 1} {
+		t.Run(fmt.Sprint(fraction), func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			zeros := 0
+			for i := 0; i < samples; i++ {
+				if v := generateExponentialHistogramValue(r, 1000, zeroThreshold, fraction, DefaultNegativeFraction); math.Abs(v) <= zeroThreshold {
+					zeros++
+				}
+			}
+			if got := float64(zeros) / samples; math.Abs(got-fraction) > 0.01 {
+				t.Errorf("zero fraction = %v, want %v", got, fraction)
+			}
+		})
+	}
+}
+
+func TestGenerateExponentialHistogramValueNegativeFraction(t *testing.T) {
+	const samples = 20000
+
+	for _, fraction := range []float64{0, 0.3, 0.5, 1} {
+		t.Run(fmt.Sprint(fraction), func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			negatives := 0
+			for i := 0; i < samples; i++ {
+				if generateExponentialHistogramValue(r, 1000, 0, 0, fraction) < 0 {
+					negatives++
+				}
+			}
+			if got := float64(negatives) / samples; math.Abs(got-fraction) > 0.01 {
+				t.Errorf("negative fraction = %v, want %v", got, fraction)
+			}
+		})
+	}
+}
+
+func TestExponentialHistogramExportsExponentialData(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	config := ExponentialHistogramConfig{
+		Name:             "test.metrics.exponential_histogram",
+		Temporality:      metricdata.CumulativeTemporality,
+		Scale:            4,
+		MaxSize:          1000,
+		NegativeFraction: DefaultNegativeFraction,
+	}
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithView(ExponentialHistogramView(config)))
+	conf := &Config{ServiceName: "test", NumMetrics: 1, Rate: 1, TotalDuration: time.Minute, Clock: fake}
+
+	g, err := NewExponentialHistogramGenerator(mp, config, conf, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = g.Run(ctx) }()
+
+	waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+	fake.Advance(time.Second)
+
+	var data metricdata.Aggregation
+	waitFor(t, "the value to be recorded", func() bool {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatal(err)
+		}
+		if len(rm.ScopeMetrics) == 0 {
+			return false
+		}
+		data = rm.ScopeMetrics[0].Metrics[0].Data
+		return true
+	})
+
+	hist, ok := data.(metricdata.ExponentialHistogram[float64])
+	if !ok {
+		t.Fatalf("exported %T, want an exponential histogram", data)
+	}
+	if got := hist.DataPoints[0].Count; got != 1 {
+		t.Errorf("count = %d, want 1", got)
+	}
+	if got := hist.DataPoints[0].Scale; got > config.Scale {
+		t.Errorf("scale = %d, want at most %d", got, config.Scale)
+	}
+}
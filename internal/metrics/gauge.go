@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+type GaugeConfig struct {
+	Name        string
+	Description string
+	Unit        string
+	Attributes  []attribute.KeyValue
+	Min         float64
+	Max         float64
+	Temporality metricdata.Temporality
+}
+
+// SimulateGauge demonstrates how to measure a value that can go up and down
+func SimulateGauge(ctx context.Context, mp metric.MeterProvider, gaugeConfig GaugeConfig, conf *Config, logger *zap.Logger) error {
+	if err := NewGaugeGenerator(mp, gaugeConfig, conf, logger).Run(ctx); err != nil {
+		logger.Error("failed to run gauge", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func gauge(mp metric.MeterProvider, gc GaugeConfig, c Config, logger *zap.Logger) WorkerFunc {
+	return func(ctx context.Context) {
+		name := gc.Name
+		if name == "" {
+			name = fmt.Sprintf("%v.metrics.gauge", c.ServiceName)
+		}
+		logger.Debug("generating gauge", zap.String("name", name))
+		gauge, _ := mp.Meter(c.ServiceName).Float64ObservableGauge(
+			name,
+			metric.WithUnit(gc.Unit),
+			metric.WithDescription(gc.Description),
+		)
+
+		clk := c.clock()
+		r := rand.New(rand.NewSource(clk.Now().UnixNano()))
+		exemplars := newExemplarReservoir(r, c.ExemplarCount)
+
+		// latest holds the bits of the value most recently generated by the loop below,
+		// so the callback exports exactly what was logged
+		var latest atomic.Uint64
+		latest.Store(math.Float64bits(generateGaugeValue(gc.Min, gc.Max, clk.Now())))
+
+		// The callback runs on the reader's goroutine so it needs its own source
+		cr := rand.New(rand.NewSource(clk.Now().UnixNano()))
+		var observations int64
+		_, err := mp.Meter(c.ServiceName).RegisterCallback(func(_ context.Context, o metric.Observer) error {
+			value := math.Float64frombits(latest.Load())
+			o.ObserveFloat64(gauge, value, metric.WithAttributes(c.recordAttributes(cr, gc.Attributes, observations)...))
+			observations++
+			return nil
+		}, gauge)
+
+		if err != nil {
+			logger.Error("failed to register callback", zap.Error(err))
+			return
+		}
+
+		runStart := clk.Now()
+		ticker := clk.NewTicker(c.interval(runStart))
+		defer ticker.Stop()
+		deadline := c.deadline()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info("Stopping gauge generation due to context cancellation")
+				return
+			case <-deadline:
+				logger.Info("Stopping gauge generation as the duration has elapsed")
+				return
+			case <-ticker.C():
+				value := generateGaugeValue(gc.Min, gc.Max, clk.Now())
+				latest.Store(math.Float64bits(value))
+				exemplar := generateExemplar(r, value, clk.Now())
+				exemplars.offer(exemplar)
+				logger.Info("generating",
+					zap.String("name", name),
+					zap.Float64("value", value),
+					zap.String("temporality", gc.Temporality.String()),
+					zap.Int("exemplars_count", exemplars.len()),
+				)
+				ticker.Reset(c.jitter(r, c.interval(runStart)))
+			}
+		}
+	}
+}
+
+// generateGaugeValue returns the value of a sine wave between min and max at now
+func generateGaugeValue(min, max float64, now time.Time) float64 {
+	amplitude := (max - min) / 2
+	center := min + amplitude
+	return center + amplitude*math.Sin(float64(now.UnixNano())/1e9)
+}
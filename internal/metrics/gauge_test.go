@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestGaugeObservesLoggedValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max float64
+	}{
+		{name: "unit", min: 0, max: 1},
+		{name: "wide", min: -50, max: 250},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			conf := &Config{ServiceName: "test", NumMetrics: 1, Rate: 1, TotalDuration: time.Minute, Clock: fake}
+			gc := GaugeConfig{
+				Name:       "test.gauge",
+				Attributes: []attribute.KeyValue{attribute.String("host", "a")},
+				Min:        tt.min,
+				Max:        tt.max,
+			}
+			core, logs := observer.New(zap.InfoLevel)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = SimulateGauge(ctx, mp, gc, conf, zap.New(core)) }()
+
+			waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+			for tick := 1; tick <= 3; tick++ {
+				fake.Advance(time.Second)
+				waitFor(t, "the value to be generated", func() bool { return logs.FilterMessage("generating").Len() == tick })
+
+				logged := logs.FilterMessage("generating").All()[tick-1].ContextMap()["value"].(float64)
+				if logged < tt.min || logged > tt.max {
+					t.Errorf("tick %d generated %v, want it between %v and %v", tick, logged, tt.min, tt.max)
+				}
+
+				m := collectMetric(t, reader, gc.Name)
+				points := m.Data.(metricdata.Gauge[float64]).DataPoints
+				if len(points) != 1 {
+					t.Fatalf("tick %d observed %d points, want 1", tick, len(points))
+				}
+				if points[0].Value != logged {
+					t.Errorf("tick %d observed %v, want the logged %v", tick, points[0].Value, logged)
+				}
+				if v, ok := points[0].Attributes.Value("host"); !ok || v.AsString() != "a" {
+					t.Errorf("tick %d observed attributes %v, want host=a", tick, points[0].Attributes.ToSlice())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+// Generator records one instrument, or a manifest of them, on a meter provider built
+// by the caller until the configured duration elapses or its context is cancelled
+type Generator struct {
+	config *Config
+	logger *zap.Logger
+	work   WorkerFunc
+}
+
+// Run generates metrics until the configured duration elapses or ctx is cancelled
+func (g *Generator) Run(ctx context.Context) error {
+	w := NewWorker(g.config, g.logger)
+	if err := w.Run(ctx, g.work); err != nil {
+		return fmt.Errorf("failed to run worker: %w", err)
+	}
+	return nil
+}
+
+// NewCounterGenerator returns a generator recording a monotonically increasing counter
+func NewCounterGenerator(mp metric.MeterProvider, conf *Config, logger *zap.Logger) *Generator {
+	return &Generator{config: conf, logger: logger, work: counter(mp, *conf, logger)}
+}
+
+// NewUpDownCounterGenerator returns a generator recording a counter that moves up and down
+func NewUpDownCounterGenerator(mp metric.MeterProvider, udcConfig UpDownCounterConfig, conf *Config, logger *zap.Logger) *Generator {
+	return &Generator{config: conf, logger: logger, work: upDownCounter(mp, udcConfig, *conf, logger)}
+}
+
+// NewSumGenerator returns a generator recording the sum described by sumConfig
+func NewSumGenerator(mp metric.MeterProvider, sumConfig SumConfig, conf *Config, logger *zap.Logger) *Generator {
+	return &Generator{config: conf, logger: logger, work: sum(mp, sumConfig, *conf, logger)}
+}
+
+// NewGaugeGenerator returns a generator observing the gauge described by gaugeConfig
+func NewGaugeGenerator(mp metric.MeterProvider, gaugeConfig GaugeConfig, conf *Config, logger *zap.Logger) *Generator {
+	return &Generator{config: conf, logger: logger, work: gauge(mp, gaugeConfig, *conf, logger)}
+}
+
+// NewHistogramGenerator returns a generator recording the histogram described by config
+func NewHistogramGenerator(mp metric.MeterProvider, config HistogramConfig, conf *Config, logger *zap.Logger) *Generator {
+	return &Generator{config: conf, logger: logger, work: histogram(mp, config, *conf, logger)}
+}
+
+// NewExponentialHistogramGenerator returns a generator recording the exponential
+// histogram described by config, which must be valid
+func NewExponentialHistogramGenerator(mp metric.MeterProvider, config ExponentialHistogramConfig, conf *Config, logger *zap.Logger) (*Generator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Generator{config: conf, logger: logger, work: exponentialHistogram(mp, config, *conf, logger)}, nil
+}
+
+// NewManifestGenerator returns a generator recording every instrument in the manifest
+// concurrently on the shared meter provider
+func NewManifestGenerator(mp metric.MeterProvider, m *Manifest, temporality metricdata.Temporality, conf *Config, logger *zap.Logger) *Generator {
+	return &Generator{config: conf, logger: logger, work: manifest(mp, m, temporality, *conf, logger)}
+}
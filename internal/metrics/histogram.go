@@ -14,6 +14,13 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// ValueTypeFloat records histogram samples as float64 values
+	ValueTypeFloat = "float"
+	// ValueTypeInt records histogram samples as int64 values
+	ValueTypeInt = "int"
+)
+
 type HistogramConfig struct {
 	Name         string
 	Description  string
@@ -22,6 +29,9 @@ type HistogramConfig struct {
 	Temporality  metricdata.Temporality
 	Bounds       []float64
 	RecordMinMax bool
+	ValueType    string
+	// Series is how many series, told apart by SeriesKey, each record is made into
+	Series int
 }
 
 type HistogramDataPoint struct {
@@ -37,9 +47,8 @@ type HistogramDataPoint struct {
 	Exemplars     []Exemplar
 }
 
-func SimulateHistogram(mp metric.MeterProvider, config HistogramConfig, conf *Config, logger *zap.Logger) {
-	c := *conf
-	err := run(conf, logger, histogram(mp, config, c, logger))
+func SimulateHistogram(ctx context.Context, mp metric.MeterProvider, config HistogramConfig, conf *Config, logger *zap.Logger) {
+	err := NewHistogramGenerator(mp, config, conf, logger).Run(ctx)
 	if err != nil {
 		logger.Error("failed to run histogram", zap.Error(err))
 	}
@@ -47,122 +56,197 @@ func SimulateHistogram(mp metric.MeterProvider, config HistogramConfig, conf *Co
 
 func histogram(mp metric.MeterProvider, config HistogramConfig, c Config, logger *zap.Logger) WorkerFunc {
 	return func(ctx context.Context) {
-		name := fmt.Sprintf("%v.metrics.histogram", c.ServiceName)
+		name := config.Name
+		if name == "" {
+			name = fmt.Sprintf("%v.metrics.histogram", c.ServiceName)
+		}
 		logger.Debug("generating histogram", zap.String("name", name))
 
-		histogram, err := mp.Meter(c.ServiceName).Float64Histogram(
-			name,
-			metric.WithUnit(config.Unit),
-			metric.WithDescription(config.Description),
-			metric.WithExplicitBucketBoundaries(config.Bounds...),
-		)
+		record, err := newHistogramRecorder(mp.Meter(c.ServiceName), name, config)
 		if err != nil {
 			logger.Error("failed to create histogram", zap.Error(err))
 			return
 		}
 
-		ticker := time.NewTicker(time.Duration(c.Rate) * time.Second)
+		clk := c.clock()
+		runStart := clk.Now()
+		ticker := clk.NewTicker(c.interval(runStart))
 		defer ticker.Stop()
+		deadline := c.deadline()
 
-		var cancel context.CancelFunc
-		if c.TotalDuration > 0 {
-			ctx, cancel = context.WithTimeout(ctx, c.TotalDuration)
-			defer cancel()
-		}
-
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		r := rand.New(rand.NewSource(clk.Now().UnixNano()))
 
-		startTime := time.Now()
-		bucketCounts := make([]uint64, len(config.Bounds)+1)
-		var count uint64
-		var sum, min, max float64
-		var exemplars []Exemplar
+		n := config.Series
+		if n < 1 {
+			n = 1
+		}
+		series := make([]*histogramSeries, n)
+		for k := range series {
+			series[k] = &histogramSeries{
+				startTime:    clk.Now(),
+				bucketCounts: make([]uint64, len(config.Bounds)+1),
+				exemplars:    newExemplarReservoir(r, c.ExemplarCount),
+			}
+			if config.Series > 1 {
+				series[k].attr = SeriesKey.Int(k)
+			}
+		}
+		var records int64
 
 		for {
 			select {
 			case <-ctx.Done():
 				logger.Info("Stopping histogram generation due to context cancellation")
 				return
-			case <-ticker.C:
-				value := generateHistogramValue(r, config.Bounds)
-				count++
-				sum += value
-				currentTime := time.Now()
-
-				if config.RecordMinMax {
-					if value < min || count == 1 {
-						min = value
-					}
-					if value > max || count == 1 {
-						max = value
+			case <-deadline:
+				logger.Info("Stopping histogram generation as the duration has elapsed")
+				return
+			case <-ticker.C():
+				currentTime := clk.Now()
+				attrs := c.recordAttributes(r, config.Attributes, records)
+				records++
+				for _, s := range series {
+					value := generateHistogramValue(r, config.Bounds, config.ValueType == ValueTypeInt)
+					recordAttrs := attrs
+					if s.attr.Valid() {
+						recordAttrs = append(append([]attribute.KeyValue{}, attrs...), s.attr)
 					}
+					record(ctx, value, metric.WithAttributes(recordAttrs...))
+					s.observe(r, value, currentTime, name, config, logger)
 				}
+				ticker.Reset(c.jitter(r, c.interval(runStart)))
+			}
+		}
+	}
+}
 
-				bucketIndex := findBucket(value, config.Bounds)
-				bucketCounts[bucketIndex]++
+// SeriesKey is the attribute that tells the series of a multi-series histogram apart
+const SeriesKey = attribute.Key("otelgen.series")
 
-				// Generate an exemplar
-				exemplar := generateExemplar(r, value, currentTime)
-				exemplars = append(exemplars, exemplar)
+// histogramSeries accumulates the values recorded into one series of a histogram,
+// mirroring what the SDK aggregates for it
+type histogramSeries struct {
+	// attr identifies the series, it is unset when the histogram has only one
+	attr          attribute.KeyValue
+	startTime     time.Time
+	bucketCounts  []uint64
+	count         uint64
+	sum, min, max float64
+	exemplars     *exemplarReservoir
+}
 
-				// Limit the number of exemplars to keep memory usage in check
-				if len(exemplars) > 10 {
-					exemplars = exemplars[1:]
-				}
+// observe accumulates value, recorded at currentTime, and logs the resulting data point
+func (s *histogramSeries) observe(r *rand.Rand, value float64, currentTime time.Time, name string, config HistogramConfig, logger *zap.Logger) {
+	s.count++
+	s.sum += value
 
-				histogram.Record(ctx, value, metric.WithAttributes(config.Attributes...))
-
-				// Log the current state of the histogram
-				logger.Info("generating",
-					zap.String("name", name),
-					zap.Float64("value", value),
-					zap.String("temporality", config.Temporality.String()),
-					zap.Uint64("count", count),
-					zap.Float64("sum", sum),
-					zap.Float64("min", min),
-					zap.Float64("max", max),
-					zap.Int64("duration_seconds", currentTime.Sub(startTime).Milliseconds()/1000),
-					zap.Reflect("bucket_counts", bucketCounts),
-					zap.Int("exemplars_count", len(exemplars)),
-				)
-
-				dataPoint := HistogramDataPoint{
-					ID:            uuid.New().String(),
-					Attributes:    config.Attributes,
-					StartTimeUnix: startTime.UnixNano(),
-					TimeUnix:      currentTime.UnixNano(),
-					Count:         count,
-					Sum:           sum,
-					Min:           min,
-					Max:           max,
-					BucketCounts:  bucketCounts,
-					Exemplars:     exemplars,
-				}
+	if config.RecordMinMax {
+		if value < s.min || s.count == 1 {
+			s.min = value
+		}
+		if value > s.max || s.count == 1 {
+			s.max = value
+		}
+	}
 
-				if config.Temporality == metricdata.DeltaTemporality {
-					// Reset for next delta
-					startTime = currentTime
-					count = 0
-					sum = 0
-					min = 0
-					max = 0
-					bucketCounts = make([]uint64, len(config.Bounds)+1)
-					exemplars = nil
-				}
+	bucketIndex := findBucket(value, config.Bounds)
+	s.bucketCounts[bucketIndex]++
 
-				processHistogramDataPoint(dataPoint, logger)
-			}
+	// Offer an exemplar to the reservoir
+	exemplar := generateExemplar(r, value, currentTime)
+	s.exemplars.offer(exemplar)
+
+	attrs := config.Attributes
+	fields := []zap.Field{
+		zap.String("name", name),
+		zap.Float64("value", value),
+		zap.String("temporality", config.Temporality.String()),
+		zap.Uint64("count", s.count),
+		zap.Float64("sum", s.sum),
+		zap.Float64("min", s.min),
+		zap.Float64("max", s.max),
+		zap.Int64("duration_seconds", currentTime.Sub(s.startTime).Milliseconds()/1000),
+		zap.Reflect("bucket_counts", s.bucketCounts),
+		zap.Int("exemplars_count", s.exemplars.len()),
+	}
+	if s.attr.Valid() {
+		attrs = append(append([]attribute.KeyValue{}, attrs...), s.attr)
+		fields = append(fields, zap.Int64("series", s.attr.Value.AsInt64()))
+	}
+
+	// Log the current state of the histogram
+	logger.Info("generating", fields...)
+
+	dataPoint := HistogramDataPoint{
+		ID:            uuid.New().String(),
+		Attributes:    attrs,
+		StartTimeUnix: s.startTime.UnixNano(),
+		TimeUnix:      currentTime.UnixNano(),
+		Count:         s.count,
+		Sum:           s.sum,
+		Min:           s.min,
+		Max:           s.max,
+		BucketCounts:  s.bucketCounts,
+		Exemplars:     s.exemplars.snapshot(),
+	}
+
+	if config.Temporality == metricdata.DeltaTemporality {
+		// Reset for next delta
+		s.startTime = currentTime
+		s.count = 0
+		s.sum = 0
+		s.min = 0
+		s.max = 0
+		s.bucketCounts = make([]uint64, len(config.Bounds)+1)
+		s.exemplars.reset()
+	}
+
+	processHistogramDataPoint(dataPoint, logger)
+}
+
+// newHistogramRecorder creates the histogram instrument matching the configured value type
+// and returns a function that records float64 samples into it.
+func newHistogramRecorder(meter metric.Meter, name string, config HistogramConfig) (func(context.Context, float64, ...metric.RecordOption), error) {
+	if config.ValueType == ValueTypeInt {
+		histogram, err := meter.Int64Histogram(
+			name,
+			metric.WithUnit(config.Unit),
+			metric.WithDescription(config.Description),
+			metric.WithExplicitBucketBoundaries(config.Bounds...),
+		)
+		if err != nil {
+			return nil, err
 		}
+		return func(ctx context.Context, value float64, opts ...metric.RecordOption) {
+			histogram.Record(ctx, int64(value), opts...)
+		}, nil
+	}
+
+	histogram, err := meter.Float64Histogram(
+		name,
+		metric.WithUnit(config.Unit),
+		metric.WithDescription(config.Description),
+		metric.WithExplicitBucketBoundaries(config.Bounds...),
+	)
+	if err != nil {
+		return nil, err
 	}
+	return histogram.Record, nil
 }
 
-func generateHistogramValue(r *rand.Rand, bounds []float64) float64 {
+func generateHistogramValue(r *rand.Rand, bounds []float64, integer bool) float64 {
+	var value float64
 	if len(bounds) == 0 {
-		return r.Float64() * 100
+		value = r.Float64() * 100
+	} else {
+		maxBound := bounds[len(bounds)-1]
+		// Generate values with a slight bias towards lower buckets
+		value = math.Pow(r.Float64(), 1.5) * maxBound * 1.1
+	}
+	if integer {
+		return math.Round(value)
 	}
-	maxBound := bounds[len(bounds)-1]
-	// Generate values with a slight bias towards lower buckets
-	return math.Pow(r.Float64(), 1.5) * maxBound * 1.1
+	return value
 }
 
 func findBucket(value float64, bounds []float64) int {
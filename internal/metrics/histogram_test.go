@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+// collectMetric returns the metric named name from a collection of reader
+func collectMetric(t *testing.T, reader sdkmetric.Reader, name string) metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	t.Fatalf("metric %s wasn't collected", name)
+	return metricdata.Metrics{}
+}
+
+func TestGenerateHistogramValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  []float64
+		integer bool
+	}{
+		{name: "float without bounds", bounds: nil},
+		{name: "float with bounds", bounds: []float64{1, 5, 10}},
+		{name: "int without bounds", bounds: nil, integer: true},
+		{name: "int with bounds", bounds: []float64{1, 5, 10}, integer: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			max := 100.0
+			if len(tt.bounds) > 0 {
+				max = tt.bounds[len(tt.bounds)-1] * 1.1
+			}
+
+			r := rand.New(rand.NewSource(1))
+			fractional := 0
+			for i := 0; i < 1000; i++ {
+				v := generateHistogramValue(r, tt.bounds, tt.integer)
+				if v < 0 || v > math.Ceil(max) {
+					t.Fatalf("generateHistogramValue() = %v, want between 0 and %v", v, max)
+				}
+				if v != math.Trunc(v) {
+					fractional++
+				}
+			}
+			if tt.integer && fractional > 0 {
+				t.Errorf("got %d fractional values on the int path", fractional)
+			}
+			if !tt.integer && fractional == 0 {
+				t.Error("got only whole values on the float path")
+			}
+		})
+	}
+}
+
+func TestNewHistogramRecorder(t *testing.T) {
+	tests := []struct {
+		valueType string
+		wantSum   interface{}
+	}{
+		{valueType: ValueTypeFloat, wantSum: 4.5},
+		{valueType: ValueTypeInt, wantSum: int64(4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.valueType, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			config := HistogramConfig{Bounds: []float64{1, 5, 10}, ValueType: tt.valueType}
+
+			record, err := newHistogramRecorder(mp.Meter("test"), "test.histogram", config)
+			if err != nil {
+				t.Fatalf("newHistogramRecorder() error = %v", err)
+			}
+			record(context.Background(), 2)
+			record(context.Background(), 2.5)
+
+			var gotSum interface{}
+			switch data := collectMetric(t, reader, "test.histogram").Data.(type) {
+			case metricdata.Histogram[float64]:
+				gotSum = data.DataPoints[0].Sum
+			case metricdata.Histogram[int64]:
+				gotSum = data.DataPoints[0].Sum
+			default:
+				t.Fatalf("got %T, want a histogram", data)
+			}
+			if gotSum != tt.wantSum {
+				t.Errorf("sum = %v (%T), want %v (%T)", gotSum, gotSum, tt.wantSum, tt.wantSum)
+			}
+		})
+	}
+}
+
+func TestHistogramSeries(t *testing.T) {
+	tests := []struct {
+		name   string
+		series int
+	}{
+		{name: "unset", series: 0},
+		{name: "single", series: 1},
+		{name: "three", series: 3},
+		{name: "ten", series: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			conf := &Config{ServiceName: "test", NumMetrics: 1, Rate: 1, TotalDuration: time.Minute, Clock: fake}
+			config := HistogramConfig{
+				Name:        "test.histogram",
+				Bounds:      []float64{1, 5, 10},
+				ValueType:   ValueTypeFloat,
+				Temporality: metricdata.CumulativeTemporality,
+				Series:      tt.series,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go SimulateHistogram(ctx, mp, config, conf, zap.NewNop())
+			waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+
+			const records = 2
+			wantSeries := max(tt.series, 1)
+			var points []metricdata.HistogramDataPoint[float64]
+			for i := 1; i <= records; i++ {
+				fake.Advance(time.Second)
+				waitFor(t, "the records", func() bool {
+					var rm metricdata.ResourceMetrics
+					if err := reader.Collect(context.Background(), &rm); err != nil || len(rm.ScopeMetrics) == 0 {
+						return false
+					}
+					points = rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64]).DataPoints
+					var count uint64
+					for _, dp := range points {
+						count += dp.Count
+					}
+					return count == uint64(i*wantSeries)
+				})
+			}
+
+			if len(points) != wantSeries {
+				t.Fatalf("got %d data points, want %d", len(points), wantSeries)
+			}
+			seen := map[int64]bool{}
+			for _, dp := range points {
+				if dp.Count != records {
+					t.Errorf("data point %v counts %d records, want %d", dp.Attributes.ToSlice(), dp.Count, records)
+				}
+				v, ok := dp.Attributes.Value(SeriesKey)
+				if tt.series <= 1 {
+					if ok {
+						t.Errorf("a single series has the %s attribute", SeriesKey)
+					}
+					continue
+				}
+				if !ok || v.AsInt64() < 0 || v.AsInt64() >= int64(tt.series) {
+					t.Errorf("data point has %s = %v, want 0 to %d", SeriesKey, v.AsInterface(), tt.series-1)
+				}
+				seen[v.AsInt64()] = true
+			}
+			if tt.series > 1 && len(seen) != tt.series {
+				t.Errorf("got the series %v, want %d distinct ones", seen, tt.series)
+			}
+		})
+	}
+}
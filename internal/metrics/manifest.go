@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// InstrumentSum emits a sum, monotonic unless configured otherwise
+	InstrumentSum = "sum"
+	// InstrumentGauge emits a gauge oscillating between min and max
+	InstrumentGauge = "gauge"
+	// InstrumentHistogram emits a histogram over the configured bounds
+	InstrumentHistogram = "histogram"
+)
+
+// Manifest lists the instruments emitted together on a shared meter provider
+type Manifest struct {
+	Instruments []InstrumentSpec `yaml:"instruments"`
+}
+
+// InstrumentSpec describes a single instrument in a manifest
+type InstrumentSpec struct {
+	Name        string            `yaml:"name"`
+	Type        string            `yaml:"type"`
+	Description string            `yaml:"description"`
+	Unit        string            `yaml:"unit"`
+	Attributes  map[string]string `yaml:"attributes"`
+	// Monotonic applies to sums and defaults to true
+	Monotonic *bool `yaml:"monotonic"`
+	// Min and Max apply to gauges
+	Min float64 `yaml:"min"`
+	Max float64 `yaml:"max"`
+	// Bounds and ValueType apply to histograms
+	Bounds    []float64 `yaml:"bounds"`
+	ValueType string    `yaml:"value_type"`
+}
+
+// LoadManifest reads and validates the manifest at path
+func LoadManifest(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks that the manifest holds at least one instrument and that
+// every instrument is valid and uniquely named
+func (m Manifest) Validate() error {
+	if len(m.Instruments) == 0 {
+		return fmt.Errorf("manifest must define at least one instrument")
+	}
+	seen := make(map[string]bool, len(m.Instruments))
+	for i, spec := range m.Instruments {
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("invalid instrument at index %d: %w", i, err)
+		}
+		if seen[spec.Name] {
+			return fmt.Errorf("invalid instrument at index %d: duplicate name %s", i, spec.Name)
+		}
+		seen[spec.Name] = true
+	}
+	return nil
+}
+
+// Validate checks the instrument spec is complete and consistent with its type
+func (s InstrumentSpec) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name must be set")
+	}
+	switch s.Type {
+	case InstrumentSum:
+	case InstrumentGauge:
+		if s.Min > s.Max {
+			return fmt.Errorf("%s: min %v must be less than or equal to max %v", s.Name, s.Min, s.Max)
+		}
+	case InstrumentHistogram:
+		if !sort.Float64sAreSorted(s.Bounds) {
+			return fmt.Errorf("%s: bounds must be in increasing order", s.Name)
+		}
+		if s.ValueType != "" && s.ValueType != ValueTypeFloat && s.ValueType != ValueTypeInt {
+			return fmt.Errorf("%s: value_type must be one of: float, int, got %s", s.Name, s.ValueType)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported type %q, use one of: sum, gauge, histogram", s.Name, s.Type)
+	}
+	return nil
+}
+
+// attributes returns the spec attributes sorted by key
+func (s InstrumentSpec) attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(s.Attributes))
+	for k, v := range s.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
+
+// workerFunc returns the worker generating the instrument described by the spec
+func (s InstrumentSpec) workerFunc(mp metric.MeterProvider, temporality metricdata.Temporality, c Config, logger *zap.Logger) WorkerFunc {
+	unit := s.Unit
+	if unit == "" {
+		unit = "1"
+	}
+
+	switch s.Type {
+	case InstrumentGauge:
+		return gauge(mp, GaugeConfig{
+			Name:        s.Name,
+			Description: s.Description,
+			Unit:        unit,
+			Attributes:  s.attributes(),
+			Min:         s.Min,
+			Max:         s.Max,
+			Temporality: temporality,
+		}, c, logger)
+	case InstrumentHistogram:
+		valueType := s.ValueType
+		if valueType == "" {
+			valueType = ValueTypeFloat
+		}
+		return histogram(mp, HistogramConfig{
+			Name:         s.Name,
+			Description:  s.Description,
+			Unit:         unit,
+			Attributes:   s.attributes(),
+			Temporality:  temporality,
+			Bounds:       s.Bounds,
+			RecordMinMax: true,
+			ValueType:    valueType,
+		}, c, logger)
+	default:
+		return sum(mp, SumConfig{
+			Name:        s.Name,
+			Description: s.Description,
+			Unit:        unit,
+			Attributes:  s.attributes(),
+			Temporality: temporality,
+			IsMonotonic: s.Monotonic == nil || *s.Monotonic,
+		}, c, logger)
+	}
+}
+
+// SimulateManifest generates every instrument in the manifest concurrently on the
+// shared meter provider until the run ends
+func SimulateManifest(ctx context.Context, mp metric.MeterProvider, m *Manifest, temporality metricdata.Temporality, conf *Config, logger *zap.Logger) error {
+	if err := NewManifestGenerator(mp, m, temporality, conf, logger).Run(ctx); err != nil {
+		logger.Error("failed to run manifest", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// manifest runs the workers of every instrument in the manifest side by side
+func manifest(mp metric.MeterProvider, m *Manifest, temporality metricdata.Temporality, c Config, logger *zap.Logger) WorkerFunc {
+	workers := make([]WorkerFunc, 0, len(m.Instruments))
+	for _, spec := range m.Instruments {
+		workers = append(workers, spec.workerFunc(mp, temporality, c, logger))
+	}
+
+	return func(ctx context.Context) {
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+			go func(w WorkerFunc) {
+				defer wg.Done()
+				w(ctx)
+			}(w)
+		}
+		wg.Wait()
+	}
+}
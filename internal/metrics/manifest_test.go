@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+// writeManifest writes content to a manifest file in a temporary directory
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			content: `instruments:
+  - name: requests
+    type: sum
+    unit: "{request}"
+  - name: temperature
+    type: gauge
+    min: 10
+    max: 30
+  - name: latency
+    type: histogram
+    bounds: [1, 5, 10]
+    value_type: int
+`,
+			want: 3,
+		},
+		{name: "empty", content: "instruments: []\n", wantErr: true},
+		{name: "not yaml", content: "instruments: [", wantErr: true},
+		{name: "missing name", content: "instruments:\n  - type: sum\n", wantErr: true},
+		{name: "unsupported type", content: "instruments:\n  - name: a\n    type: summary\n", wantErr: true},
+		{name: "duplicate name", content: "instruments:\n  - name: a\n    type: sum\n  - name: a\n    type: gauge\n", wantErr: true},
+		{name: "gauge min above max", content: "instruments:\n  - name: a\n    type: gauge\n    min: 5\n    max: 1\n", wantErr: true},
+		{name: "unsorted bounds", content: "instruments:\n  - name: a\n    type: histogram\n    bounds: [5, 1]\n", wantErr: true},
+		{name: "unsupported value type", content: "instruments:\n  - name: a\n    type: histogram\n    value_type: decimal\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := LoadManifest(writeManifest(t, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(m.Instruments) != tt.want {
+				t.Errorf("LoadManifest() = %d instruments, want %d", len(m.Instruments), tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadManifest(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadManifest() succeeded with a missing file, want an error")
+	}
+}
+
+func TestManifestCreatesInstruments(t *testing.T) {
+	m, err := LoadManifest(writeManifest(t, `instruments:
+  - name: requests
+    type: sum
+    description: Requests served
+    unit: "{request}"
+  - name: temperature
+    type: gauge
+    unit: Cel
+    min: 10
+    max: 30
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	conf := &Config{ServiceName: "test", NumMetrics: 1, Rate: 1, TotalDuration: time.Hour, Clock: fake}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = SimulateManifest(ctx, mp, m, metricdata.CumulativeTemporality, conf, zap.NewNop())
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	want := map[string]string{"requests": "{request}", "temperature": "Cel"}
+	var got map[string]metricdata.Metrics
+	waitFor(t, "both instruments to be recorded", func() bool {
+		fake.Advance(time.Second)
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatal(err)
+		}
+		got = map[string]metricdata.Metrics{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				got[metric.Name] = metric
+			}
+		}
+		return len(got) == len(want)
+	})
+
+	for name, unit := range want {
+		if got[name].Unit != unit {
+			t.Errorf("%s unit = %q, want %q", name, got[name].Unit, unit)
+		}
+	}
+	if got["requests"].Description != "Requests served" {
+		t.Errorf("requests description = %q, want %q", got["requests"].Description, "Requests served")
+	}
+	if _, ok := got["requests"].Data.(metricdata.Sum[int64]); !ok {
+		t.Errorf("requests is a %T, want a sum", got["requests"].Data)
+	}
+	if _, ok := got["temperature"].Data.(metricdata.Gauge[float64]); !ok {
+		t.Errorf("temperature is a %T, want a gauge", got["temperature"].Data)
+	}
+}
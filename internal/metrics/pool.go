@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"math/rand"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// withPool returns attrs extended with AttributesPerPoint attributes chosen at random
+// from AttributePool, so each recording carries a different subset of the pool.
+func (c Config) withPool(r *rand.Rand, attrs []attribute.KeyValue) []attribute.KeyValue {
+	if c.AttributesPerPoint <= 0 || len(c.AttributePool) == 0 {
+		return attrs
+	}
+
+	n := c.AttributesPerPoint
+	if n > len(c.AttributePool) {
+		n = len(c.AttributePool)
+	}
+
+	result := make([]attribute.KeyValue, 0, len(attrs)+n)
+	result = append(result, attrs...)
+	for _, i := range r.Perm(len(c.AttributePool))[:n] {
+		result = append(result, c.AttributePool[i])
+	}
+	return result
+}
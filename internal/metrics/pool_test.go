@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestWithPool(t *testing.T) {
+	base := []attribute.KeyValue{attribute.String("service", "otelgen")}
+	pool := []attribute.KeyValue{
+		attribute.String("region", "eu"),
+		attribute.String("zone", "a"),
+		attribute.Int("shard", 3),
+		attribute.Bool("canary", true),
+		attribute.String("tier", "gold"),
+	}
+
+	tests := []struct {
+		name  string
+		pool  []attribute.KeyValue
+		n     int
+		wantN int
+	}{
+		{name: "disabled", pool: pool, n: 0, wantN: 0},
+		{name: "empty pool", n: 2, wantN: 0},
+		{name: "one", pool: pool, n: 1, wantN: 1},
+		{name: "three", pool: pool, n: 3, wantN: 3},
+		{name: "whole pool", pool: pool, n: len(pool), wantN: len(pool)},
+		{name: "more than the pool", pool: pool, n: len(pool) + 2, wantN: len(pool)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{AttributePool: tt.pool, AttributesPerPoint: tt.n}
+			r := rand.New(rand.NewSource(1))
+			replay := rand.New(rand.NewSource(1))
+			subsets := map[attribute.Distinct]bool{}
+			for point := 0; point < 50; point++ {
+				attrs := c.withPool(r, base)
+				if !reflect.DeepEqual(attrs[:len(base)], base) {
+					t.Fatalf("point %d attributes = %v, want them to start with %v", point, attrs, base)
+				}
+				drawn := attrs[len(base):]
+				if len(drawn) != tt.wantN {
+					t.Fatalf("point %d drew %d attributes, want %d", point, len(drawn), tt.wantN)
+				}
+				keys := map[attribute.Key]bool{}
+				for _, kv := range drawn {
+					if !containsKeyValue(tt.pool, kv) {
+						t.Errorf("point %d drew %v, which isn't in the pool", point, kv)
+					}
+					if keys[kv.Key] {
+						t.Errorf("point %d drew %s twice", point, kv.Key)
+					}
+					keys[kv.Key] = true
+				}
+				if again := c.withPool(replay, base); !reflect.DeepEqual(again, attrs) {
+					t.Errorf("point %d drew %v, then %v from the same seed", point, attrs, again)
+				}
+				set := attribute.NewSet(drawn...)
+				subsets[set.Equivalent()] = true
+			}
+			if tt.wantN > 0 && tt.wantN < len(tt.pool) && len(subsets) < 2 {
+				t.Errorf("every point drew the same subset of the pool")
+			}
+		})
+	}
+}
+
+func containsKeyValue(kvs []attribute.KeyValue, kv attribute.KeyValue) bool {
+	for _, candidate := range kvs {
+		if candidate == kv {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"math/rand"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// withRow returns attrs extended with the attribute row for the given record number,
+// cycling through AttributeRows so each row becomes its own time series.
+func (c Config) withRow(attrs []attribute.KeyValue, record int64) []attribute.KeyValue {
+	if len(c.AttributeRows) == 0 {
+		return attrs
+	}
+
+	row := c.AttributeRows[record%int64(len(c.AttributeRows))]
+	result := make([]attribute.KeyValue, 0, len(attrs)+len(row))
+	result = append(result, attrs...)
+	return append(result, row...)
+}
+
+// recordAttributes returns attrs extended with the drifting, row and pooled
+// attributes configured for the given record number.
+func (c Config) recordAttributes(r *rand.Rand, attrs []attribute.KeyValue, record int64) []attribute.KeyValue {
+	return c.withPool(r, c.withRow(c.withDrift(attrs, record), record))
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+func TestSumEmitsOneSeriesPerRow(t *testing.T) {
+	rows := [][]attribute.KeyValue{
+		{attribute.String("region", "eu"), attribute.String("tier", "gold")},
+		{attribute.String("region", "us"), attribute.String("tier", "silver")},
+		{attribute.String("region", "ap"), attribute.String("tier", "bronze")},
+	}
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	conf := &Config{
+		ServiceName:   "test",
+		NumMetrics:    1,
+		Rate:          1,
+		TotalDuration: 10 * time.Second,
+		Clock:         fake,
+		AttributeRows: rows,
+	}
+	sc := SumConfig{Name: "test.sum", Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		SimulateSum(ctx, mp, sc, conf, zap.NewNop())
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Two passes through the rows, recording 1 to 6
+	waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+	var total int64
+	for i := int64(1); i <= 2*int64(len(rows)); i++ {
+		total += i
+		fake.Advance(time.Second)
+		waitFor(t, "the sum to be recorded", func() bool { return collectSum(t, reader, sc.Name) == total })
+	}
+
+	// Row n was recorded with n+1 and n+4
+	want := map[attribute.Distinct]int64{}
+	for n, row := range rows {
+		set := attribute.NewSet(row...)
+		want[set.Equivalent()] = int64(2*n + 5)
+	}
+	dps := collectMetric(t, reader, sc.Name).Data.(metricdata.Sum[int64]).DataPoints
+	if len(dps) != len(rows) {
+		t.Fatalf("got %d series, want one per row: %v", len(dps), dps)
+	}
+	for _, dp := range dps {
+		key := dp.Attributes.Equivalent()
+		if got, ok := want[key]; !ok || dp.Value != got {
+			t.Errorf("series %v = %d, want one of the rows with its total", dp.Attributes.ToSlice(), dp.Value)
+		}
+		delete(want, key)
+	}
+}
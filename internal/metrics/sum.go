@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -19,11 +20,13 @@ type SumConfig struct {
 	Attributes  []attribute.KeyValue
 	Temporality metricdata.Temporality
 	IsMonotonic bool
+	// ResetInterval drops the cumulative total back to zero this often, simulating a
+	// restarted process, 0 disables it
+	ResetInterval time.Duration
 }
 
-func SimulateSum(mp metric.MeterProvider, sumConfig SumConfig, conf *Config, logger *zap.Logger) {
-	c := *conf
-	err := run(conf, logger, sum(mp, sumConfig, c, logger))
+func SimulateSum(ctx context.Context, mp metric.MeterProvider, sumConfig SumConfig, conf *Config, logger *zap.Logger) {
+	err := NewSumGenerator(mp, sumConfig, conf, logger).Run(ctx)
 	if err != nil {
 		logger.Error("failed to run sum", zap.Error(err))
 	}
@@ -31,50 +34,150 @@ func SimulateSum(mp metric.MeterProvider, sumConfig SumConfig, conf *Config, log
 
 func sum(mp metric.MeterProvider, sc SumConfig, c Config, logger *zap.Logger) WorkerFunc {
 	return func(ctx context.Context) {
-		name := fmt.Sprintf("%v.metrics.sum", c.ServiceName)
+		name := sc.Name
+		if name == "" {
+			name = fmt.Sprintf("%v.metrics.sum", c.ServiceName)
+		}
 		logger.Debug("generating sum", zap.String("name", name))
-		counter, _ := mp.Meter(c.ServiceName).Int64Counter(
-			name,
-			metric.WithUnit(sc.Unit),
-			metric.WithDescription(sc.Description),
-		)
 
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		var exemplars []Exemplar
+		// The SDK's cumulative state can't be reset, so resetting sums observe totals
+		// kept here instead
+		var totals *resettableSum
+		var add func(ctx context.Context, value int64, attrs []attribute.KeyValue)
+		if sc.ResetInterval > 0 {
+			totals = &resettableSum{series: map[attribute.Distinct]*seriesTotal{}}
+			counter, err := newObservableSum(mp.Meter(c.ServiceName), name, sc)
+			if err != nil {
+				logger.Error("failed to create sum", zap.Error(err))
+				return
+			}
+			_, err = mp.Meter(c.ServiceName).RegisterCallback(func(_ context.Context, o metric.Observer) error {
+				totals.observe(o, counter)
+				return nil
+			}, counter)
+			if err != nil {
+				logger.Error("failed to register callback", zap.Error(err))
+				return
+			}
+			add = func(_ context.Context, value int64, attrs []attribute.KeyValue) {
+				totals.add(value, attrs)
+			}
+		} else {
+			counter, err := mp.Meter(c.ServiceName).Int64Counter(
+				name,
+				metric.WithUnit(sc.Unit),
+				metric.WithDescription(sc.Description),
+			)
+			if err != nil {
+				logger.Error("failed to create sum", zap.Error(err))
+				return
+			}
+			add = func(ctx context.Context, value int64, attrs []attribute.KeyValue) {
+				counter.Add(ctx, value, metric.WithAttributes(attrs...))
+			}
+		}
+
+		clk := c.clock()
+		r := rand.New(rand.NewSource(clk.Now().UnixNano()))
+		exemplars := newExemplarReservoir(r, c.ExemplarCount)
 		var i int64
-		ticker := time.NewTicker(time.Duration(c.Rate) * time.Second)
+		runStart := clk.Now()
+		lastReset := runStart
+		ticker := clk.NewTicker(c.interval(runStart))
 		defer ticker.Stop()
-
-		var cancel context.CancelFunc
-		if c.TotalDuration > 0 {
-			ctx, cancel = context.WithTimeout(ctx, c.TotalDuration)
-			defer cancel()
-		}
+		deadline := c.deadline()
 
 		for {
 			select {
 			case <-ctx.Done():
 				logger.Info("Stopping sum generation due to context cancellation")
 				return
-			case <-ticker.C:
+			case <-deadline:
+				logger.Info("Stopping sum generation as the duration has elapsed")
+				return
+			case <-ticker.C():
+				if totals != nil && clk.Now().Sub(lastReset) >= sc.ResetInterval {
+					totals.reset()
+					lastReset = clk.Now()
+					i = 0
+					logger.Info("resetting sum", zap.String("name", name))
+				}
 				i++
 				value := i
 				if !sc.IsMonotonic {
 					value = (value % 100) - 50 // Oscillate between -50 and 49
 				}
-				exemplar := generateExemplar(r, float64(value), time.Now())
-				exemplars = append(exemplars, exemplar)
-				if len(exemplars) > 10 {
-					exemplars = exemplars[1:]
-				}
+				exemplar := generateExemplar(r, float64(value), clk.Now())
+				exemplars.offer(exemplar)
 				logger.Info("generating",
 					zap.String("name", name),
 					zap.Int64("value", value),
 					zap.String("temporality", sc.Temporality.String()),
-					zap.Int("exemplars_count", len(exemplars)),
+					zap.Int("exemplars_count", exemplars.len()),
 				)
-				counter.Add(ctx, value, metric.WithAttributes(sc.Attributes...))
+				add(ctx, value, c.recordAttributes(r, sc.Attributes, i-1))
+				ticker.Reset(c.jitter(r, c.interval(runStart)))
 			}
 		}
 	}
 }
+
+// newObservableSum returns the instrument a resetting sum is observed through, an
+// up-down counter unless the sum is monotonic
+func newObservableSum(meter metric.Meter, name string, sc SumConfig) (metric.Int64Observable, error) {
+	if sc.IsMonotonic {
+		return meter.Int64ObservableCounter(
+			name,
+			metric.WithUnit(sc.Unit),
+			metric.WithDescription(sc.Description),
+		)
+	}
+	return meter.Int64ObservableUpDownCounter(
+		name,
+		metric.WithUnit(sc.Unit),
+		metric.WithDescription(sc.Description),
+	)
+}
+
+// resettableSum holds the cumulative total of every series of an observable sum, so
+// they can drop back to zero as a restarted process's would
+type resettableSum struct {
+	mu     sync.Mutex
+	series map[attribute.Distinct]*seriesTotal
+}
+
+type seriesTotal struct {
+	attrs attribute.Set
+	value int64
+}
+
+// add adds value to the total of the series with attrs
+func (s *resettableSum) add(value int64, attrs []attribute.KeyValue) {
+	set := attribute.NewSet(attrs...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.series[set.Equivalent()]
+	if !ok {
+		t = &seriesTotal{attrs: set}
+		s.series[set.Equivalent()] = t
+	}
+	t.value += value
+}
+
+// reset drops every total to zero, keeping the series so the drop is observed
+func (s *resettableSum) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.series {
+		t.value = 0
+	}
+}
+
+// observe reports the total of every series to o
+func (s *resettableSum) observe(o metric.Observer, counter metric.Int64Observable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.series {
+		o.ObserveInt64(counter, t.value, metric.WithAttributeSet(t.attrs))
+	}
+}
@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	"github.com/krzko/otelgen/internal/rateprofile"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// collectSum returns the total of the int64 sum named name, 0 before it's recorded
+func collectSum(t *testing.T, reader sdkmetric.Reader, name string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				total += dp.Value
+			}
+		}
+	}
+	return total
+}
+
+// waitFor polls cond until it holds, failing the test after a second
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSumFollowsClock(t *testing.T) {
+	tests := []struct {
+		name      string
+		resetting time.Duration
+		// want is the total after each advance of one second. No two in a row are
+		// equal, so waiting for each shows the tick before it was recorded.
+		want []int64
+	}{
+		{"cumulative", 0, []int64{1, 3, 6, 10}},
+		{"reset every three seconds", 3 * time.Second, []int64{1, 3, 1, 3, 6, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			conf := &Config{
+				ServiceName:   "test",
+				NumMetrics:    1,
+				Rate:          1,
+				TotalDuration: 10 * time.Second,
+				Clock:         fake,
+			}
+			sc := SumConfig{
+				Name:          "test.sum",
+				Temporality:   metricdata.CumulativeTemporality,
+				IsMonotonic:   true,
+				ResetInterval: tt.resetting,
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				SimulateSum(context.Background(), mp, sc, conf, zap.NewNop())
+			}()
+
+			// The worker's deadline, the simulator's deadline and its ticker
+			waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+			for _, want := range tt.want {
+				fake.Advance(time.Second)
+				waitFor(t, "the sum to be recorded", func() bool { return collectSum(t, reader, sc.Name) == want })
+			}
+
+			select {
+			case <-done:
+				t.Fatal("the simulator stopped before its duration elapsed")
+			default:
+			}
+			fake.Advance(10 * time.Second)
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("the simulator didn't stop once its duration elapsed")
+			}
+		})
+	}
+}
+
+func TestSumLinearProfileRecordsEarly(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	conf := &Config{
+		ServiceName:   "test",
+		NumMetrics:    1,
+		Rate:          1,
+		RateProfile:   rateprofile.Linear,
+		TotalDuration: 10 * time.Second,
+		Clock:         fake,
+	}
+	sc := SumConfig{Name: "test.sum", Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go SimulateSum(ctx, mp, sc, conf, zap.NewNop())
+
+	// The ramp starts at the profile's minimum rate, a hundredth of the configured
+	// one, which would hold the first point back well past the end of the run
+	waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+	fake.Advance(5 * time.Second)
+	waitFor(t, "the first point", func() bool { return collectSum(t, reader, sc.Name) == 1 })
+}
+
+func TestSumGeneratingLogsFollowLevel(t *testing.T) {
+	tests := []struct {
+		level zapcore.Level
+		want  int
+	}{
+		{level: zap.InfoLevel, want: 3},
+		{level: zap.WarnLevel},
+		{level: zap.ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			conf := &Config{ServiceName: "test", NumMetrics: 1, Rate: 1, TotalDuration: 10 * time.Second, Clock: fake}
+			sc := SumConfig{Name: "test.sum", Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+			core, logs := observer.New(tt.level)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go SimulateSum(ctx, mp, sc, conf, zap.New(core))
+
+			waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+			// The i-th record adds i
+			for _, want := range []int64{1, 3, 6} {
+				fake.Advance(time.Second)
+				waitFor(t, "the sum to be recorded", func() bool { return collectSum(t, reader, sc.Name) == want })
+			}
+
+			if got := logs.FilterMessage("generating").Len(); got != tt.want {
+				t.Errorf("got %d generating entries at %s, want %d", got, tt.level, tt.want)
+			}
+		})
+	}
+}
+
+func TestSumResets(t *testing.T) {
+	tests := []struct {
+		name      string
+		monotonic bool
+		resetting time.Duration
+		// restart is the total right after each reset, that of a single point
+		restart int64
+	}{
+		{name: "every three seconds", monotonic: true, resetting: 3 * time.Second, restart: 1},
+		{name: "every four seconds", monotonic: true, resetting: 4 * time.Second, restart: 1},
+		{name: "not monotonic", resetting: 3 * time.Second, restart: -49},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			conf := &Config{ServiceName: "test", NumMetrics: 1, Rate: 1, TotalDuration: time.Minute, Clock: fake}
+			sc := SumConfig{
+				Name:          "test.sum",
+				Temporality:   metricdata.CumulativeTemporality,
+				IsMonotonic:   tt.monotonic,
+				ResetInterval: tt.resetting,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go SimulateSum(ctx, mp, sc, conf, zap.NewNop())
+			waitFor(t, "the simulator to start", func() bool { return fake.Waiters() == 3 })
+
+			// The i-th point since the last reset adds i, or i-50 when not monotonic
+			var want, i int64
+			for step := 1; step <= int(3*tt.resetting/time.Second); step++ {
+				fake.Advance(time.Second)
+				if step%int(tt.resetting/time.Second) == 0 {
+					waitFor(t, fmt.Sprintf("the reset after %ds", step), func() bool { return collectSum(t, reader, sc.Name) == tt.restart })
+					want, i = tt.restart, 1
+					continue
+				}
+				i++
+				if tt.monotonic {
+					want += i
+				} else {
+					want += i%100 - 50
+				}
+				waitFor(t, fmt.Sprintf("the total after %ds", step), func() bool { return collectSum(t, reader, sc.Name) == want })
+			}
+
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(context.Background(), &rm); err != nil {
+				t.Fatal(err)
+			}
+			data := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+			if data.IsMonotonic != tt.monotonic || data.Temporality != metricdata.CumulativeTemporality {
+				t.Errorf("exported a %s sum with monotonic = %t, want a cumulative one with %t", data.Temporality, data.IsMonotonic, tt.monotonic)
+			}
+		})
+	}
+}
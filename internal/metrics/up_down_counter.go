@@ -4,23 +4,26 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
-	"time"
 
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap"
 )
 
+type UpDownCounterConfig struct {
+	Temporality metricdata.Temporality
+}
+
 // SimulateUpDownCounter demonstrates how to measure numbers that can go up and down
-func SimulateUpDownCounter(mp metric.MeterProvider, conf *Config, logger *zap.Logger) {
-	c := *conf
-	err := run(conf, logger, upDownCounter(mp, c, logger))
+func SimulateUpDownCounter(ctx context.Context, mp metric.MeterProvider, udcConfig UpDownCounterConfig, conf *Config, logger *zap.Logger) {
+	err := NewUpDownCounterGenerator(mp, udcConfig, conf, logger).Run(ctx)
 	if err != nil {
 		logger.Error("failed to run up-down-counter", zap.Error(err))
 	}
 }
 
 // upDownCounter generates a up down counter metric
-func upDownCounter(mp metric.MeterProvider, c Config, logger *zap.Logger) WorkerFunc {
+func upDownCounter(mp metric.MeterProvider, udc UpDownCounterConfig, c Config, logger *zap.Logger) WorkerFunc {
 	return func(ctx context.Context) {
 		name := fmt.Sprintf("%v.metrics.up_down_counter", c.ServiceName)
 		counter, _ := mp.Meter(c.ServiceName).Int64UpDownCounter(
@@ -31,31 +34,24 @@ func upDownCounter(mp metric.MeterProvider, c Config, logger *zap.Logger) Worker
 
 		if c.TotalDuration > 0 {
 			logger.Info("generation duration", zap.Float64("seconds", c.TotalDuration.Seconds()))
+		}
+		clk := c.clock()
+		deadline := c.deadline()
 
-		loop:
-			for timeout := time.After(c.TotalDuration); ; {
-				select {
-				case <-timeout:
-					break loop
-				default:
-				}
-				logger.Info("generating", zap.String("name", name))
-				if rand.Float64() >= 0.5 {
-					counter.Add(ctx, +1)
-				} else {
-					counter.Add(ctx, -1)
-				}
-				time.Sleep(time.Duration(c.Rate) * time.Second)
+		runStart := clk.Now()
+		for {
+			logger.Info("generating", zap.String("name", name), zap.String("temporality", udc.Temporality.String()))
+			if rand.Float64() >= 0.5 {
+				counter.Add(ctx, +1)
+			} else {
+				counter.Add(ctx, -1)
 			}
-		} else {
-			for {
-				logger.Info("generating", zap.String("name", name))
-				if rand.Float64() >= 0.5 {
-					counter.Add(ctx, +1)
-				} else {
-					counter.Add(ctx, -1)
-				}
-				time.Sleep(time.Duration(c.Rate) * time.Second)
+			select {
+			case <-ctx.Done():
+				return
+			case <-deadline:
+				return
+			case <-clk.After(c.interval(runStart)):
 			}
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/krzko/otelgen/internal/clock"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
@@ -18,6 +19,7 @@ type Worker struct {
 	totalDuration  time.Duration   // how long to run the test for (overrides `numMetrics`)
 	limitPerSecond rate.Limit      // how many metrics per second to generate
 	wg             *sync.WaitGroup // notify when done
+	clock          clock.Clock
 	logger         *zap.Logger
 }
 
@@ -28,19 +30,11 @@ func NewWorker(c *Config, logger *zap.Logger) *Worker {
 		totalDuration:  c.TotalDuration,
 		limitPerSecond: rate.Limit(c.Rate),
 		wg:             &sync.WaitGroup{},
+		clock:          c.clock(),
 		logger:         logger,
 	}
 }
 
-// run is a function that runs a worker
-func run(c *Config, logger *zap.Logger, workerFunc WorkerFunc) error {
-	w := NewWorker(c, logger)
-	if err := w.Run(context.Background(), workerFunc); err != nil {
-		return fmt.Errorf("failed to run worker: %w", err)
-	}
-	return nil
-}
-
 // Run runs the worker
 func (w *Worker) Run(ctx context.Context, workerFunc WorkerFunc) error {
 	if w.totalDuration == 0 {
@@ -53,11 +47,13 @@ func (w *Worker) Run(ctx context.Context, workerFunc WorkerFunc) error {
 
 	running := atomic.NewBool(true)
 	errChan := make(chan error, 1)
+	done := make(chan struct{})
 	for i := 0; i < 1; i++ {
 		w.wg.Add(1)
 
 		go func() {
 			defer w.wg.Done()
+			defer close(done)
 			workerFunc(ctx)
 		}()
 	}
@@ -65,7 +61,11 @@ func (w *Worker) Run(ctx context.Context, workerFunc WorkerFunc) error {
 	if w.totalDuration > 0 {
 		w.logger.Info("generation duration", zap.Float64("seconds", w.totalDuration.Seconds()))
 		w.logger.Info("generation rate", zap.Float64("per second", float64(w.limitPerSecond)))
-		time.Sleep(w.totalDuration)
+		select {
+		case <-w.clock.After(w.totalDuration):
+		case <-ctx.Done():
+		case <-done:
+		}
 		running.Store(false)
 	}
 	w.wg.Wait()
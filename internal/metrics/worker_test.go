@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+func TestSimulateReturnsOnCancel(t *testing.T) {
+	tests := []struct {
+		name     string
+		simulate func(ctx context.Context, mp metric.MeterProvider, conf *Config)
+	}{
+		{"counter", func(ctx context.Context, mp metric.MeterProvider, conf *Config) {
+			SimulateCounter(ctx, mp, conf, zap.NewNop())
+		}},
+		{"gauge", func(ctx context.Context, mp metric.MeterProvider, conf *Config) {
+			_ = SimulateGauge(ctx, mp, GaugeConfig{Name: "test.gauge", Max: 1}, conf, zap.NewNop())
+		}},
+		{"histogram", func(ctx context.Context, mp metric.MeterProvider, conf *Config) {
+			hc := HistogramConfig{Name: "test.histogram", Bounds: []float64{1, 10, 100}}
+			SimulateHistogram(ctx, mp, hc, conf, zap.NewNop())
+		}},
+		{"exponential histogram", func(ctx context.Context, mp metric.MeterProvider, conf *Config) {
+			ec := ExponentialHistogramConfig{Name: "test.exponential_histogram", Scale: 2, MaxSize: 160}
+			SimulateExponentialHistogram(ctx, mp, ec, conf, zap.NewNop())
+		}},
+		{"sum", func(ctx context.Context, mp metric.MeterProvider, conf *Config) {
+			sc := SumConfig{Name: "test.sum", Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+			SimulateSum(ctx, mp, sc, conf, zap.NewNop())
+		}},
+		{"up down counter", func(ctx context.Context, mp metric.MeterProvider, conf *Config) {
+			SimulateUpDownCounter(ctx, mp, UpDownCounterConfig{}, conf, zap.NewNop())
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader()))
+			// An hour-long run only returns in time if cancelling ctx stops it
+			conf := &Config{ServiceName: "test", NumMetrics: 1, Rate: 1, TotalDuration: time.Hour}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				tt.simulate(ctx, mp, conf)
+			}()
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("did not return within a second of cancelling the context")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Package partialsuccess picks out the OTLP partial success responses the exporters
+// report. A receiver that accepts an export but drops some of its items answers with
+// a partial success, which the exporters pass to the OTel error handler rather than
+// returning from Export, so they are recovered from there.
+package partialsuccess
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+// Error is a partial success response, the items of signal a receiver rejected
+type Error struct {
+	Signal   string
+	Rejected int64
+	Message  string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// pattern matches the errors the OTLP exporters hand to otel.Handle, e.g.
+// "OTLP partial success: quota exceeded (3 spans rejected)"
+var pattern = regexp.MustCompile(`^OTLP partial success: .* \((\d+) (spans|metric data points|log records) rejected\)$`)
+
+// signals maps the kind of item in a partial success onto its signal
+var signals = map[string]string{
+	"spans":              "traces",
+	"metric data points": "metrics",
+	"log records":        "logs",
+}
+
+// Parse returns the partial success err describes, reporting false for any other error
+func Parse(err error) (*Error, bool) {
+	var ps *Error
+	if errors.As(err, &ps) {
+		return ps, true
+	}
+	m := pattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil, false
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &Error{Signal: signals[m[2]], Rejected: n, Message: m[0]}, true
+}
+
+// Handler logs the errors reported through otel.Handle, passing every partial
+// success to rejected as well
+type Handler struct {
+	logger   *zap.Logger
+	rejected func(*Error)
+}
+
+var _ otel.ErrorHandler = (*Handler)(nil)
+
+// NewHandler returns an error handler that passes partial successes to rejected
+func NewHandler(logger *zap.Logger, rejected func(*Error)) *Handler {
+	return &Handler{logger: logger, rejected: rejected}
+}
+
+func (h *Handler) Handle(err error) {
+	ps, ok := Parse(err)
+	if !ok {
+		h.logger.Error("opentelemetry error", zap.Error(err))
+		return
+	}
+	h.logger.Warn("receiver rejected part of an export",
+		zap.String("signal", ps.Signal),
+		zap.Int64("rejected", ps.Rejected),
+		zap.String("message", ps.Message),
+	)
+	h.rejected(ps)
+}
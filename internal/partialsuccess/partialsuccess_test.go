@@ -0,0 +1,119 @@
+package partialsuccess
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krzko/otelgen/internal/summary"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   *Error
+		wantOK bool
+	}{
+		{
+			name:   "spans",
+			err:    errors.New("OTLP partial success: quota exceeded (3 spans rejected)"),
+			want:   &Error{Signal: "traces", Rejected: 3, Message: "OTLP partial success: quota exceeded (3 spans rejected)"},
+			wantOK: true,
+		},
+		{
+			name:   "metric data points",
+			err:    errors.New("OTLP partial success: empty message (12 metric data points rejected)"),
+			want:   &Error{Signal: "metrics", Rejected: 12, Message: "OTLP partial success: empty message (12 metric data points rejected)"},
+			wantOK: true,
+		},
+		{
+			name:   "log records",
+			err:    fmt.Errorf("OTLP partial success: %s (%d %s rejected)", "too large", 1, "log records"),
+			want:   &Error{Signal: "logs", Rejected: 1, Message: "OTLP partial success: too large (1 log records rejected)"},
+			wantOK: true,
+		},
+		{
+			name:   "wrapped",
+			err:    fmt.Errorf("export: %w", &Error{Signal: "logs", Rejected: 2, Message: "dropped"}),
+			want:   &Error{Signal: "logs", Rejected: 2, Message: "dropped"},
+			wantOK: true,
+		},
+		{name: "other error", err: errors.New("context deadline exceeded")},
+		{name: "unknown kind", err: errors.New("OTLP partial success: x (3 profiles rejected)")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("Parse() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && *got != *tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+// mockExporter accepts every export but, like the OTLP exporters, reports the
+// spans its receiver dropped to the OTel error handler
+type mockExporter struct {
+	rejected int
+}
+
+func (e *mockExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	if e.rejected > 0 {
+		otel.Handle(fmt.Errorf("OTLP partial success: quota exceeded (%d spans rejected)", e.rejected))
+	}
+	return nil
+}
+
+func (e *mockExporter) Shutdown(context.Context) error { return nil }
+
+func TestHandlerReportsRejected(t *testing.T) {
+	defer func(h otel.ErrorHandler) { otel.SetErrorHandler(h) }(otel.GetErrorHandler())
+
+	s := summary.New("traces single")
+	otel.SetErrorHandler(NewHandler(zap.NewNop(), func(ps *Error) {
+		s.Reject(ps.Signal, ps.Rejected, ps)
+	}))
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(&mockExporter{rejected: 2}))
+	for i := 0; i < 3; i++ {
+		_, span := tp.Tracer("test").Start(context.Background(), "span")
+		span.End()
+	}
+	otel.Handle(errors.New("unrelated failure"))
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := s.WriteFile(path, summary.Completed, false); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r summary.Report
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Rejected != 6 {
+		t.Errorf("rejected = %d, want the 6 spans rejected over 3 exports", r.Rejected)
+	}
+	if traces, ok := r.Signals["traces"]; !ok || traces.Rejected != 6 {
+		t.Errorf("signals = %v, want 6 traces rejected", r.Signals)
+	}
+	if len(r.Errors) != 1 {
+		t.Errorf("errors = %q, want only the partial success message", r.Errors)
+	}
+}
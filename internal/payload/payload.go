@@ -0,0 +1,23 @@
+package payload
+
+import (
+	"math/rand"
+)
+
+// Key is the attribute key under which the synthetic payload is attached
+const Key = "otelgen.payload"
+
+// MaxSize is the largest payload in bytes, kept under the default 4MiB gRPC message limit
+const MaxSize = 1 << 20
+
+const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// New returns a random alphanumeric string of size bytes. It's generated once and
+// shared by every span or log record so padding doesn't cost an allocation per item.
+func New(size int) string {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(b)
+}
@@ -0,0 +1,21 @@
+package payload
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, size := range []int{0, 1, 1024, MaxSize} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			got := New(size)
+			if len(got) != size {
+				t.Errorf("len(New(%d)) = %d", size, len(got))
+			}
+			if i := strings.IndexFunc(got, func(r rune) bool { return !strings.ContainsRune(alphabet, r) }); i >= 0 {
+				t.Errorf("New(%d) has %q at %d, want only alphanumerics", size, got[i], i)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package progress
+
+import (
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	"go.uber.org/zap"
+)
+
+// Start logs a heartbeat every interval reporting how many items have been
+// emitted so far, until the returned stop function is called. count reports
+// the running total and total is the planned run duration, 0 if unbounded.
+// An interval of 0 disables reporting.
+func Start(logger *zap.Logger, item string, interval, total time.Duration, count func() int64) (stop func()) {
+	return StartClock(clock.Real(), logger, item, interval, total, count)
+}
+
+// StartClock is Start with the heartbeat and elapsed time taken from clk
+func StartClock(clk clock.Clock, logger *zap.Logger, item string, interval, total time.Duration, count func() int64) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	start := clk.Now()
+	ticker := clk.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C():
+				logger.Info("generation progress", fields(item, now.Sub(start), total, count())...)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func fields(item string, elapsed, total time.Duration, emitted int64) []zap.Field {
+	f := []zap.Field{
+		zap.String("item", item),
+		zap.Int64("emitted", emitted),
+		zap.Duration("elapsed", elapsed.Round(time.Second)),
+	}
+	if elapsed > 0 {
+		f = append(f, zap.Float64("rate_per_second", float64(emitted)/elapsed.Seconds()))
+	}
+	if total > 0 {
+		remaining := total - elapsed
+		if remaining < 0 {
+			remaining = 0
+		}
+		f = append(f, zap.Duration("remaining", remaining.Round(time.Second)))
+	}
+	return f
+}
@@ -0,0 +1,133 @@
+package progress
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStartClock(t *testing.T) {
+	f := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	core, logs := observer.New(zap.InfoLevel)
+	var emitted atomic.Int64
+
+	stop := StartClock(f, zap.New(core), "spans", 10*time.Second, time.Minute, emitted.Load)
+	defer stop()
+
+	for i := 1; i <= 3; i++ {
+		emitted.Add(100)
+		f.Advance(10 * time.Second)
+		waitForEntries(t, logs, i)
+	}
+
+	tests := []struct {
+		elapsed   time.Duration
+		emitted   int64
+		remaining time.Duration
+	}{
+		{10 * time.Second, 100, 50 * time.Second},
+		{20 * time.Second, 200, 40 * time.Second},
+		{30 * time.Second, 300, 30 * time.Second},
+	}
+	for i, tt := range tests {
+		fields := logs.All()[i].ContextMap()
+		if got := fields["item"]; got != "spans" {
+			t.Errorf("heartbeat %d item = %v, want spans", i, got)
+		}
+		if got := fields["emitted"]; got != tt.emitted {
+			t.Errorf("heartbeat %d emitted = %v, want %d", i, got, tt.emitted)
+		}
+		if got := fields["elapsed"]; got != tt.elapsed {
+			t.Errorf("heartbeat %d elapsed = %v, want %s", i, got, tt.elapsed)
+		}
+		if got := fields["remaining"]; got != tt.remaining {
+			t.Errorf("heartbeat %d remaining = %v, want %s", i, got, tt.remaining)
+		}
+		if got := fields["rate_per_second"]; got != 10.0 {
+			t.Errorf("heartbeat %d rate_per_second = %v, want 10", i, got)
+		}
+	}
+}
+
+func TestStartDisabled(t *testing.T) {
+	f := clock.NewFake(time.Now())
+	stop := StartClock(f, zap.NewNop(), "logs", 0, 0, func() int64 { return 0 })
+	defer stop()
+	if n := f.Waiters(); n != 0 {
+		t.Fatalf("a zero interval scheduled %d heartbeats", n)
+	}
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		total   time.Duration
+		want    []string
+	}{
+		{"unbounded", 5 * time.Second, 0, []string{"item", "emitted", "elapsed", "rate_per_second"}},
+		{"bounded", 5 * time.Second, time.Minute, []string{"item", "emitted", "elapsed", "rate_per_second", "remaining"}},
+		{"not started", 0, time.Minute, []string{"item", "emitted", "elapsed", "remaining"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fields("logs", tt.elapsed, tt.total, 10)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d fields, want %v", len(got), tt.want)
+			}
+			for i, key := range tt.want {
+				if got[i].Key != key {
+					t.Errorf("field %d = %s, want %s", i, got[i].Key, key)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldsRemainingNeverNegative(t *testing.T) {
+	for _, f := range fields("logs", 2*time.Minute, time.Minute, 10) {
+		if f.Key == "remaining" && time.Duration(f.Integer) != 0 {
+			t.Fatalf("remaining = %s past the end of the run, want 0", time.Duration(f.Integer))
+		}
+	}
+}
+
+// waitForEntries waits for the heartbeat goroutine to log n entries
+func waitForEntries(t *testing.T, logs *observer.ObservedLogs, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for logs.Len() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d heartbeats, want %d", logs.Len(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	f := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	core, logs := observer.New(zap.InfoLevel)
+
+	stop := StartClock(f, zap.New(core), "spans", time.Second, 0, func() int64 { return 1 })
+	f.Advance(time.Second)
+	waitForEntries(t, logs, 1)
+
+	stop()
+	// The heartbeat goroutine stops its ticker on the way out
+	deadline := time.Now().Add(time.Second)
+	for f.Waiters() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the heartbeat kept its ticker after stop")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	f.Advance(5 * time.Second)
+	time.Sleep(10 * time.Millisecond)
+	if n := logs.Len(); n != 1 {
+		t.Errorf("got %d heartbeats, want none after stop", n-1)
+	}
+}
@@ -0,0 +1,198 @@
+package promrw
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// label is a Prometheus label, prompb.Label
+type label struct {
+	name, value string
+}
+
+// timeSeries is a single sample of a series, prompb.TimeSeries
+type timeSeries struct {
+	labels    []label
+	value     float64
+	timestamp time.Time
+}
+
+// convert maps the data points in rm onto Prometheus series. Monotonic sums gain a
+// _total suffix, histograms expand into _bucket, _sum and _count series, and
+// exponential histograms are reduced to their _sum and _count.
+func convert(rm *metricdata.ResourceMetrics) []timeSeries {
+	var target []label
+	if v, ok := rm.Resource.Set().Value(semconv.ServiceNameKey); ok {
+		target = append(target, label{"job", v.Emit()})
+	}
+	if v, ok := rm.Resource.Set().Value(semconv.ServiceInstanceIDKey); ok {
+		target = append(target, label{"instance", v.Emit()})
+	}
+
+	var series []timeSeries
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			name := sanitize(m.Name)
+			switch d := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				series = appendDataPoints(series, name, target, d.DataPoints)
+			case metricdata.Gauge[float64]:
+				series = appendDataPoints(series, name, target, d.DataPoints)
+			case metricdata.Sum[int64]:
+				series = appendDataPoints(series, sumName(name, d.IsMonotonic), target, d.DataPoints)
+			case metricdata.Sum[float64]:
+				series = appendDataPoints(series, sumName(name, d.IsMonotonic), target, d.DataPoints)
+			case metricdata.Histogram[int64]:
+				series = appendHistogramDataPoints(series, name, target, d.DataPoints)
+			case metricdata.Histogram[float64]:
+				series = appendHistogramDataPoints(series, name, target, d.DataPoints)
+			case metricdata.ExponentialHistogram[int64]:
+				series = appendExponentialHistogramDataPoints(series, name, target, d.DataPoints)
+			case metricdata.ExponentialHistogram[float64]:
+				series = appendExponentialHistogramDataPoints(series, name, target, d.DataPoints)
+			}
+		}
+	}
+	return series
+}
+
+func sumName(name string, monotonic bool) string {
+	if monotonic && !strings.HasSuffix(name, "_total") {
+		return name + "_total"
+	}
+	return name
+}
+
+func appendDataPoints[N int64 | float64](series []timeSeries, name string, target []label, dps []metricdata.DataPoint[N]) []timeSeries {
+	for _, dp := range dps {
+		series = append(series, timeSeries{
+			labels:    labels(name, target, dp.Attributes),
+			value:     float64(dp.Value),
+			timestamp: dp.Time,
+		})
+	}
+	return series
+}
+
+func appendHistogramDataPoints[N int64 | float64](series []timeSeries, name string, target []label, dps []metricdata.HistogramDataPoint[N]) []timeSeries {
+	for _, dp := range dps {
+		// Buckets are cumulative in Prometheus, each counting every value up to its bound
+		var cumulative uint64
+		for i, bound := range dp.Bounds {
+			cumulative += dp.BucketCounts[i]
+			series = append(series, timeSeries{
+				labels:    labels(name+"_bucket", target, dp.Attributes, label{"le", strconv.FormatFloat(bound, 'f', -1, 64)}),
+				value:     float64(cumulative),
+				timestamp: dp.Time,
+			})
+		}
+		series = append(series,
+			timeSeries{labels: labels(name+"_bucket", target, dp.Attributes, label{"le", "+Inf"}), value: float64(dp.Count), timestamp: dp.Time},
+			timeSeries{labels: labels(name+"_sum", target, dp.Attributes), value: float64(dp.Sum), timestamp: dp.Time},
+			timeSeries{labels: labels(name+"_count", target, dp.Attributes), value: float64(dp.Count), timestamp: dp.Time},
+		)
+	}
+	return series
+}
+
+func appendExponentialHistogramDataPoints[N int64 | float64](series []timeSeries, name string, target []label, dps []metricdata.ExponentialHistogramDataPoint[N]) []timeSeries {
+	for _, dp := range dps {
+		series = append(series,
+			timeSeries{labels: labels(name+"_sum", target, dp.Attributes), value: float64(dp.Sum), timestamp: dp.Time},
+			timeSeries{labels: labels(name+"_count", target, dp.Attributes), value: float64(dp.Count), timestamp: dp.Time},
+		)
+	}
+	return series
+}
+
+// labels returns the sorted labels of a series, remote write requires them ordered by name
+func labels(name string, target []label, attrs attribute.Set, extra ...label) []label {
+	ls := make([]label, 0, 1+len(target)+attrs.Len()+len(extra))
+	ls = append(ls, label{"__name__", name})
+	ls = append(ls, target...)
+	for _, kv := range attrs.ToSlice() {
+		ls = append(ls, label{sanitize(string(kv.Key)), kv.Value.Emit()})
+	}
+	ls = append(ls, extra...)
+	sort.Slice(ls, func(i, j int) bool { return ls[i].name < ls[j].name })
+	return ls
+}
+
+// sanitize replaces the characters Prometheus doesn't allow in metric and label
+// names with underscores
+func sanitize(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// marshalWriteRequest encodes series as a prompb.WriteRequest
+func marshalWriteRequest(series []timeSeries) []byte {
+	var buf []byte
+	for _, ts := range series {
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, marshalTimeSeries(ts))
+	}
+	return buf
+}
+
+func marshalTimeSeries(ts timeSeries) []byte {
+	var buf []byte
+	for _, l := range ts.labels {
+		var lb []byte
+		lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+		lb = protowire.AppendString(lb, l.name)
+		lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+		lb = protowire.AppendString(lb, l.value)
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, lb)
+	}
+
+	var sb []byte
+	sb = protowire.AppendTag(sb, 1, protowire.Fixed64Type)
+	sb = protowire.AppendFixed64(sb, math.Float64bits(ts.value))
+	sb = protowire.AppendTag(sb, 2, protowire.VarintType)
+	sb = protowire.AppendVarint(sb, uint64(ts.timestamp.UnixMilli()))
+	buf = protowire.AppendTag(buf, 2, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, sb)
+	return buf
+}
+
+// maxLiteral is the longest literal a single snappy element can hold with a
+// two byte length
+const maxLiteral = 1 << 16
+
+// snappyEncode frames src as a snappy block made only of literals. Remote write
+// requires the snappy block format, and an uncompressed block is valid for every
+// decoder, trading size for not pulling in a compression library.
+func snappyEncode(src []byte) []byte {
+	dst := protowire.AppendVarint(nil, uint64(len(src)))
+	for len(src) > 0 {
+		n := len(src)
+		if n > maxLiteral {
+			n = maxLiteral
+		}
+		// Tag 0xf4 is a literal whose length - 1 follows in two bytes, little endian
+		dst = append(dst, 0xf4, byte(n-1), byte((n-1)>>8))
+		dst = append(dst, src[:n]...)
+		src = src[n:]
+	}
+	return dst
+}
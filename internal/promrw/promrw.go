@@ -0,0 +1,124 @@
+// Package promrw provides a metric exporter that sends data points to a Prometheus
+// remote-write receiver, as snappy compressed protobuf WriteRequests.
+package promrw
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+// DefaultPath is the remote-write path used when the output doesn't name one
+const DefaultPath = "/api/v1/write"
+
+// ParseAddress returns the URL a promrw://host:port/path (HTTP) or
+// promrw+https://host:port/path output is written to
+func ParseAddress(output string) (string, error) {
+	u, err := url.Parse(output)
+	if err != nil {
+		return "", fmt.Errorf("invalid remote-write output %q: %w", output, err)
+	}
+	switch u.Scheme {
+	case "promrw", "promrw+http":
+		u.Scheme = "http"
+	case "promrw+https":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("unsupported remote-write scheme: %s, use one of: promrw, promrw+http, promrw+https", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("remote-write output %q must be of the form %s://host:port/path", output, u.Scheme)
+	}
+	if u.Path == "" {
+		u.Path = DefaultPath
+	}
+	return u.String(), nil
+}
+
+// IsOutput reports whether output names a remote-write receiver
+func IsOutput(output string) bool {
+	return strings.HasPrefix(output, "promrw://") || strings.HasPrefix(output, "promrw+")
+}
+
+// MetricExporter posts every collection to a remote-write receiver. Prometheus
+// expects cumulative series, so it asks for cumulative temporality throughout.
+type MetricExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+var _ sdkmetric.Exporter = (*MetricExporter)(nil)
+
+// NewMetricExporter returns a metric exporter for the remote-write output, sending
+// headers with every request
+func NewMetricExporter(output string, headers map[string]string, logger *zap.Logger) (*MetricExporter, error) {
+	u, err := ParseAddress(output)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricExporter{
+		url:     u,
+		headers: headers,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		logger:  logger,
+	}, nil
+}
+
+func (e *MetricExporter) Temporality(sdkmetric.InstrumentKind) metricdata.Temporality {
+	return metricdata.CumulativeTemporality
+}
+
+func (e *MetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *MetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	series := convert(rm)
+	if len(series) == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(snappyEncode(marshalWriteRequest(series))))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "otelgen")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote-write request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote-write receiver returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	e.logger.Debug("sent remote-write request", zap.Int("series", len(series)))
+	return nil
+}
+
+func (e *MetricExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *MetricExporter) Shutdown(context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,245 @@
+package promrw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// snappyDecode decodes a snappy block made of literals, the only elements snappyEncode writes
+func snappyDecode(b []byte) ([]byte, error) {
+	size, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return nil, errors.New("bad length preamble")
+	}
+	b = b[n:]
+	var out []byte
+	for len(b) > 0 {
+		tag := b[0]
+		if tag&3 != 0 {
+			return nil, fmt.Errorf("unexpected copy element %#x", tag)
+		}
+		length, header := int(tag>>2)+1, 1
+		if tag>>2 >= 60 {
+			extra := int(tag>>2) - 59
+			if len(b) < 1+extra {
+				return nil, errors.New("truncated literal length")
+			}
+			length = 0
+			for i := extra; i > 0; i-- {
+				length = length<<8 | int(b[i])
+			}
+			length++
+			header += extra
+		}
+		if len(b) < header+length {
+			return nil, errors.New("truncated literal")
+		}
+		out = append(out, b[header:header+length]...)
+		b = b[header+length:]
+	}
+	if uint64(len(out)) != size {
+		return nil, fmt.Errorf("decoded %d bytes, the preamble says %d", len(out), size)
+	}
+	return out, nil
+}
+
+// consumeFields calls fn with the number, type and remaining bytes of every field in b,
+// fn returning how many of them the field's value took
+func consumeFields(b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) int) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		m := fn(num, typ, b)
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		b = b[m:]
+	}
+	return nil
+}
+
+// parseWriteRequest decodes a prompb.WriteRequest into its series
+func parseWriteRequest(b []byte) ([]timeSeries, error) {
+	var series []timeSeries
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		if num != 1 {
+			return protowire.ConsumeFieldValue(num, typ, b)
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n
+		}
+		ts, err := parseTimeSeries(v)
+		if err != nil {
+			return -1
+		}
+		series = append(series, ts)
+		return n
+	})
+	return series, err
+}
+
+func parseTimeSeries(b []byte) (timeSeries, error) {
+	var ts timeSeries
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n
+		}
+		switch num {
+		case 1:
+			var l label
+			if err := consumeFields(v, func(num protowire.Number, _ protowire.Type, b []byte) int {
+				s, n := protowire.ConsumeString(b)
+				if num == 1 {
+					l.name = s
+				} else {
+					l.value = s
+				}
+				return n
+			}); err != nil {
+				return -1
+			}
+			ts.labels = append(ts.labels, l)
+		case 2:
+			if err := consumeFields(v, func(num protowire.Number, typ protowire.Type, b []byte) int {
+				switch num {
+				case 1:
+					bits, n := protowire.ConsumeFixed64(b)
+					ts.value = math.Float64frombits(bits)
+					return n
+				case 2:
+					ms, n := protowire.ConsumeVarint(b)
+					ts.timestamp = time.UnixMilli(int64(ms)).UTC()
+					return n
+				}
+				return protowire.ConsumeFieldValue(num, typ, b)
+			}); err != nil {
+				return -1
+			}
+		}
+		return n
+	})
+	return ts, err
+}
+
+func TestMetricExporter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	attrs := attribute.NewSet(attribute.String("http.method", "GET"))
+	rm := &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(semconv.ServiceName("checkout"), semconv.ServiceInstanceID("instance-1")),
+		ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{
+			{Name: "queue.depth", Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Attributes: attrs, Time: now, Value: 7}}}},
+			{Name: "requests", Data: metricdata.Sum[float64]{IsMonotonic: true, DataPoints: []metricdata.DataPoint[float64]{{Attributes: attrs, Time: now, Value: 2.5}}}},
+			{Name: "latency", Data: metricdata.Histogram[float64]{DataPoints: []metricdata.HistogramDataPoint[float64]{{
+				Attributes: attrs, Time: now, Bounds: []float64{0.1, 1}, BucketCounts: []uint64{1, 2, 3}, Count: 6, Sum: 9,
+			}}}},
+		}}},
+	}
+	target := func(name string, extra ...label) []label {
+		return append([]label{{"__name__", name}, {"http_method", "GET"}, {"instance", "instance-1"}, {"job", "checkout"}}, extra...)
+	}
+	want := []timeSeries{
+		{labels: target("queue_depth"), value: 7, timestamp: now},
+		{labels: target("requests_total"), value: 2.5, timestamp: now},
+		{labels: target("latency_bucket", label{"le", "0.1"}), value: 1, timestamp: now},
+		{labels: target("latency_bucket", label{"le", "1"}), value: 3, timestamp: now},
+		{labels: target("latency_bucket", label{"le", "+Inf"}), value: 6, timestamp: now},
+		{labels: target("latency_sum"), value: 9, timestamp: now},
+		{labels: target("latency_count"), value: 6, timestamp: now},
+	}
+
+	var got []timeSeries
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for header, want := range map[string]string{
+			"Content-Encoding":                  "snappy",
+			"Content-Type":                      "application/x-protobuf",
+			"X-Prometheus-Remote-Write-Version": "0.1.0",
+			"Authorization":                     "Bearer token",
+		} {
+			if got := r.Header.Get(header); got != want {
+				t.Errorf("%s header = %q, want %q", header, got, want)
+			}
+		}
+		if r.URL.Path != DefaultPath {
+			t.Errorf("request path = %q, want %q", r.URL.Path, DefaultPath)
+		}
+		body, _ := io.ReadAll(r.Body)
+		decoded, err := snappyDecode(body)
+		if err != nil {
+			t.Errorf("snappy decode: %v", err)
+			return
+		}
+		if got, err = parseWriteRequest(decoded); err != nil {
+			t.Errorf("protobuf decode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	exp, err := NewMetricExporter("promrw://"+strings.TrimPrefix(srv.URL, "http://"), map[string]string{"Authorization": "Bearer token"}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Export(context.Background(), rm); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("received series\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestMetricExporterReportsRejection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	exp, err := NewMetricExporter("promrw://"+strings.TrimPrefix(srv.URL, "http://"), nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{
+		{Name: "g", Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Value: 1}}}},
+	}}}}
+	err = exp.Export(context.Background(), rm)
+	if err == nil || !strings.Contains(err.Error(), "out of order sample") {
+		t.Errorf("Export() error = %v, want the receiver's rejection", err)
+	}
+}
+
+func TestSnappyEncode(t *testing.T) {
+	for _, size := range []int{0, 1, 60, maxLiteral, maxLiteral + 1, 3*maxLiteral + 17} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			src := make([]byte, size)
+			for i := range src {
+				src[i] = byte(i * 7)
+			}
+			got, err := snappyDecode(snappyEncode(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(src) {
+				t.Errorf("round trip of %d bytes differs", size)
+			}
+		})
+	}
+}
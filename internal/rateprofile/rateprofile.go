@@ -0,0 +1,113 @@
+package rateprofile
+
+import (
+	"fmt"
+	"time"
+)
+
+// Profile describes how the generation rate is modulated over the duration of a run.
+type Profile string
+
+const (
+	// Constant keeps the configured rate for the whole run.
+	Constant Profile = "constant"
+	// Linear ramps the rate from zero up to the configured rate.
+	Linear Profile = "linear"
+	// Spike runs at a low baseline and jumps to the configured rate around the midpoint.
+	Spike Profile = "spike"
+)
+
+const (
+	// minFactor stops a profile from stalling generation entirely.
+	minFactor = 0.01
+	// spikeBaseline is the fraction of the configured rate used outside of a spike.
+	spikeBaseline = 0.1
+	// spikeWidth is the fraction of the run spent at the full rate during a spike.
+	spikeWidth = 0.1
+	// intervalSteps is how many steps of a run Interval follows the rate across.
+	intervalSteps = 1000
+)
+
+// Parse converts a flag value into a Profile.
+func Parse(s string) (Profile, error) {
+	switch p := Profile(s); p {
+	case Constant, Linear, Spike:
+		return p, nil
+	case "":
+		return Constant, nil
+	default:
+		return "", fmt.Errorf("unknown rate profile: %s (expected one of: constant, linear, spike)", s)
+	}
+}
+
+// RequiresDuration reports whether the profile needs a known run duration.
+func (p Profile) RequiresDuration() bool {
+	return p != Constant && p != ""
+}
+
+// Factor returns the fraction of the configured rate to use after elapsed of total.
+func (p Profile) Factor(elapsed, total time.Duration) float64 {
+	if total <= 0 {
+		return 1
+	}
+	progress := float64(elapsed) / float64(total)
+	if progress < 0 {
+		progress = 0
+	} else if progress > 1 {
+		progress = 1
+	}
+
+	var f float64
+	switch p {
+	case Linear:
+		f = progress
+	case Spike:
+		f = spikeBaseline
+		if progress >= 0.5 && progress < 0.5+spikeWidth {
+			f = 1
+		}
+	default:
+		f = 1
+	}
+
+	if f < minFactor {
+		return minFactor
+	}
+	return f
+}
+
+// Rate scales a per-second rate according to the profile.
+func (p Profile) Rate(base float64, elapsed, total time.Duration) float64 {
+	return base * p.Factor(elapsed, total)
+}
+
+// Interval returns how long to wait after elapsed of total before the next emission,
+// base being the interval at the configured rate. The wait follows the rate as it
+// changes rather than holding the rate at elapsed, so a linear ramp starting near zero
+// emits as soon as it has ramped up instead of waiting out its slowest interval.
+func (p Profile) Interval(base time.Duration, elapsed, total time.Duration) time.Duration {
+	if base <= 0 || total <= 0 || !p.RequiresDuration() {
+		return time.Duration(float64(base) / p.Factor(elapsed, total))
+	}
+
+	// Sum the share of an emission owed over each step until one is due, the rate
+	// no longer changing once the run is over
+	step := total / intervalSteps
+	var owed float64
+	var wait time.Duration
+	for {
+		f := p.Factor(elapsed+wait, total)
+		need := time.Duration((1 - owed) * float64(base) / f)
+		if need <= step || elapsed+wait >= total {
+			return wait + need
+		}
+		owed += float64(step) * f / float64(base)
+		wait += step
+	}
+}
+
+// Pace returns the per-second rate that spaces the next emission Interval apart, for
+// pacing a limiter whose waits are fixed when they're reserved.
+func (p Profile) Pace(base float64, elapsed, total time.Duration) float64 {
+	return float64(time.Second) / float64(p.Interval(time.Duration(float64(time.Second)/base), elapsed, total))
+}
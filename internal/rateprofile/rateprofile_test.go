@@ -0,0 +1,73 @@
+package rateprofile
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestProfileRate(t *testing.T) {
+	const base = 10.0
+	total := 10 * time.Second
+
+	tests := []struct {
+		name    string
+		profile Profile
+		elapsed time.Duration
+		total   time.Duration
+		want    float64
+	}{
+		{name: "constant at start", profile: Constant, elapsed: 0, total: total, want: base},
+		{name: "constant at end", profile: Constant, elapsed: total, total: total, want: base},
+		{name: "unset is constant", profile: "", elapsed: 5 * time.Second, total: total, want: base},
+		{name: "linear at start is floored", profile: Linear, elapsed: 0, total: total, want: base * minFactor},
+		{name: "linear halfway", profile: Linear, elapsed: 5 * time.Second, total: total, want: base / 2},
+		{name: "linear at end", profile: Linear, elapsed: total, total: total, want: base},
+		{name: "linear past end", profile: Linear, elapsed: 2 * total, total: total, want: base},
+		{name: "spike before", profile: Spike, elapsed: 2 * time.Second, total: total, want: base * spikeBaseline},
+		{name: "spike start", profile: Spike, elapsed: 5 * time.Second, total: total, want: base},
+		{name: "spike during", profile: Spike, elapsed: 5500 * time.Millisecond, total: total, want: base},
+		{name: "spike after", profile: Spike, elapsed: 7 * time.Second, total: total, want: base * spikeBaseline},
+		{name: "no duration", profile: Linear, elapsed: time.Second, total: 0, want: base},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.profile.Rate(base, tt.elapsed, tt.total); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Rate(%v, %v, %v) = %v, want %v", base, tt.elapsed, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileInterval(t *testing.T) {
+	total := 10 * time.Second
+
+	tests := []struct {
+		name    string
+		profile Profile
+		base    time.Duration
+		elapsed time.Duration
+		total   time.Duration
+		want    time.Duration
+	}{
+		{name: "constant", profile: Constant, base: time.Second, elapsed: 0, total: total, want: time.Second},
+		// The rate climbs by a tenth a second, owing a whole emission after sqrt(20)s
+		{name: "linear from the start", profile: Linear, base: time.Second, elapsed: 0, total: total, want: 4472 * time.Millisecond},
+		{name: "linear at the end", profile: Linear, base: time.Second, elapsed: total, total: total, want: time.Second},
+		{name: "linear past the end", profile: Linear, base: time.Second, elapsed: 2 * total, total: total, want: time.Second},
+		{name: "linear beyond the run", profile: Linear, base: time.Hour, elapsed: 0, total: total, want: time.Hour + 5*time.Second},
+		{name: "spike baseline", profile: Spike, base: 100 * time.Millisecond, elapsed: 0, total: total, want: time.Second},
+		// Half an emission is owed at the baseline by the spike, the rest within half a second of it
+		{name: "spike reached while waiting", profile: Spike, base: time.Second, elapsed: 0, total: total, want: 5500 * time.Millisecond},
+		{name: "spike", profile: Spike, base: time.Second, elapsed: 5 * time.Second, total: total, want: time.Second},
+		{name: "unbounded run", profile: Linear, base: time.Second, elapsed: time.Hour, total: 0, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.profile.Interval(tt.base, tt.elapsed, tt.total)
+			if diff := got - tt.want; diff < -tt.want/100 || diff > tt.want/100 {
+				t.Errorf("Interval(%v, %v, %v) = %v, want %v", tt.base, tt.elapsed, tt.total, got, tt.want)
+			}
+		})
+	}
+}
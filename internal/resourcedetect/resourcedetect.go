@@ -0,0 +1,49 @@
+package resourcedetect
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Merge detects host, process and OS attributes from the running environment
+// and merges them with res. Attributes already set on res take precedence.
+func Merge(ctx context.Context, res *resource.Resource) (*resource.Resource, error) {
+	detected, err := resource.New(ctx,
+		resource.WithHost(),
+		resource.WithProcess(),
+		resource.WithOS(),
+		resource.WithAttributes(res.Attributes()...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect resource attributes: %w", err)
+	}
+	return detected, nil
+}
+
+// MergeContainer detects the id of the container the process runs in and merges
+// it with res. Attributes already set on res take precedence.
+func MergeContainer(ctx context.Context, res *resource.Resource) (*resource.Resource, error) {
+	merged, err := resource.New(ctx,
+		resource.WithContainer(),
+		resource.WithAttributes(res.Attributes()...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect container resource attributes: %w", err)
+	}
+	return merged, nil
+}
+
+// MergeEnv reads the attributes set in OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME
+// and merges them with res. Attributes already set on res take precedence.
+func MergeEnv(ctx context.Context, res *resource.Resource) (*resource.Resource, error) {
+	merged, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithAttributes(res.Attributes()...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource attributes from the environment: %w", err)
+	}
+	return merged, nil
+}
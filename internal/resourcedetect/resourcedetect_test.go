@@ -0,0 +1,93 @@
+package resourcedetect
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *resource.Resource
+		keep map[attribute.Key]string
+	}{
+		{name: "empty", res: resource.Empty()},
+		{
+			name: "service",
+			res:  resource.NewSchemaless(semconv.ServiceName("otelgen"), semconv.ServiceVersion("1.2.3")),
+			keep: map[attribute.Key]string{semconv.ServiceNameKey: "otelgen", semconv.ServiceVersionKey: "1.2.3"},
+		},
+		{
+			name: "host name set",
+			res:  resource.NewSchemaless(semconv.HostName("node-1")),
+			keep: map[attribute.Key]string{semconv.HostNameKey: "node-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge(context.Background(), tt.res)
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			for _, key := range []attribute.Key{semconv.HostNameKey, semconv.ProcessPIDKey, semconv.OSTypeKey} {
+				if _, ok := got.Set().Value(key); !ok {
+					t.Errorf("Merge() detected no %s", key)
+				}
+			}
+			for key, want := range tt.keep {
+				if v, _ := got.Set().Value(key); v.AsString() != want {
+					t.Errorf("Merge() %s = %q, want %q", key, v.AsString(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		res  *resource.Resource
+		want map[attribute.Key]string
+	}{
+		{
+			name: "from the environment",
+			env:  "deployment.environment=staging,team=platform",
+			res:  resource.NewSchemaless(semconv.ServiceName("otelgen")),
+			want: map[attribute.Key]string{semconv.ServiceNameKey: "otelgen", "deployment.environment": "staging", "team": "platform"},
+		},
+		{
+			name: "flags take precedence",
+			env:  "service.name=from-env,service.version=9.9.9,team=platform",
+			res:  resource.NewSchemaless(semconv.ServiceName("otelgen"), semconv.ServiceVersion("1.2.3")),
+			want: map[attribute.Key]string{semconv.ServiceNameKey: "otelgen", semconv.ServiceVersionKey: "1.2.3", "team": "platform"},
+		},
+		{
+			name: "unset",
+			res:  resource.NewSchemaless(semconv.ServiceName("otelgen")),
+			want: map[attribute.Key]string{semconv.ServiceNameKey: "otelgen"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_RESOURCE_ATTRIBUTES", tt.env)
+			t.Setenv("OTEL_SERVICE_NAME", "")
+			got, err := MergeEnv(context.Background(), tt.res)
+			if err != nil {
+				t.Fatalf("MergeEnv() error = %v", err)
+			}
+			if got.Len() != len(tt.want) {
+				t.Errorf("MergeEnv() = %s, want %d attributes", got, len(tt.want))
+			}
+			for key, want := range tt.want {
+				if v, _ := got.Set().Value(key); v.AsString() != want {
+					t.Errorf("MergeEnv() %s = %q, want %q", key, v.AsString(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package shard spreads exports across several exporters, each with its own
+// connection, so a single connection doesn't bottleneck very high rates.
+package shard
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type metricExporter struct {
+	exporters []sdkmetric.Exporter
+}
+
+// MetricExporter returns an exporter that splits the metric streams of every
+// collection across exps, exporting the shards concurrently. The temporality and
+// aggregation of the first exporter apply to all of them. A single exporter is
+// returned as it is.
+func MetricExporter(exps []sdkmetric.Exporter) sdkmetric.Exporter {
+	if len(exps) == 1 {
+		return exps[0]
+	}
+	return &metricExporter{exporters: exps}
+}
+
+func (e *metricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return e.exporters[0].Temporality(kind)
+}
+
+func (e *metricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return e.exporters[0].Aggregation(kind)
+}
+
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	shards := split(rm, len(e.exporters))
+	return e.each(func(i int, exp sdkmetric.Exporter) error {
+		if shards[i] == nil {
+			return nil
+		}
+		return exp.Export(ctx, shards[i])
+	})
+}
+
+func (e *metricExporter) ForceFlush(ctx context.Context) error {
+	return e.each(func(_ int, exp sdkmetric.Exporter) error {
+		return exp.ForceFlush(ctx)
+	})
+}
+
+func (e *metricExporter) Shutdown(ctx context.Context) error {
+	return e.each(func(_ int, exp sdkmetric.Exporter) error {
+		return exp.Shutdown(ctx)
+	})
+}
+
+// each calls fn for every exporter concurrently, joining the errors they return
+func (e *metricExporter) each(fn func(int, sdkmetric.Exporter) error) error {
+	errs := make([]error, len(e.exporters))
+	var wg sync.WaitGroup
+	for i, exp := range e.exporters {
+		wg.Add(1)
+		go func(i int, exp sdkmetric.Exporter) {
+			defer wg.Done()
+			errs[i] = fn(i, exp)
+		}(i, exp)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// split deals the metric streams of rm round robin into n shards that keep their
+// resource and scope, leaving a shard nil when it is given no streams
+func split(rm *metricdata.ResourceMetrics, n int) []*metricdata.ResourceMetrics {
+	shards := make([]*metricdata.ResourceMetrics, n)
+	next := 0
+	for _, sm := range rm.ScopeMetrics {
+		scoped := make([]bool, n)
+		for _, m := range sm.Metrics {
+			i := next % n
+			next++
+			if shards[i] == nil {
+				shards[i] = &metricdata.ResourceMetrics{Resource: rm.Resource}
+			}
+			if !scoped[i] {
+				shards[i].ScopeMetrics = append(shards[i].ScopeMetrics, metricdata.ScopeMetrics{Scope: sm.Scope})
+				scoped[i] = true
+			}
+			last := &shards[i].ScopeMetrics[len(shards[i].ScopeMetrics)-1]
+			last.Metrics = append(last.Metrics, m)
+		}
+	}
+	return shards
+}
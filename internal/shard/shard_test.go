@@ -0,0 +1,170 @@
+package shard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// resourceMetrics returns a collection with the given number of metric streams in
+// each of its scopes
+func resourceMetrics(streams ...int) *metricdata.ResourceMetrics {
+	rm := &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(attribute.String("service.name", "otelgen")),
+	}
+	for s, n := range streams {
+		sm := metricdata.ScopeMetrics{Scope: instrumentation.Scope{Name: fmt.Sprintf("scope-%d", s)}}
+		for m := 0; m < n; m++ {
+			sm.Metrics = append(sm.Metrics, metricdata.Metrics{Name: fmt.Sprintf("metric-%d-%d", s, m)})
+		}
+		rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+	}
+	return rm
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name       string
+		streams    []int
+		n          int
+		wantShards int
+	}{
+		{name: "one shard", streams: []int{5}, n: 1, wantShards: 1},
+		{name: "even", streams: []int{6}, n: 3, wantShards: 3},
+		{name: "uneven", streams: []int{7}, n: 3, wantShards: 3},
+		{name: "fewer streams than shards", streams: []int{2}, n: 4, wantShards: 2},
+		{name: "several scopes", streams: []int{3, 1, 4}, n: 3, wantShards: 3},
+		{name: "no streams", streams: []int{0}, n: 2, wantShards: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := resourceMetrics(tt.streams...)
+			shards := split(rm, tt.n)
+			if len(shards) != tt.n {
+				t.Fatalf("split() returned %d shards, want %d", len(shards), tt.n)
+			}
+
+			scopes := map[string]string{}
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					scopes[m.Name] = sm.Scope.Name
+				}
+			}
+			seen := map[string]int{}
+			nonNil := 0
+			for i, shard := range shards {
+				if shard == nil {
+					continue
+				}
+				nonNil++
+				if shard.Resource != rm.Resource {
+					t.Errorf("shard %d lost its resource", i)
+				}
+				for _, sm := range shard.ScopeMetrics {
+					if len(sm.Metrics) == 0 {
+						t.Errorf("shard %d has the empty scope %q", i, sm.Scope.Name)
+					}
+					for _, m := range sm.Metrics {
+						seen[m.Name]++
+						if scopes[m.Name] != sm.Scope.Name {
+							t.Errorf("%s moved from scope %q to %q", m.Name, scopes[m.Name], sm.Scope.Name)
+						}
+					}
+				}
+			}
+			if nonNil != tt.wantShards {
+				t.Errorf("split() filled %d shards, want %d", nonNil, tt.wantShards)
+			}
+			for name := range scopes {
+				if seen[name] != 1 {
+					t.Errorf("%s landed in %d shards, want exactly one", name, seen[name])
+				}
+			}
+		})
+	}
+}
+
+// mockExporter records the streams it's given, failing every call with err
+type mockExporter struct {
+	sdkmetric.Exporter
+	err error
+
+	mu      sync.Mutex
+	streams int
+	calls   int
+}
+
+func (e *mockExporter) Temporality(sdkmetric.InstrumentKind) metricdata.Temporality {
+	return metricdata.DeltaTemporality
+}
+
+func (e *mockExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls++
+	for _, sm := range rm.ScopeMetrics {
+		e.streams += len(sm.Metrics)
+	}
+	return e.err
+}
+
+func (e *mockExporter) ForceFlush(context.Context) error {
+	return e.err
+}
+
+func (e *mockExporter) Shutdown(context.Context) error {
+	return e.err
+}
+
+func TestMetricExporter(t *testing.T) {
+	errFirst := errors.New("first connection refused")
+	errThird := errors.New("third connection refused")
+	mocks := []*mockExporter{{err: errFirst}, {}, {err: errThird}}
+	exps := make([]sdkmetric.Exporter, len(mocks))
+	for i, m := range mocks {
+		exps[i] = m
+	}
+	exp := MetricExporter(exps)
+
+	ctx := context.Background()
+	calls := []struct {
+		name string
+		fn   func() error
+	}{
+		{name: "Export", fn: func() error { return exp.Export(ctx, resourceMetrics(4, 2)) }},
+		{name: "ForceFlush", fn: func() error { return exp.ForceFlush(ctx) }},
+		{name: "Shutdown", fn: func() error { return exp.Shutdown(ctx) }},
+	}
+	for _, tt := range calls {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fn()
+			if !errors.Is(err, errFirst) || !errors.Is(err, errThird) {
+				t.Errorf("%s() error = %v, want both connections' errors", tt.name, err)
+			}
+		})
+	}
+
+	for i, m := range mocks {
+		if m.calls != 1 || m.streams != 2 {
+			t.Errorf("exporter %d exported %d streams over %d calls, want 2 in one", i, m.streams, m.calls)
+		}
+	}
+	if got := exp.Temporality(sdkmetric.InstrumentKindCounter); got != metricdata.DeltaTemporality {
+		t.Errorf("Temporality() = %v, want the first exporter's", got)
+	}
+}
+
+func TestMetricExporterSingle(t *testing.T) {
+	mock := &mockExporter{}
+	if got := MetricExporter([]sdkmetric.Exporter{mock}); got != mock {
+		t.Errorf("MetricExporter() = %T, want the single exporter as it is", got)
+	}
+}
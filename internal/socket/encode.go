@@ -0,0 +1,279 @@
+package socket
+
+import (
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// spanLine is the JSON shape of an exported span
+type spanLine struct {
+	Signal        string                 `json:"signal"`
+	TraceID       string                 `json:"trace_id"`
+	SpanID        string                 `json:"span_id"`
+	ParentSpanID  string                 `json:"parent_span_id,omitempty"`
+	Name          string                 `json:"name"`
+	Kind          string                 `json:"kind"`
+	StartTime     time.Time              `json:"start_time"`
+	EndTime       time.Time              `json:"end_time"`
+	StatusCode    string                 `json:"status_code"`
+	StatusMessage string                 `json:"status_message,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Scope         string                 `json:"scope,omitempty"`
+	Resource      map[string]interface{} `json:"resource,omitempty"`
+}
+
+// metricLine is the JSON shape of a single exported metric data point
+type metricLine struct {
+	Signal      string                 `json:"signal"`
+	Name        string                 `json:"name"`
+	Unit        string                 `json:"unit,omitempty"`
+	Type        string                 `json:"type"`
+	Temporality string                 `json:"temporality,omitempty"`
+	StartTime   time.Time              `json:"start_time,omitempty"`
+	Time        time.Time              `json:"time"`
+	Value       interface{}            `json:"value,omitempty"`
+	Count       uint64                 `json:"count,omitempty"`
+	Sum         interface{}            `json:"sum,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Scope       string                 `json:"scope,omitempty"`
+	Resource    map[string]interface{} `json:"resource,omitempty"`
+}
+
+// logLine is the JSON shape of an exported log record
+type logLine struct {
+	Signal       string                 `json:"signal"`
+	Time         time.Time              `json:"time"`
+	ObservedTime time.Time              `json:"observed_time"`
+	Severity     int                    `json:"severity"`
+	SeverityText string                 `json:"severity_text,omitempty"`
+	Body         interface{}            `json:"body,omitempty"`
+	TraceID      string                 `json:"trace_id,omitempty"`
+	SpanID       string                 `json:"span_id,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Scope        string                 `json:"scope,omitempty"`
+	Resource     map[string]interface{} `json:"resource,omitempty"`
+}
+
+func encodeSpan(s sdktrace.ReadOnlySpan) spanLine {
+	line := spanLine{
+		Signal:        "span",
+		TraceID:       s.SpanContext().TraceID().String(),
+		SpanID:        s.SpanContext().SpanID().String(),
+		Name:          s.Name(),
+		Kind:          s.SpanKind().String(),
+		StartTime:     s.StartTime(),
+		EndTime:       s.EndTime(),
+		StatusCode:    s.Status().Code.String(),
+		StatusMessage: s.Status().Description,
+		Attributes:    attributes(s.Attributes()),
+		Scope:         s.InstrumentationScope().Name,
+		Resource:      resourceAttributes(s.Resource()),
+	}
+	if s.Parent().HasSpanID() {
+		line.ParentSpanID = s.Parent().SpanID().String()
+	}
+	return line
+}
+
+// encodeMetrics returns one line per data point held in rm. The SDK gives no
+// guarantee about the order of scopes, metrics or data points, so lines are ordered
+// by scope, metric name and data point attributes to keep the output byte stable.
+func encodeMetrics(rm *metricdata.ResourceMetrics) []interface{} {
+	res := resourceAttributes(rm.Resource)
+	var lines []interface{}
+	scopes := sorted(rm.ScopeMetrics, func(a, b metricdata.ScopeMetrics) bool {
+		if a.Scope.Name != b.Scope.Name {
+			return a.Scope.Name < b.Scope.Name
+		}
+		return a.Scope.Version < b.Scope.Version
+	})
+	for _, sm := range scopes {
+		metrics := sorted(sm.Metrics, func(a, b metricdata.Metrics) bool { return a.Name < b.Name })
+		for _, m := range metrics {
+			base := metricLine{
+				Signal:   "metric",
+				Name:     m.Name,
+				Unit:     m.Unit,
+				Scope:    sm.Scope.Name,
+				Resource: res,
+			}
+			lines = append(lines, encodeAggregation(base, m.Data)...)
+		}
+	}
+	return lines
+}
+
+func encodeAggregation(base metricLine, data metricdata.Aggregation) []interface{} {
+	switch d := data.(type) {
+	case metricdata.Gauge[int64]:
+		return encodeDataPoints(base, "gauge", "", d.DataPoints)
+	case metricdata.Gauge[float64]:
+		return encodeDataPoints(base, "gauge", "", d.DataPoints)
+	case metricdata.Sum[int64]:
+		return encodeDataPoints(base, "sum", d.Temporality.String(), d.DataPoints)
+	case metricdata.Sum[float64]:
+		return encodeDataPoints(base, "sum", d.Temporality.String(), d.DataPoints)
+	case metricdata.Histogram[int64]:
+		return encodeHistogramDataPoints(base, d.Temporality.String(), d.DataPoints)
+	case metricdata.Histogram[float64]:
+		return encodeHistogramDataPoints(base, d.Temporality.String(), d.DataPoints)
+	case metricdata.ExponentialHistogram[int64]:
+		return encodeExponentialHistogramDataPoints(base, d.Temporality.String(), d.DataPoints)
+	case metricdata.ExponentialHistogram[float64]:
+		return encodeExponentialHistogramDataPoints(base, d.Temporality.String(), d.DataPoints)
+	default:
+		return nil
+	}
+}
+
+func encodeDataPoints[N int64 | float64](base metricLine, kind, temporality string, dps []metricdata.DataPoint[N]) []interface{} {
+	lines := make([]interface{}, 0, len(dps))
+	dps = byAttributes(dps, func(dp metricdata.DataPoint[N]) attribute.Set { return dp.Attributes })
+	for _, dp := range dps {
+		line := base
+		line.Type = kind
+		line.Temporality = temporality
+		line.StartTime = dp.StartTime
+		line.Time = dp.Time
+		line.Value = dp.Value
+		line.Attributes = attributes(dp.Attributes.ToSlice())
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func encodeHistogramDataPoints[N int64 | float64](base metricLine, temporality string, dps []metricdata.HistogramDataPoint[N]) []interface{} {
+	lines := make([]interface{}, 0, len(dps))
+	dps = byAttributes(dps, func(dp metricdata.HistogramDataPoint[N]) attribute.Set { return dp.Attributes })
+	for _, dp := range dps {
+		line := base
+		line.Type = "histogram"
+		line.Temporality = temporality
+		line.StartTime = dp.StartTime
+		line.Time = dp.Time
+		line.Count = dp.Count
+		line.Sum = dp.Sum
+		line.Attributes = attributes(dp.Attributes.ToSlice())
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func encodeExponentialHistogramDataPoints[N int64 | float64](base metricLine, temporality string, dps []metricdata.ExponentialHistogramDataPoint[N]) []interface{} {
+	lines := make([]interface{}, 0, len(dps))
+	dps = byAttributes(dps, func(dp metricdata.ExponentialHistogramDataPoint[N]) attribute.Set { return dp.Attributes })
+	for _, dp := range dps {
+		line := base
+		line.Type = "exponential_histogram"
+		line.Temporality = temporality
+		line.StartTime = dp.StartTime
+		line.Time = dp.Time
+		line.Count = dp.Count
+		line.Sum = dp.Sum
+		line.Attributes = attributes(dp.Attributes.ToSlice())
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// byAttributes returns a copy of dps ordered by their encoded attributes
+func byAttributes[T any](dps []T, attrs func(T) attribute.Set) []T {
+	enc := attribute.DefaultEncoder()
+	return sorted(dps, func(a, b T) bool {
+		sa, sb := attrs(a), attrs(b)
+		return sa.Encoded(enc) < sb.Encoded(enc)
+	})
+}
+
+// sorted returns a copy of s ordered by less, leaving s untouched
+func sorted[T any](s []T, less func(a, b T) bool) []T {
+	out := append([]T(nil), s...)
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+func encodeLog(r sdklog.Record) logLine {
+	line := logLine{
+		Signal:       "log",
+		Time:         r.Timestamp(),
+		ObservedTime: r.ObservedTimestamp(),
+		Severity:     int(r.Severity()),
+		SeverityText: r.SeverityText(),
+		Body:         logValue(r.Body()),
+		Scope:        r.InstrumentationScope().Name,
+	}
+	res := r.Resource()
+	line.Resource = resourceAttributes(&res)
+	if r.TraceID().IsValid() {
+		line.TraceID = r.TraceID().String()
+	}
+	if r.SpanID().IsValid() {
+		line.SpanID = r.SpanID().String()
+	}
+	if r.AttributesLen() > 0 {
+		line.Attributes = make(map[string]interface{}, r.AttributesLen())
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			line.Attributes[kv.Key] = logValue(kv.Value)
+			return true
+		})
+	}
+	return line
+}
+
+// attributes converts attrs into a JSON object keyed by attribute name
+func attributes(attrs []attribute.KeyValue) map[string]interface{} {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{}, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+func resourceAttributes(res *resource.Resource) map[string]interface{} {
+	if res == nil {
+		return nil
+	}
+	return attributes(res.Attributes())
+}
+
+// logValue converts a log value into its JSON equivalent
+func logValue(v log.Value) interface{} {
+	switch v.Kind() {
+	case log.KindBool:
+		return v.AsBool()
+	case log.KindInt64:
+		return v.AsInt64()
+	case log.KindFloat64:
+		return v.AsFloat64()
+	case log.KindString:
+		return v.AsString()
+	case log.KindBytes:
+		return v.AsBytes()
+	case log.KindSlice:
+		values := v.AsSlice()
+		s := make([]interface{}, 0, len(values))
+		for _, item := range values {
+			s = append(s, logValue(item))
+		}
+		return s
+	case log.KindMap:
+		kvs := v.AsMap()
+		m := make(map[string]interface{}, len(kvs))
+		for _, kv := range kvs {
+			m[kv.Key] = logValue(kv.Value)
+		}
+		return m
+	default:
+		return nil
+	}
+}
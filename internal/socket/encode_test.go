@@ -0,0 +1,121 @@
+package socket
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenMetrics returns a fixed collection spanning two scopes and every kind of
+// aggregation, with its scopes, metrics and data points reversed when shuffle is set
+func goldenMetrics(shuffle bool) *metricdata.ResourceMetrics {
+	start, end := time.Unix(1700000000, 0).UTC(), time.Unix(1700000010, 0).UTC()
+	points := func(values ...int64) []metricdata.DataPoint[int64] {
+		var dps []metricdata.DataPoint[int64]
+		for i, v := range values {
+			dps = append(dps, metricdata.DataPoint[int64]{
+				Attributes: attribute.NewSet(attribute.Int("worker", i), attribute.String("host", "a")),
+				StartTime:  start,
+				Time:       end,
+				Value:      v,
+			})
+		}
+		return dps
+	}
+	rm := &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(attribute.String("service.name", "otelgen"), attribute.String("host.name", "golden")),
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Scope: instrumentation.Scope{Name: "otelgen/a", Version: "v1"},
+				Metrics: []metricdata.Metrics{
+					{Name: "otelgen.counter", Unit: "1", Data: metricdata.Sum[int64]{Temporality: metricdata.CumulativeTemporality, IsMonotonic: true, DataPoints: points(1, 2, 3)}},
+					{Name: "otelgen.gauge", Data: metricdata.Gauge[float64]{DataPoints: []metricdata.DataPoint[float64]{{Time: end, Value: 0.5}}}},
+				},
+			},
+			{
+				Scope: instrumentation.Scope{Name: "otelgen/b"},
+				Metrics: []metricdata.Metrics{
+					{Name: "otelgen.histogram", Unit: "ms", Data: metricdata.Histogram[float64]{
+						Temporality: metricdata.DeltaTemporality,
+						DataPoints: []metricdata.HistogramDataPoint[float64]{
+							{Attributes: attribute.NewSet(attribute.String("route", "/b")), StartTime: start, Time: end, Count: 2, Sum: 7},
+							{Attributes: attribute.NewSet(attribute.String("route", "/a")), StartTime: start, Time: end, Count: 3, Sum: 9},
+						},
+					}},
+					{Name: "otelgen.exponential_histogram", Data: metricdata.ExponentialHistogram[int64]{
+						Temporality: metricdata.DeltaTemporality,
+						DataPoints:  []metricdata.ExponentialHistogramDataPoint[int64]{{StartTime: start, Time: end, Count: 4, Sum: 10}},
+					}},
+				},
+			},
+		},
+	}
+	if shuffle {
+		slices.Reverse(rm.ScopeMetrics)
+		for _, sm := range rm.ScopeMetrics {
+			slices.Reverse(sm.Metrics)
+		}
+		sum := rm.ScopeMetrics[1].Metrics[1].Data.(metricdata.Sum[int64])
+		slices.Reverse(sum.DataPoints)
+	}
+	return rm
+}
+
+func encodeGolden(t *testing.T, rm *metricdata.ResourceMetrics) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	e := NewMetricExporter(newStreamSink(&out, false, 0), sdkmetric.DefaultTemporalitySelector)
+	if err := e.Export(context.Background(), rm); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestEncodeMetricsGolden(t *testing.T) {
+	golden := filepath.Join("testdata", "metrics.golden")
+	want := encodeGolden(t, goldenMetrics(false))
+	if *update {
+		if err := os.WriteFile(golden, want, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v, run the test with -update to create it", err)
+	}
+
+	tests := []struct {
+		name    string
+		shuffle bool
+	}{
+		{name: "ordered", shuffle: false},
+		{name: "shuffled", shuffle: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := goldenMetrics(tt.shuffle)
+			for i := 0; i < 3; i++ {
+				if got := encodeGolden(t, rm); !bytes.Equal(got, b) {
+					t.Fatalf("encoding %d differs from %s:\n%s\nwant:\n%s", i, golden, got, b)
+				}
+			}
+			if !reflect.DeepEqual(rm, goldenMetrics(tt.shuffle)) {
+				t.Error("encoding reordered the exported data")
+			}
+		})
+	}
+}
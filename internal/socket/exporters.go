@@ -0,0 +1,109 @@
+package socket
+
+import (
+	"context"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// flusher is implemented by sinks that buffer frames
+type flusher interface {
+	Flush() error
+}
+
+// flush writes the frames buffered by w, if it buffers any
+func flush(w Sink) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// SpanExporter writes every span as a JSON line to a sink
+type SpanExporter struct {
+	w Sink
+}
+
+var _ sdktrace.SpanExporter = (*SpanExporter)(nil)
+
+// NewSpanExporter returns a span exporter that streams spans to w
+func NewSpanExporter(w Sink) *SpanExporter {
+	return &SpanExporter{w: w}
+}
+
+func (e *SpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	lines := make([]interface{}, 0, len(spans))
+	for _, s := range spans {
+		lines = append(lines, encodeSpan(s))
+	}
+	return writeLines(ctx, e.w, lines)
+}
+
+func (e *SpanExporter) Shutdown(context.Context) error {
+	return e.w.Close()
+}
+
+// MetricExporter writes every data point as a JSON line to a sink
+type MetricExporter struct {
+	w           Sink
+	temporality sdkmetric.TemporalitySelector
+}
+
+var _ sdkmetric.Exporter = (*MetricExporter)(nil)
+
+// NewMetricExporter returns a metric exporter that streams data points to w,
+// reporting the temporality chosen by temporality
+func NewMetricExporter(w Sink, temporality sdkmetric.TemporalitySelector) *MetricExporter {
+	return &MetricExporter{w: w, temporality: temporality}
+}
+
+func (e *MetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return e.temporality(kind)
+}
+
+func (e *MetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *MetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return writeLines(ctx, e.w, encodeMetrics(rm))
+}
+
+func (e *MetricExporter) ForceFlush(context.Context) error {
+	return flush(e.w)
+}
+
+func (e *MetricExporter) Shutdown(context.Context) error {
+	return e.w.Close()
+}
+
+// LogExporter writes every log record as a JSON line to a sink
+type LogExporter struct {
+	w Sink
+}
+
+var _ sdklog.Exporter = (*LogExporter)(nil)
+
+// NewLogExporter returns a log exporter that streams records to w
+func NewLogExporter(w Sink) *LogExporter {
+	return &LogExporter{w: w}
+}
+
+func (e *LogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	lines := make([]interface{}, 0, len(records))
+	for _, r := range records {
+		lines = append(lines, encodeLog(r))
+	}
+	return writeLines(ctx, e.w, lines)
+}
+
+func (e *LogExporter) ForceFlush(context.Context) error {
+	return flush(e.w)
+}
+
+func (e *LogExporter) Shutdown(context.Context) error {
+	return e.w.Close()
+}
@@ -0,0 +1,218 @@
+// Package socket provides exporters that stream telemetry as newline delimited JSON
+// to a raw TCP or UDP listener, for ingest paths that don't speak OTLP, or to stdout.
+package socket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// dialTimeout bounds how long connecting to the listener may take
+const dialTimeout = 5 * time.Second
+
+// ParseAddress splits a tcp://host:port or udp://host:port output into the network
+// and address to dial
+func ParseAddress(output string) (network, address string, err error) {
+	u, err := url.Parse(output)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid socket output %q: %w", output, err)
+	}
+	if u.Scheme != "tcp" && u.Scheme != "udp" {
+		return "", "", fmt.Errorf("unsupported socket scheme: %s, use one of: tcp, udp", u.Scheme)
+	}
+	if u.Host == "" || u.Port() == "" {
+		return "", "", fmt.Errorf("socket output %q must be of the form %s://host:port", output, u.Scheme)
+	}
+	return u.Scheme, u.Host, nil
+}
+
+// Sink receives the encoded frames of the exporters
+type Sink interface {
+	WriteFrames(ctx context.Context, frames [][]byte) error
+	Close() error
+}
+
+// writeLines encodes each value as a JSON line and writes them to sink
+func writeLines(ctx context.Context, sink Sink, values []interface{}) error {
+	lines := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode telemetry: %w", err)
+		}
+		lines = append(lines, append(b, '\n'))
+	}
+	return sink.WriteFrames(ctx, lines)
+}
+
+// streamSink writes frames to an io.Writer that outlives the exporters, batching
+// them in a buffer when one is configured
+type streamSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	buf    *bufio.Writer
+	pretty bool
+}
+
+// Stdout returns a sink writing frames to standard output, indenting each one when
+// pretty is set and leaving them as compact ndjson otherwise. A bufferSize above 0
+// batches the writes in a buffer of that many bytes, flushed when full, by Flush
+// and on Close.
+func Stdout(pretty bool, bufferSize int) Sink {
+	return newStreamSink(os.Stdout, pretty, bufferSize)
+}
+
+func newStreamSink(w io.Writer, pretty bool, bufferSize int) *streamSink {
+	s := &streamSink{w: w, pretty: pretty}
+	if bufferSize > 0 {
+		s.buf = bufio.NewWriterSize(w, bufferSize)
+	}
+	return s
+}
+
+func (s *streamSink) WriteFrames(_ context.Context, frames [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range frames {
+		if s.pretty {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, f, "", "  "); err != nil {
+				return fmt.Errorf("failed to indent telemetry: %w", err)
+			}
+			f = buf.Bytes()
+		}
+		if err := s.write(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes a frame through the buffer when there is one, flushing what's
+// buffered first when the frame doesn't fit so a frame is never split across writes
+func (s *streamSink) write(f []byte) error {
+	if s.buf == nil {
+		_, err := s.w.Write(f)
+		return err
+	}
+	if s.buf.Buffered() > 0 && s.buf.Available() < len(f) {
+		if err := s.buf.Flush(); err != nil {
+			return err
+		}
+	}
+	_, err := s.buf.Write(f)
+	return err
+}
+
+// Flush writes any buffered frames to the underlying writer
+func (s *streamSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buf == nil {
+		return nil
+	}
+	return s.buf.Flush()
+}
+
+// Close flushes any buffered frames but leaves the underlying writer open, it
+// isn't owned by the sink
+func (s *streamSink) Close() error {
+	return s.Flush()
+}
+
+// Writer writes ndjson lines to a socket, dialling lazily and reconnecting after
+// a failed write. It's safe for concurrent use and shared by the signal exporters.
+type Writer struct {
+	network string
+	address string
+	logger  *zap.Logger
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewWriter returns a writer for the tcp:// or udp:// output
+func NewWriter(output string, logger *zap.Logger) (*Writer, error) {
+	network, address, err := ParseAddress(output)
+	if err != nil {
+		return nil, err
+	}
+	return NewNetworkWriter(network, address, logger), nil
+}
+
+// NewNetworkWriter returns a writer that dials address on network, tcp or udp
+func NewNetworkWriter(network, address string, logger *zap.Logger) *Writer {
+	return &Writer{network: network, address: address, logger: logger}
+}
+
+// WriteFrames writes each frame to the socket as is. A failed write drops the
+// connection and is retried once on a fresh one.
+func (w *Writer) WriteFrames(ctx context.Context, frames [][]byte) error {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.write(ctx, frames)
+	if err == nil {
+		return nil
+	}
+	w.logger.Warn("socket write failed, reconnecting", zap.String("address", w.address), zap.Error(err))
+	w.close()
+	return w.write(ctx, frames)
+}
+
+// write sends every frame on the current connection, dialling one if needed. Frames
+// are written individually so each UDP datagram carries a single record.
+func (w *Writer) write(ctx context.Context, lines [][]byte) error {
+	if w.conn == nil {
+		d := net.Dialer{Timeout: dialTimeout}
+		conn, err := d.DialContext(ctx, w.network, w.address)
+		if err != nil {
+			return fmt.Errorf("failed to dial %s://%s: %w", w.network, w.address, err)
+		}
+		w.conn = conn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = w.conn.SetWriteDeadline(deadline)
+	} else {
+		_ = w.conn.SetWriteDeadline(time.Time{})
+	}
+
+	for _, line := range lines {
+		if _, err := w.conn.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the connection, a later write dials a new one
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.close()
+}
+
+func (w *Writer) close() error {
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,284 @@
+package socket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+var gauge = &metricdata.ResourceMetrics{
+	Resource: resource.Empty(),
+	ScopeMetrics: []metricdata.ScopeMetrics{{
+		Metrics: []metricdata.Metrics{{
+			Name: "otelgen.gauge",
+			Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Time: time.Unix(0, 0), Value: 1}}},
+		}},
+	}},
+}
+
+func TestStreamSinkFlush(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		flush      func(e *MetricExporter) error
+		// wantBeforeFlush is whether the data point reaches the writer before it's flushed
+		wantBeforeFlush bool
+	}{
+		{"unbuffered", 0, func(e *MetricExporter) error { return e.ForceFlush(context.Background()) }, true},
+		{"force flush", 4096, func(e *MetricExporter) error { return e.ForceFlush(context.Background()) }, false},
+		{"shutdown", 4096, func(e *MetricExporter) error { return e.Shutdown(context.Background()) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			e := NewMetricExporter(newStreamSink(&out, false, tt.bufferSize), sdkmetric.DefaultTemporalitySelector)
+			if err := e.Export(context.Background(), gauge); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.Len() > 0; got != tt.wantBeforeFlush {
+				t.Errorf("written before flush = %t, want %t", got, tt.wantBeforeFlush)
+			}
+			if err := tt.flush(e); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), `"name":"otelgen.gauge"`) || !strings.HasSuffix(out.String(), "\n") {
+				t.Errorf("written after flush = %q, want the data point as a JSON line", out.String())
+			}
+		})
+	}
+}
+
+// writeRecorder keeps every write to tell whether a frame was split across them
+type writeRecorder struct {
+	writes []string
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestStreamSinkKeepsFramesWhole(t *testing.T) {
+	frames := [][]byte{[]byte("{\"a\":1}\n"), []byte("{\"b\":22}\n"), []byte("{\"c\":333}\n"), []byte("{\"d\":4444}\n")}
+	w := &writeRecorder{}
+	s := newStreamSink(w, false, 20)
+	if err := s.WriteFrames(context.Background(), frames); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var all string
+	for _, write := range w.writes {
+		if !strings.HasSuffix(write, "\n") {
+			t.Errorf("write %q splits a frame", write)
+		}
+		all += write
+	}
+	if want := string(bytes.Join(frames, nil)); all != want {
+		t.Errorf("written = %q, want %q", all, want)
+	}
+	if len(w.writes) >= len(frames) {
+		t.Errorf("got %d writes for %d frames, want them batched", len(w.writes), len(frames))
+	}
+}
+
+// countingWriter counts the writes reaching it, standing in for stdout
+type countingWriter struct {
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return len(p), nil
+}
+
+func BenchmarkStreamSink(b *testing.B) {
+	for _, bench := range []struct {
+		name       string
+		bufferSize int
+	}{
+		{"unbuffered", 0},
+		{"64KiB", 64 << 10},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			w := &countingWriter{}
+			e := NewMetricExporter(newStreamSink(w, false, bench.bufferSize), sdkmetric.DefaultTemporalitySelector)
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := e.Export(ctx, gauge); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if err := e.Shutdown(ctx); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(w.writes)/float64(b.N), "writes/op")
+		})
+	}
+}
+
+// acceptLines accepts connections on ln, sending every line read from them on the
+// returned channel
+func acceptLines(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+	lines := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return lines
+}
+
+// signals export one item of each signal to a sink, and what its line contains
+var signals = []struct {
+	name   string
+	export func(w Sink) error
+	want   string
+}{
+	{
+		name: "spans",
+		export: func(w Sink) error {
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewSpanExporter(w)))
+			_, span := tp.Tracer("test").Start(context.Background(), "otelgen.span")
+			span.End()
+			return tp.Shutdown(context.Background())
+		},
+		want: `"name":"otelgen.span"`,
+	},
+	{
+		name: "metrics",
+		export: func(w Sink) error {
+			return NewMetricExporter(w, sdkmetric.DefaultTemporalitySelector).Export(context.Background(), gauge)
+		},
+		want: `"name":"otelgen.gauge"`,
+	},
+	{
+		name: "logs",
+		export: func(w Sink) error {
+			lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(NewLogExporter(w))))
+			var r log.Record
+			r.SetBody(log.StringValue("otelgen.log"))
+			lp.Logger("test").Emit(context.Background(), r)
+			return lp.Shutdown(context.Background())
+		},
+		want: `"body":"otelgen.log"`,
+	},
+}
+
+func TestWriterTCP(t *testing.T) {
+	for _, tt := range signals {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+			lines := acceptLines(t, ln)
+
+			w, err := NewWriter("tcp://"+ln.Addr().String(), zap.NewNop())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			if err := tt.export(w); err != nil {
+				t.Fatalf("export error = %v", err)
+			}
+
+			select {
+			case line := <-lines:
+				if !strings.Contains(line, tt.want) {
+					t.Errorf("received %q, want it to contain %s", line, tt.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("the listener received nothing")
+			}
+		})
+	}
+}
+
+func TestWriterReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	w := NewNetworkWriter("tcp", ln.Addr().String(), zap.NewNop())
+	defer w.Close()
+	for _, frame := range []string{"first", "second"} {
+		if err := w.WriteFrames(context.Background(), [][]byte{[]byte(frame + "\n")}); err != nil {
+			t.Fatalf("WriteFrames(%s) error = %v", frame, err)
+		}
+		select {
+		case got := <-lines:
+			if got != frame {
+				t.Errorf("received %q, want %q", got, frame)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("the listener never received %q", frame)
+		}
+		// Drop the connection, the next write has to dial a new one
+		_ = w.Close()
+	}
+}
+
+func TestStreamSinkPretty(t *testing.T) {
+	for _, pretty := range []bool{true, false} {
+		for _, sig := range signals {
+			t.Run(fmt.Sprintf("%s pretty=%t", sig.name, pretty), func(t *testing.T) {
+				var out bytes.Buffer
+				if err := sig.export(newStreamSink(&out, pretty, 0)); err != nil {
+					t.Fatal(err)
+				}
+				got := out.String()
+				if !strings.HasSuffix(got, "\n") {
+					t.Fatalf("output %q doesn't end its line", got)
+				}
+				if lines := strings.Count(got, "\n"); (lines == 1) == pretty {
+					t.Errorf("output spans %d lines with pretty=%t:\n%s", lines, pretty, got)
+				}
+				if pretty && !strings.Contains(got, "\n  \"") {
+					t.Errorf("output isn't indented:\n%s", got)
+				}
+
+				var compact bytes.Buffer
+				if err := json.Compact(&compact, out.Bytes()); err != nil {
+					t.Fatalf("output isn't a single JSON value: %v\n%s", err, got)
+				}
+				if !strings.Contains(compact.String(), sig.want) {
+					t.Errorf("output %s doesn't contain %s", compact.String(), sig.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package summary records the outcome of a run and writes it to a JSON file, giving CI
+// jobs something simpler to assert on than the log output.
+package summary
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/krzko/otelgen/internal/exportstats"
+)
+
+// Statuses a run can end with
+const (
+	Completed   = "completed"
+	Failed      = "failed"
+	Interrupted = "interrupted"
+)
+
+// MaxErrors is how many distinct error messages a summary keeps
+const MaxErrors = 20
+
+// Signal is the export outcome of a single signal
+type Signal struct {
+	ExportsSucceeded int64 `json:"exports_succeeded"`
+	ExportsFailed    int64 `json:"exports_failed"`
+	Items            int64 `json:"items"`
+	// Rejected counts the items receivers dropped from exports they otherwise accepted
+	Rejected int64   `json:"rejected"`
+	Rate     float64 `json:"rate"`
+}
+
+// Report is the JSON document written at the end of a run
+type Report struct {
+	Command          string             `json:"command"`
+	Status           string             `json:"status"`
+	StartedAt        time.Time          `json:"started_at"`
+	EndedAt          time.Time          `json:"ended_at"`
+	DurationSeconds  float64            `json:"duration_seconds"`
+	ExportsSucceeded int64              `json:"exports_succeeded"`
+	ExportsFailed    int64              `json:"exports_failed"`
+	Items            int64              `json:"items"`
+	Rejected         int64              `json:"rejected"`
+	Rate             float64            `json:"rate"`
+	Errors           []string           `json:"errors"`
+	Signals          map[string]*Signal `json:"signals"`
+}
+
+// Summary reports the exports of a run from the stats of its exporters. A nil
+// Summary records nothing.
+type Summary struct {
+	command string
+	start   time.Time
+
+	mu       sync.Mutex
+	stats    []*exportstats.Stats
+	rejected map[string]int64
+	errors   []string
+	seen     map[string]bool
+	written  bool
+}
+
+// New returns a summary of command, started now
+func New(command string) *Summary {
+	return &Summary{
+		command:  command,
+		start:    time.Now(),
+		rejected: map[string]int64{},
+		seen:     map[string]bool{},
+	}
+}
+
+// Track counts the exports of stats towards the summary, recording the errors of
+// those that fail, and returns stats
+func (s *Summary) Track(stats *exportstats.Stats) *exportstats.Stats {
+	if s == nil {
+		return stats
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats = append(s.stats, stats)
+	return stats.OnError(s.Error)
+}
+
+// Reject records that a receiver dropped n items of signal from an accepted export,
+// err describing why
+func (s *Summary) Reject(signal string, n int64, err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejected[signal] += n
+	s.addError(err)
+}
+
+// Error records err against the run, keeping the first MaxErrors distinct messages
+func (s *Summary) Error(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addError(err)
+}
+
+func (s *Summary) addError(err error) {
+	msg := err.Error()
+	if s.seen[msg] || len(s.errors) >= MaxErrors {
+		return
+	}
+	s.seen[msg] = true
+	s.errors = append(s.errors, msg)
+}
+
+// report returns the summary as it stands, ending with status
+func (s *Summary) report(status string) Report {
+	end := time.Now()
+	elapsed := end.Sub(s.start).Seconds()
+	r := Report{
+		Command:         s.command,
+		Status:          status,
+		StartedAt:       s.start,
+		EndedAt:         end,
+		DurationSeconds: elapsed,
+		Errors:          append([]string{}, s.errors...),
+		Signals:         map[string]*Signal{},
+	}
+	signal := func(name string) *Signal {
+		if _, ok := r.Signals[name]; !ok {
+			r.Signals[name] = &Signal{}
+		}
+		return r.Signals[name]
+	}
+	for _, stats := range s.stats {
+		counts, sig := stats.Counts(), signal(stats.Signal())
+		sig.ExportsSucceeded += counts.Succeeded
+		sig.ExportsFailed += counts.Failed
+		sig.Items += counts.Items
+	}
+	for name, n := range s.rejected {
+		signal(name).Rejected += n
+	}
+
+	for _, sig := range r.Signals {
+		sig.Rate = rate(sig.Items, elapsed)
+		r.ExportsSucceeded += sig.ExportsSucceeded
+		r.ExportsFailed += sig.ExportsFailed
+		r.Items += sig.Items
+		r.Rejected += sig.Rejected
+	}
+	r.Rate = rate(r.Items, elapsed)
+	return r
+}
+
+// rate returns items per second over elapsed seconds
+func rate(items int64, elapsed float64) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(items) / elapsed
+}
+
+// WriteFile writes the summary to path as JSON, indented when pretty is set, ending
+// the run with status. Only the first call writes, so a run interrupted while
+// finishing keeps one summary.
+func (s *Summary) WriteFile(path, status string, pretty bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.written {
+		return nil
+	}
+	s.written = true
+
+	var b []byte
+	var err error
+	if pretty {
+		b, err = json.MarshalIndent(s.report(status), "", "  ")
+	} else {
+		b, err = json.Marshal(s.report(status))
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
@@ -0,0 +1,127 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krzko/otelgen/internal/exportstats"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+var errExport = errors.New("collector unavailable")
+
+// mockExporter fails every export while failing is set
+type mockExporter struct {
+	sdklog.Exporter
+	failing bool
+}
+
+func (e *mockExporter) Export(context.Context, []sdklog.Record) error {
+	if e.failing {
+		return errExport
+	}
+	return nil
+}
+
+// readReport returns the report written to path
+func readReport(t *testing.T, path string) Report {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("the summary wasn't written: %v", err)
+	}
+	var r Report
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("the summary isn't JSON: %v\n%s", err, b)
+	}
+	return r
+}
+
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		pretty bool
+	}{
+		{name: "completed", status: Completed},
+		{name: "interrupted", status: Interrupted, pretty: true},
+		{name: "failed", status: Failed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("logs multi")
+			mock := &mockExporter{}
+			exp := s.Track(exportstats.New("logs", nil)).LogExporter(mock)
+			_ = exp.Export(context.Background(), make([]sdklog.Record, 3))
+			_ = exp.Export(context.Background(), make([]sdklog.Record, 2))
+			mock.failing = true
+			_ = exp.Export(context.Background(), make([]sdklog.Record, 4))
+			s.Reject("logs", 1, errors.New("1 log record rejected"))
+
+			path := filepath.Join(t.TempDir(), "summary.json")
+			if err := s.WriteFile(path, tt.status, tt.pretty); err != nil {
+				t.Fatal(err)
+			}
+			r := readReport(t, path)
+
+			if r.Command != "logs multi" || r.Status != tt.status {
+				t.Errorf("command %q and status %q, want %q and %q", r.Command, r.Status, "logs multi", tt.status)
+			}
+			if r.StartedAt.IsZero() || r.EndedAt.Before(r.StartedAt) || r.DurationSeconds <= 0 {
+				t.Errorf("started %v, ended %v, lasting %vs", r.StartedAt, r.EndedAt, r.DurationSeconds)
+			}
+			if r.ExportsSucceeded != 2 || r.ExportsFailed != 1 || r.Items != 5 || r.Rejected != 1 || r.Rate <= 0 {
+				t.Errorf("report = %+v, want 2 exports succeeded, 1 failed, 5 items and 1 rejected", r)
+			}
+			logs, ok := r.Signals["logs"]
+			if !ok {
+				t.Fatalf("signals = %v, want logs", r.Signals)
+			}
+			if logs.Items != r.Items || logs.Rejected != r.Rejected {
+				t.Errorf("logs = %+v, want the run's totals", *logs)
+			}
+			if len(r.Errors) != 2 {
+				t.Errorf("errors = %q, want the export failure and the rejection", r.Errors)
+			}
+		})
+	}
+}
+
+func TestWriteFileOnce(t *testing.T) {
+	s := New("traces")
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := s.WriteFile(path, Interrupted, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteFile(path, Completed, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := readReport(t, path).Status; got != Interrupted {
+		t.Errorf("status = %q, want the first summary's %q", got, Interrupted)
+	}
+}
+
+func TestErrorsAreCapped(t *testing.T) {
+	s := New("metrics")
+	for i := 0; i < MaxErrors+5; i++ {
+		s.Error(errors.New(string(rune('a' + i))))
+		s.Error(errors.New("repeated"))
+	}
+	if got := len(s.report(Completed).Errors); got != MaxErrors {
+		t.Errorf("kept %d errors, want %d", got, MaxErrors)
+	}
+}
+
+func TestNilSummary(t *testing.T) {
+	var s *Summary
+	stats := exportstats.New("logs", nil)
+	if got := s.Track(stats); got != stats {
+		t.Error("a nil summary didn't hand back the stats")
+	}
+	s.Reject("logs", 1, errExport)
+	s.Error(errExport)
+}
@@ -0,0 +1,143 @@
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/zap"
+)
+
+// header matches an RFC 5424 message, capturing its priority, timestamp, host,
+// app name, msgid, structured data and message
+var header = regexp.MustCompile(`^<(\d{1,3})>1 (\S+) (\S+) (\S+) - (\S+) (-|\[.*\]) (.*)$`)
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		severity log.Severity
+		want     int
+	}{
+		{log.SeverityTrace1, severityDebug},
+		{log.SeverityDebug4, severityDebug},
+		{log.SeverityInfo1, severityInformational},
+		{log.SeverityWarn2, severityWarning},
+		{log.SeverityError1, severityError},
+		{log.SeverityFatal4, severityCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity.String(), func(t *testing.T) {
+			if got := Severity(tt.severity); got != tt.want {
+				t.Errorf("Severity(%v) = %d, want %d", tt.severity, got, tt.want)
+			}
+		})
+	}
+}
+
+// emit exports a single error record through a logger provider on exp
+func emit(t *testing.T, exp sdklog.Exporter, ts time.Time) {
+	t.Helper()
+	res := resource.NewSchemaless(semconv.ServiceName("checkout"), semconv.HostName("node-1"))
+	lp := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)),
+	)
+	var r log.Record
+	r.SetTimestamp(ts)
+	r.SetSeverity(log.SeverityError1)
+	r.SetSeverityText("ERROR")
+	r.SetBody(log.StringValue("payment declined"))
+	lp.Logger("test").Emit(context.Background(), r)
+	if err := lp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogExporterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	exp, err := NewLogExporter("syslog://"+conn.LocalAddr().String(), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	emit(t, exp, ts)
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("the listener received nothing: %v", err)
+	}
+
+	m := header.FindStringSubmatch(string(buf[:n]))
+	if m == nil {
+		t.Fatalf("received %q, want an RFC 5424 message", buf[:n])
+	}
+	want := []string{fmt.Sprint(facilityUser*8 + severityError), ts.Format(time.RFC3339Nano), "node-1", "checkout", "ERROR", "-", "payment declined"}
+	for i, field := range []string{"priority", "timestamp", "hostname", "app-name", "msgid", "structured data", "message"} {
+		if m[i+1] != want[i] {
+			t.Errorf("%s = %q, want %q", field, m[i+1], want[i])
+		}
+	}
+}
+
+func TestLogExporterTCPOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		length, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+		if err != nil {
+			received <- "bad length " + length
+			return
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return
+		}
+		received <- string(msg)
+	}()
+
+	exp, err := NewLogExporter("syslog+tcp://"+ln.Addr().String(), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	emit(t, exp, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	select {
+	case msg := <-received:
+		if !header.MatchString(msg) || !strings.HasSuffix(msg, " payment declined") {
+			t.Errorf("received frame %q, want exactly one RFC 5424 message", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the listener received nothing")
+	}
+}
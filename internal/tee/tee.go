@@ -0,0 +1,81 @@
+// Package tee provides exporters that hand every export to two exporters, such as
+// OTLP and a local copy printed for debugging a remote run.
+package tee
+
+import (
+	"context"
+	"errors"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type spanExporter struct {
+	primary, secondary sdktrace.SpanExporter
+}
+
+// SpanExporter returns an exporter that exports spans to both primary and secondary
+func SpanExporter(primary, secondary sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return &spanExporter{primary: primary, secondary: secondary}
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return errors.Join(e.primary.ExportSpans(ctx, spans), e.secondary.ExportSpans(ctx, spans))
+}
+
+func (e *spanExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.primary.Shutdown(ctx), e.secondary.Shutdown(ctx))
+}
+
+type metricExporter struct {
+	primary, secondary sdkmetric.Exporter
+}
+
+// MetricExporter returns an exporter that exports metrics to both primary and
+// secondary, taking its temporality and aggregation from primary
+func MetricExporter(primary, secondary sdkmetric.Exporter) sdkmetric.Exporter {
+	return &metricExporter{primary: primary, secondary: secondary}
+}
+
+func (e *metricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return e.primary.Temporality(kind)
+}
+
+func (e *metricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return e.primary.Aggregation(kind)
+}
+
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return errors.Join(e.primary.Export(ctx, rm), e.secondary.Export(ctx, rm))
+}
+
+func (e *metricExporter) ForceFlush(ctx context.Context) error {
+	return errors.Join(e.primary.ForceFlush(ctx), e.secondary.ForceFlush(ctx))
+}
+
+func (e *metricExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.primary.Shutdown(ctx), e.secondary.Shutdown(ctx))
+}
+
+type logExporter struct {
+	primary, secondary sdklog.Exporter
+}
+
+// LogExporter returns an exporter that exports log records to both primary and secondary
+func LogExporter(primary, secondary sdklog.Exporter) sdklog.Exporter {
+	return &logExporter{primary: primary, secondary: secondary}
+}
+
+func (e *logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	return errors.Join(e.primary.Export(ctx, records), e.secondary.Export(ctx, records))
+}
+
+func (e *logExporter) ForceFlush(ctx context.Context) error {
+	return errors.Join(e.primary.ForceFlush(ctx), e.secondary.ForceFlush(ctx))
+}
+
+func (e *logExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.primary.Shutdown(ctx), e.secondary.Shutdown(ctx))
+}
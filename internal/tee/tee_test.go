@@ -0,0 +1,147 @@
+package tee
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var errExport = errors.New("export failed")
+
+// mockExporter records the calls made on it for every signal, failing them with err
+type mockExporter struct {
+	sdkmetric.Exporter
+	err      error
+	spans    []string
+	metrics  []*metricdata.ResourceMetrics
+	logs     []string
+	flushed  bool
+	shutdown bool
+}
+
+func (e *mockExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, s := range spans {
+		e.spans = append(e.spans, s.Name())
+	}
+	return e.err
+}
+
+func (e *mockExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.metrics = append(e.metrics, rm)
+	return e.err
+}
+
+func (e *mockExporter) ExportLogs(_ context.Context, records []sdklog.Record) error {
+	for _, r := range records {
+		e.logs = append(e.logs, r.Body().AsString())
+	}
+	return e.err
+}
+
+func (e *mockExporter) ForceFlush(context.Context) error {
+	e.flushed = true
+	return e.err
+}
+
+func (e *mockExporter) Shutdown(context.Context) error {
+	e.shutdown = true
+	return e.err
+}
+
+// logMock adapts mockExporter to sdklog.Exporter, whose Export differs from the metric one
+type logMock struct{ *mockExporter }
+
+func (e logMock) Export(ctx context.Context, records []sdklog.Record) error {
+	return e.ExportLogs(ctx, records)
+}
+
+func TestExporters(t *testing.T) {
+	tests := []struct {
+		name string
+		// export sends one item named "item" through a tee of primary and secondary,
+		// then flushes and shuts it down, returning the export error
+		export func(primary, secondary *mockExporter) error
+		got    func(e *mockExporter) []string
+		// flushes is whether the exporter has a ForceFlush to pass on
+		flushes bool
+	}{
+		{
+			name: "spans",
+			export: func(primary, secondary *mockExporter) error {
+				exp := SpanExporter(primary, secondary)
+				spans := tracetest.SpanStubs{{Name: "item"}}.Snapshots()
+				err := exp.ExportSpans(context.Background(), spans)
+				_ = exp.Shutdown(context.Background())
+				return err
+			},
+			got:     func(e *mockExporter) []string { return e.spans },
+			flushes: false,
+		},
+		{
+			name: "metrics",
+			export: func(primary, secondary *mockExporter) error {
+				exp := MetricExporter(primary, secondary)
+				rm := &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{{Name: "item"}}}}}
+				err := exp.Export(context.Background(), rm)
+				_ = exp.ForceFlush(context.Background())
+				_ = exp.Shutdown(context.Background())
+				return err
+			},
+			got: func(e *mockExporter) []string {
+				var names []string
+				for _, rm := range e.metrics {
+					names = append(names, rm.ScopeMetrics[0].Metrics[0].Name)
+				}
+				return names
+			},
+			flushes: true,
+		},
+		{
+			name: "logs",
+			export: func(primary, secondary *mockExporter) error {
+				exp := LogExporter(logMock{primary}, logMock{secondary})
+				var r sdklog.Record
+				r.SetBody(log.StringValue("item"))
+				err := exp.Export(context.Background(), []sdklog.Record{r})
+				_ = exp.ForceFlush(context.Background())
+				_ = exp.Shutdown(context.Background())
+				return err
+			},
+			got:     func(e *mockExporter) []string { return e.logs },
+			flushes: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, failing := range []string{"", "primary", "secondary"} {
+				primary, secondary := &mockExporter{}, &mockExporter{}
+				switch failing {
+				case "primary":
+					primary.err = errExport
+				case "secondary":
+					secondary.err = errExport
+				}
+
+				err := tt.export(primary, secondary)
+				if !errors.Is(err, errExport) != (failing == "") {
+					t.Errorf("failing %q: export error = %v", failing, err)
+				}
+				for side, e := range map[string]*mockExporter{"primary": primary, "secondary": secondary} {
+					if got := tt.got(e); len(got) != 1 || got[0] != "item" {
+						t.Errorf("failing %q: %s received %v, want [item]", failing, side, got)
+					}
+					if e.flushed != tt.flushes || !e.shutdown {
+						t.Errorf("failing %q: %s flushed = %t, shut down = %t, want %t and true", failing, side, e.flushed, e.shutdown, tt.flushes)
+					}
+				}
+			}
+		})
+	}
+}
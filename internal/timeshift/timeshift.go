@@ -0,0 +1,232 @@
+// Package timeshift wraps exporters to move the timestamps of exported telemetry into
+// the past, simulating the ingestion of historical data. Timestamps are either offset
+// by a fixed duration or replayed across a fixed window.
+package timeshift
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Shift maps the time telemetry was recorded at onto the time it's exported with,
+// a nil Shift leaves timestamps untouched
+type Shift func(time.Time) time.Time
+
+// Offset returns a shift that moves timestamps offset into the past, nil when
+// offset is not positive
+func Offset(offset time.Duration) Shift {
+	if offset <= 0 {
+		return nil
+	}
+	return func(t time.Time) time.Time {
+		return t.Add(-offset)
+	}
+}
+
+// Window is a historical time range that a run of a known duration is replayed across
+type Window struct {
+	Since    time.Time
+	Until    time.Time
+	Duration time.Duration
+	// Clock tells when the run starts, the real clock is used when nil
+	Clock clock.Clock
+}
+
+// NewWindow returns the window [since, until] replayed over a run of duration
+func NewWindow(since, until time.Time, duration time.Duration) (*Window, error) {
+	if !since.Before(until) {
+		return nil, fmt.Errorf("window start %s must be before its end %s", since.Format(time.RFC3339), until.Format(time.RFC3339))
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("a window can only be replayed over a run with a duration")
+	}
+	return &Window{Since: since, Until: until, Duration: duration}, nil
+}
+
+// Shift returns a shift that scales the time elapsed since now onto the window, so
+// telemetry recorded evenly over the run lands evenly across [Since, Until]. Times
+// before now map to Since and times after the run map to Until.
+func (w *Window) Shift() Shift {
+	clk := w.Clock
+	if clk == nil {
+		clk = clock.Real()
+	}
+	start := clk.Now()
+	scale := float64(w.Until.Sub(w.Since)) / float64(w.Duration)
+	return func(t time.Time) time.Time {
+		elapsed := t.Sub(start)
+		if elapsed <= 0 {
+			return w.Since
+		}
+		mapped := w.Since.Add(time.Duration(float64(elapsed) * scale))
+		if mapped.After(w.Until) {
+			return w.Until
+		}
+		return mapped
+	}
+}
+
+// New returns the shift replaying window when set, otherwise the one offsetting
+// timestamps by offset
+func New(offset time.Duration, window *Window) Shift {
+	if window != nil {
+		return window.Shift()
+	}
+	return Offset(offset)
+}
+
+type spanExporter struct {
+	sdktrace.SpanExporter
+	shift Shift
+}
+
+// SpanExporter wraps exp to export spans and their events with shifted timestamps,
+// returning exp unwrapped when shift is nil
+func SpanExporter(exp sdktrace.SpanExporter, shift Shift) sdktrace.SpanExporter {
+	if shift == nil {
+		return exp
+	}
+	return &spanExporter{SpanExporter: exp, shift: shift}
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	shifted := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, s := range spans {
+		shifted[i] = &span{ReadOnlySpan: s, shift: e.shift}
+	}
+	return e.SpanExporter.ExportSpans(ctx, shifted)
+}
+
+// span reports the shifted timestamps of the wrapped span
+type span struct {
+	sdktrace.ReadOnlySpan
+	shift Shift
+}
+
+func (s *span) StartTime() time.Time {
+	return s.shift(s.ReadOnlySpan.StartTime())
+}
+
+func (s *span) EndTime() time.Time {
+	return s.shift(s.ReadOnlySpan.EndTime())
+}
+
+func (s *span) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	for i := range events {
+		events[i].Time = s.shift(events[i].Time)
+	}
+	return events
+}
+
+type metricExporter struct {
+	sdkmetric.Exporter
+	shift Shift
+}
+
+// MetricExporter wraps exp to export data points with shifted start and observed
+// times, returning exp unwrapped when shift is nil
+func MetricExporter(exp sdkmetric.Exporter, shift Shift) sdkmetric.Exporter {
+	if shift == nil {
+		return exp
+	}
+	return &metricExporter{Exporter: exp, shift: shift}
+}
+
+// Export shifts the data points in place, the aggregators rewrite every timestamp
+// on the next collection so the shift never accumulates
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			shiftAggregation(m.Data, e.shift)
+		}
+	}
+	return e.Exporter.Export(ctx, rm)
+}
+
+func shiftAggregation(data metricdata.Aggregation, shift Shift) {
+	switch d := data.(type) {
+	case metricdata.Gauge[int64]:
+		shiftDataPoints(d.DataPoints, shift)
+	case metricdata.Gauge[float64]:
+		shiftDataPoints(d.DataPoints, shift)
+	case metricdata.Sum[int64]:
+		shiftDataPoints(d.DataPoints, shift)
+	case metricdata.Sum[float64]:
+		shiftDataPoints(d.DataPoints, shift)
+	case metricdata.Histogram[int64]:
+		shiftHistogramDataPoints(d.DataPoints, shift)
+	case metricdata.Histogram[float64]:
+		shiftHistogramDataPoints(d.DataPoints, shift)
+	case metricdata.ExponentialHistogram[int64]:
+		shiftExponentialHistogramDataPoints(d.DataPoints, shift)
+	case metricdata.ExponentialHistogram[float64]:
+		shiftExponentialHistogramDataPoints(d.DataPoints, shift)
+	}
+}
+
+func shiftDataPoints[N int64 | float64](dps []metricdata.DataPoint[N], shift Shift) {
+	for i := range dps {
+		dps[i].StartTime = shift(dps[i].StartTime)
+		dps[i].Time = shift(dps[i].Time)
+		shiftExemplars(dps[i].Exemplars, shift)
+	}
+}
+
+func shiftHistogramDataPoints[N int64 | float64](dps []metricdata.HistogramDataPoint[N], shift Shift) {
+	for i := range dps {
+		dps[i].StartTime = shift(dps[i].StartTime)
+		dps[i].Time = shift(dps[i].Time)
+		shiftExemplars(dps[i].Exemplars, shift)
+	}
+}
+
+func shiftExponentialHistogramDataPoints[N int64 | float64](dps []metricdata.ExponentialHistogramDataPoint[N], shift Shift) {
+	for i := range dps {
+		dps[i].StartTime = shift(dps[i].StartTime)
+		dps[i].Time = shift(dps[i].Time)
+		shiftExemplars(dps[i].Exemplars, shift)
+	}
+}
+
+func shiftExemplars[N int64 | float64](exemplars []metricdata.Exemplar[N], shift Shift) {
+	for i := range exemplars {
+		exemplars[i].Time = shift(exemplars[i].Time)
+	}
+}
+
+type logExporter struct {
+	sdklog.Exporter
+	shift Shift
+}
+
+// LogExporter wraps exp to export log records with shifted timestamps, returning
+// exp unwrapped when shift is nil
+func LogExporter(exp sdklog.Exporter, shift Shift) sdklog.Exporter {
+	if shift == nil {
+		return exp
+	}
+	return &logExporter{Exporter: exp, shift: shift}
+}
+
+func (e *logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	// The processor owns records, so the shifted copies are exported instead
+	shifted := make([]sdklog.Record, len(records))
+	for i, r := range records {
+		if ts := r.Timestamp(); !ts.IsZero() {
+			r.SetTimestamp(e.shift(ts))
+		}
+		if ts := r.ObservedTimestamp(); !ts.IsZero() {
+			r.SetObservedTimestamp(e.shift(ts))
+		}
+		shifted[i] = r
+	}
+	return e.Exporter.Export(ctx, shifted)
+}
@@ -0,0 +1,274 @@
+package timeshift
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krzko/otelgen/internal/clock"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var start = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   time.Time
+	}{
+		{"one hour", time.Hour, start.Add(-time.Hour)},
+		{"one day", 24 * time.Hour, start.Add(-24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Offset(tt.offset)(start); !got.Equal(tt.want) {
+				t.Errorf("Offset(%s)(%s) = %s, want %s", tt.offset, start, got, tt.want)
+			}
+		})
+	}
+
+	for _, offset := range []time.Duration{0, -time.Hour} {
+		if Offset(offset) != nil {
+			t.Errorf("Offset(%s) isn't nil", offset)
+		}
+	}
+}
+
+func TestWindowShift(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(10 * time.Hour)
+	w, err := NewWindow(since, until, 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Clock = clock.NewFake(start)
+	shift := w.Shift()
+
+	// Every minute of the run covers an hour of the window
+	tests := []struct {
+		name     string
+		recorded time.Time
+		want     time.Time
+	}{
+		{"before the run", start.Add(-time.Second), since},
+		{"at the start", start, since},
+		{"after a minute", start.Add(time.Minute), since.Add(time.Hour)},
+		{"half way", start.Add(5 * time.Minute), since.Add(5 * time.Hour)},
+		{"at the end", start.Add(10 * time.Minute), until},
+		{"after the run", start.Add(time.Hour), until},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shift(tt.recorded); !got.Equal(tt.want) {
+				t.Errorf("shift(%s) = %s, want %s", tt.recorded, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		since    time.Time
+		until    time.Time
+		duration time.Duration
+		wantErr  bool
+	}{
+		{"valid", start, start.Add(time.Hour), time.Minute, false},
+		{"empty", start, start, time.Minute, true},
+		{"reversed", start.Add(time.Hour), start, time.Minute, true},
+		{"no duration", start, start.Add(time.Hour), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWindow(tt.since, tt.until, tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewWindow() error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if New(0, nil) != nil {
+		t.Error("New without an offset or window shifts timestamps")
+	}
+
+	w, err := NewWindow(start.Add(-time.Hour), start, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Clock = clock.NewFake(start)
+	// The window takes precedence over the offset
+	if got := New(time.Hour, w)(start); !got.Equal(w.Since) {
+		t.Errorf("New(1h, window)(start) = %s, want the window start %s", got, w.Since)
+	}
+}
+
+// metricCapture keeps the last resource metrics exported to it
+type metricCapture struct {
+	sdkmetric.Exporter
+	rm *metricdata.ResourceMetrics
+}
+
+func (e *metricCapture) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.rm = rm
+	return nil
+}
+
+// logCapture keeps the records exported to it
+type logCapture struct {
+	sdklog.Exporter
+	records []sdklog.Record
+}
+
+func (e *logCapture) Export(_ context.Context, records []sdklog.Record) error {
+	e.records = append(e.records, records...)
+	return nil
+}
+
+func TestExportersShiftTimestamps(t *testing.T) {
+	const offset = 24 * time.Hour
+	shifted := start.Add(-offset)
+
+	t.Run("spans", func(t *testing.T) {
+		exp := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(SpanExporter(exp, Offset(offset))))
+		defer func() { _ = tp.Shutdown(context.Background()) }()
+
+		_, s := tp.Tracer("test").Start(context.Background(), "span", trace.WithTimestamp(start))
+		s.AddEvent("event", trace.WithTimestamp(start.Add(time.Second)))
+		s.End(trace.WithTimestamp(start.Add(2 * time.Second)))
+
+		got := exp.GetSpans()[0]
+		if !got.StartTime.Equal(shifted) {
+			t.Errorf("start time = %s, want %s", got.StartTime, shifted)
+		}
+		if want := shifted.Add(2 * time.Second); !got.EndTime.Equal(want) {
+			t.Errorf("end time = %s, want %s", got.EndTime, want)
+		}
+		if want := shifted.Add(time.Second); !got.Events[0].Time.Equal(want) {
+			t.Errorf("event time = %s, want %s", got.Events[0].Time, want)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		capture := &metricCapture{}
+		rm := &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{
+			{Name: "sum", Data: metricdata.Sum[int64]{DataPoints: []metricdata.DataPoint[int64]{{
+				StartTime: start,
+				Time:      start.Add(time.Minute),
+				Exemplars: []metricdata.Exemplar[int64]{{Time: start.Add(time.Second)}},
+			}}}},
+			{Name: "histogram", Data: metricdata.Histogram[float64]{DataPoints: []metricdata.HistogramDataPoint[float64]{{
+				StartTime: start,
+				Time:      start.Add(time.Minute),
+			}}}},
+		}}}}
+		if err := MetricExporter(capture, Offset(offset)).Export(context.Background(), rm); err != nil {
+			t.Fatal(err)
+		}
+
+		metrics := capture.rm.ScopeMetrics[0].Metrics
+		sum := metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0]
+		hist := metrics[1].Data.(metricdata.Histogram[float64]).DataPoints[0]
+		tests := []struct {
+			name      string
+			got, want time.Time
+		}{
+			{"sum start", sum.StartTime, shifted},
+			{"sum time", sum.Time, shifted.Add(time.Minute)},
+			{"exemplar", sum.Exemplars[0].Time, shifted.Add(time.Second)},
+			{"histogram start", hist.StartTime, shifted},
+			{"histogram time", hist.Time, shifted.Add(time.Minute)},
+		}
+		for _, tt := range tests {
+			if !tt.got.Equal(tt.want) {
+				t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+			}
+		}
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		capture := &logCapture{}
+		var timed, untimed sdklog.Record
+		timed.SetTimestamp(start)
+		timed.SetObservedTimestamp(start.Add(time.Second))
+		if err := LogExporter(capture, Offset(offset)).Export(context.Background(), []sdklog.Record{timed, untimed}); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := capture.records[0].Timestamp(); !got.Equal(shifted) {
+			t.Errorf("timestamp = %s, want %s", got, shifted)
+		}
+		if got, want := capture.records[0].ObservedTimestamp(), shifted.Add(time.Second); !got.Equal(want) {
+			t.Errorf("observed timestamp = %s, want %s", got, want)
+		}
+		// An unset timestamp stays unset rather than moving before the epoch
+		if got := capture.records[1].Timestamp(); !got.IsZero() {
+			t.Errorf("unset timestamp = %s, want it left unset", got)
+		}
+	})
+
+	if exp := tracetest.NewInMemoryExporter(); SpanExporter(exp, nil) != sdktrace.SpanExporter(exp) {
+		t.Error("SpanExporter without a shift wraps the exporter")
+	}
+}
+
+func TestWindowSpansRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   time.Duration
+		duration time.Duration
+		items    int
+	}{
+		{"a day in a minute", 24 * time.Hour, time.Minute, 60},
+		{"a week in ten seconds", 7 * 24 * time.Hour, 10 * time.Second, 7},
+		{"an hour in an hour", time.Hour, time.Hour, 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			w, err := NewWindow(since, since.Add(tt.window), tt.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fake := clock.NewFake(start)
+			w.Clock = fake
+
+			exp := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(SpanExporter(exp, w.Shift())))
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			// Emit items evenly across the run, the last one as it ends
+			for i := 0; i < tt.items; i++ {
+				fake.Advance(start.Add(tt.duration * time.Duration(i) / time.Duration(tt.items-1)).Sub(fake.Now()))
+				now := fake.Now()
+				_, s := tp.Tracer("test").Start(context.Background(), "span", trace.WithTimestamp(now))
+				s.End(trace.WithTimestamp(now))
+			}
+
+			spans := exp.GetSpans()
+			if len(spans) != tt.items {
+				t.Fatalf("got %d spans, want %d", len(spans), tt.items)
+			}
+			if got := spans[0].StartTime; !got.Equal(w.Since) {
+				t.Errorf("first span at %s, want the window start %s", got, w.Since)
+			}
+			if got := spans[len(spans)-1].StartTime; !got.Equal(w.Until) {
+				t.Errorf("last span at %s, want the window end %s", got, w.Until)
+			}
+			for i := 1; i < len(spans); i++ {
+				if !spans[i].StartTime.After(spans[i-1].StartTime) {
+					t.Fatalf("span %d at %s isn't after span %d at %s", i, spans[i].StartTime, i-1, spans[i-1].StartTime)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package traces
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/krzko/otelgen/internal/payload"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// attributeProcessor adds synthetic attributes to every span as it starts
+type attributeProcessor struct {
+	attrs []attribute.KeyValue
+}
+
+// NewAttributeProcessor returns a span processor that appends count synthetic
+// attr.N attributes to every span, inflating the attribute volume of each scenario
+func NewAttributeProcessor(count int) sdktrace.SpanProcessor {
+	attrs := make([]attribute.KeyValue, 0, count)
+	for i := 0; i < count; i++ {
+		attrs = append(attrs, attribute.String(fmt.Sprintf("attr.%d", i), fmt.Sprintf("value-%d", i)))
+	}
+	return &attributeProcessor{attrs: attrs}
+}
+
+// NewPayloadProcessor returns a span processor that pads every span with a
+// synthetic attribute of size bytes, to exercise attribute and body size limits
+func NewPayloadProcessor(size int) sdktrace.SpanProcessor {
+	return &attributeProcessor{attrs: []attribute.KeyValue{attribute.String(payload.Key, payload.New(size))}}
+}
+
+func (p *attributeProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	s.SetAttributes(p.attrs...)
+}
+
+func (p *attributeProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *attributeProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *attributeProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,84 @@
+package traces
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/krzko/otelgen/internal/payload"
+	"github.com/krzko/otelgen/internal/traces/scenarios"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestAttributeProcessor(t *testing.T) {
+	for _, count := range []int{0, 1, 5, 50} {
+		t.Run(fmt.Sprint(count), func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithSpanProcessor(NewAttributeProcessor(count)),
+				sdktrace.WithSpanProcessor(recorder),
+			)
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			if err := scenarios.BasicScenario(context.Background(), tp.Tracer("test"), zap.NewNop(), "test", scenarios.Options{}); err != nil {
+				t.Fatalf("BasicScenario() error = %v", err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) == 0 {
+				t.Fatal("no spans were recorded")
+			}
+			for _, s := range spans {
+				synthetic := map[attribute.Key]string{}
+				for _, kv := range s.Attributes() {
+					synthetic[kv.Key] = kv.Value.AsString()
+				}
+				for i := 0; i < count; i++ {
+					key := attribute.Key(fmt.Sprintf("attr.%d", i))
+					if got, want := synthetic[key], fmt.Sprintf("value-%d", i); got != want {
+						t.Errorf("%s %s = %q, want %q", s.Name(), key, got, want)
+					}
+				}
+				if _, ok := synthetic[attribute.Key(fmt.Sprintf("attr.%d", count))]; ok {
+					t.Errorf("%s carries more than %d synthetic attributes", s.Name(), count)
+				}
+			}
+		})
+	}
+}
+
+func TestPayloadProcessor(t *testing.T) {
+	for _, size := range []int{1, 4096, payload.MaxSize} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithSpanProcessor(NewPayloadProcessor(size)),
+				sdktrace.WithSpanProcessor(recorder),
+			)
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			if err := scenarios.BasicScenario(context.Background(), tp.Tracer("test"), zap.NewNop(), "test", scenarios.Options{}); err != nil {
+				t.Fatalf("BasicScenario() error = %v", err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) == 0 {
+				t.Fatal("no spans were recorded")
+			}
+			for _, s := range spans {
+				got := -1
+				for _, kv := range s.Attributes() {
+					if kv.Key == payload.Key {
+						got = len(kv.Value.AsString())
+					}
+				}
+				if got != size {
+					t.Errorf("%s %s length = %d, want %d", s.Name(), payload.Key, got, size)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package traces
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// baggageProcessor copies the baggage of a span's context onto the span as it starts
+type baggageProcessor struct{}
+
+// NewBaggageProcessor returns a span processor that adds every baggage member in
+// the context a span starts with as a string attribute of the same key, as
+// instrumentation copying baggage onto spans would
+func NewBaggageProcessor() sdktrace.SpanProcessor {
+	return baggageProcessor{}
+}
+
+func (baggageProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	for _, m := range baggage.FromContext(ctx).Members() {
+		s.SetAttributes(attribute.String(m.Key(), m.Value()))
+	}
+}
+
+func (baggageProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (baggageProcessor) Shutdown(context.Context) error { return nil }
+
+func (baggageProcessor) ForceFlush(context.Context) error { return nil }
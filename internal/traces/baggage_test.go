@@ -0,0 +1,58 @@
+package traces
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestBaggageProcessor(t *testing.T) {
+	tests := []struct {
+		name    string
+		baggage string
+		want    map[attribute.Key]string
+	}{
+		{name: "none", want: map[attribute.Key]string{}},
+		{name: "one member", baggage: "tenant=acme", want: map[attribute.Key]string{"tenant": "acme"}},
+		{name: "many members", baggage: "tenant=acme,region=ap-southeast-2", want: map[attribute.Key]string{"tenant": "acme", "region": "ap-southeast-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bag, err := baggage.Parse(tt.baggage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithSpanProcessor(NewBaggageProcessor()),
+				sdktrace.WithSpanProcessor(recorder),
+			)
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			ctx := baggage.ContextWithBaggage(context.Background(), bag)
+			ctx, parent := tp.Tracer("test").Start(ctx, "parent")
+			_, child := tp.Tracer("test").Start(ctx, "child")
+			child.End()
+			parent.End()
+
+			for _, s := range recorder.Ended() {
+				got := map[attribute.Key]string{}
+				for _, kv := range s.Attributes() {
+					got[kv.Key] = kv.Value.AsString()
+				}
+				if len(got) != len(tt.want) {
+					t.Errorf("%s attributes = %v, want %v", s.Name(), got, tt.want)
+				}
+				for key, want := range tt.want {
+					if got[key] != want {
+						t.Errorf("%s %s = %q, want %q", s.Name(), key, got[key], want)
+					}
+				}
+			}
+		})
+	}
+}
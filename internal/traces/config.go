@@ -5,22 +5,143 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/krzko/otelgen/internal/idgen"
+	"github.com/krzko/otelgen/internal/rateprofile"
+	"github.com/krzko/otelgen/internal/timeshift"
+	"github.com/krzko/otelgen/internal/traces/scenarios"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Config struct {
-	WorkerCount      int
-	NumTraces        int
-	PropagateContext bool
-	Rate             int64
-	TotalDuration    time.Duration
-	ServiceName      string
-	Scenarios        []string
+	WorkerCount       int
+	NumTraces         int
+	PropagateContext  bool
+	Rate              float64
+	RateProfile       rateprofile.Profile
+	TotalDuration     time.Duration
+	ProgressInterval  time.Duration
+	ServiceName       string
+	ServiceVersion    string
+	ServiceInstanceID string
+	Scenarios         []string
+	SpanCount         int
+	MaxTraceDepth     int
+	ClockSkew         time.Duration
+	StatusMessage     string
+	SpanKind          trace.SpanKind
+	LinkMode          scenarios.LinkMode
+	// IDGenerator supplies trace and span IDs, crypto/rand is used when nil
+	IDGenerator sdktrace.IDGenerator
+	// SpanAttributeCount is the number of synthetic attributes added to every span
+	SpanAttributeCount int
+	// PayloadSize is the size in bytes of a synthetic attribute added to every span, 0 disables it
+	PayloadSize int
+	// ScenarioRetries is how many times a failing scenario is run again before giving up
+	ScenarioRetries int
+	// ScenarioBackoff is the wait before a scenario's first retry, doubling after each
+	ScenarioBackoff time.Duration
+	// Baggage is set on the context every scenario runs in
+	Baggage baggage.Baggage
+	// BaggageToAttributes copies the baggage members of a span's context onto the span
+	BaggageToAttributes bool
+	// NamePrefix and NameSuffix wrap the name of every span
+	NamePrefix string
+	NameSuffix string
+	// SpanRate caps the spans started per second across all workers, 0 leaves them unthrottled
+	SpanRate float64
+
+	DetectResources bool
+	ResourceFromEnv bool
+	// ResourceFromContainer merges the detected container.id into the resource
+	ResourceFromContainer bool
+
+	// MaxExportErrors aborts the run after this many consecutive export failures, 0 disables it
+	MaxExportErrors int
+	// ExportStats logs a summary of export outcomes on shutdown
+	ExportStats bool
+	// FlushInterval is how often buffered telemetry is force flushed, 0 disables it
+	FlushInterval time.Duration
+	// ShutdownTimeout bounds how long exporters and providers may take to drain on shutdown
+	ShutdownTimeout time.Duration
+	// Discard counts telemetry in place of exporting it
+	Discard bool
+	// Socket, when set, streams telemetry as ndjson to this tcp:// or udp:// address in place of exporting it
+	Socket string
+	// Kafka, when set, produces telemetry as ndjson to this kafka://host:port/topic in place of exporting it
+	Kafka string
+	// TeeStdout additionally prints every exported item to stdout as ndjson
+	TeeStdout bool
+	// Pretty indents the JSON TeeStdout prints, which is compact ndjson otherwise
+	Pretty bool
+	// OutputBuffer is the size in bytes of the buffer TeeStdout batches its writes in, 0 disables it
+	OutputBuffer int
+	// StartTimeOffset shifts the timestamps of exported telemetry this far into the past
+	StartTimeOffset time.Duration
+	// Window, when set, replays the run's telemetry evenly across a historical time range
+	Window *timeshift.Window
+	// Chaos gives some exported telemetry odd but valid edge cases, chosen from ChaosSeed
+	Chaos     bool
+	ChaosSeed int64
+	// SyncExport exports every record as it ends instead of batching them
+	SyncExport bool
 
 	// OTLP config
 	Endpoint string
 	Insecure bool
 	UseHTTP  bool
 	Headers  HeaderValue
+	// URLPath overrides the default signal path used by the HTTP exporter
+	URLPath string
+}
+
+// DefaultScenarioBackoff is the wait before a failing scenario's first retry
+const DefaultScenarioBackoff = 100 * time.Millisecond
+
+// SpanProcessor returns the processor exporting spans to exp, a simple one exporting
+// each span as it ends when SyncExport is set and a batching one otherwise
+func (c *Config) SpanProcessor(exp sdktrace.SpanExporter) sdktrace.SpanProcessor {
+	if c.SyncExport {
+		return sdktrace.NewSimpleSpanProcessor(exp)
+	}
+	return sdktrace.NewBatchSpanProcessor(exp, sdktrace.WithBatchTimeout(time.Second))
+}
+
+// TracerProviderOptions returns the tracer provider options that apply this config:
+// its ID generator and, when set, the synthetic span attributes, baggage attributes
+// and span name affixes.
+func (c *Config) TracerProviderOptions() []sdktrace.TracerProviderOption {
+	ids := c.IDGenerator
+	if ids == nil {
+		ids = idgen.NewCrypto()
+	}
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithIDGenerator(ids)}
+
+	if n := c.SpanAttributeCount; n > 0 {
+		// Raise the attribute limit so the synthetic attributes aren't dropped
+		limits := sdktrace.NewSpanLimits()
+		limits.AttributeCountLimit += n
+		opts = append(opts,
+			sdktrace.WithRawSpanLimits(limits),
+			sdktrace.WithSpanProcessor(NewAttributeProcessor(n)),
+		)
+	}
+
+	if c.PayloadSize > 0 {
+		opts = append(opts, sdktrace.WithSpanProcessor(NewPayloadProcessor(c.PayloadSize)))
+	}
+
+	if c.BaggageToAttributes {
+		opts = append(opts, sdktrace.WithSpanProcessor(NewBaggageProcessor()))
+	}
+
+	if c.NamePrefix != "" || c.NameSuffix != "" {
+		opts = append(opts, sdktrace.WithSpanProcessor(NewNameProcessor(c.NamePrefix, c.NameSuffix)))
+	}
+
+	return opts
 }
 
 type HeaderValue map[string]string
@@ -0,0 +1,89 @@
+package traces
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	fixedTraceID = trace.TraceID{0x0a, 0x0b, 0x0c}
+	fixedSpanID  = trace.SpanID{0x01, 0x02, 0x03}
+)
+
+// fixedIDs hands out the same trace and span ID every time
+type fixedIDs struct{}
+
+func (fixedIDs) NewIDs(context.Context) (trace.TraceID, trace.SpanID) {
+	return fixedTraceID, fixedSpanID
+}
+
+func (fixedIDs) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	return fixedSpanID
+}
+
+func TestConfigIDGenerator(t *testing.T) {
+	tests := []struct {
+		name      string
+		ids       sdktrace.IDGenerator
+		wantFixed bool
+	}{
+		{"configured", fixedIDs{}, true},
+		{"default", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			c := &Config{IDGenerator: tt.ids}
+			tp := sdktrace.NewTracerProvider(append(c.TracerProviderOptions(), sdktrace.WithSpanProcessor(recorder))...)
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			_, span := tp.Tracer("test").Start(context.Background(), "span")
+			span.End()
+
+			sc := recorder.Ended()[0].SpanContext()
+			if !sc.IsValid() {
+				t.Fatalf("span context %v isn't valid", sc)
+			}
+			if got := sc.TraceID() == fixedTraceID && sc.SpanID() == fixedSpanID; got != tt.wantFixed {
+				t.Errorf("span has IDs %s/%s, want the fixed IDs = %t", sc.TraceID(), sc.SpanID(), tt.wantFixed)
+			}
+		})
+	}
+}
+
+func TestConfigSpanProcessor(t *testing.T) {
+	tests := []struct {
+		name     string
+		sync     bool
+		wantSync bool
+	}{
+		{"sync export", true, true},
+		{"batched", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := tracetest.NewInMemoryExporter()
+			c := &Config{SyncExport: tt.sync}
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(c.SpanProcessor(exp)))
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			_, span := tp.Tracer("test").Start(context.Background(), "span")
+			span.End()
+
+			// A simple processor has exported the span by the time End returns
+			if got := len(exp.GetSpans()) == 1; got != tt.wantSync {
+				t.Errorf("exported as the span ended = %t, want %t", got, tt.wantSync)
+			}
+			if err := tp.ForceFlush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(exp.GetSpans()); got != 1 {
+				t.Errorf("got %d spans after flushing, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package traces
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// nameProcessor wraps the name of every span as it starts
+type nameProcessor struct {
+	prefix, suffix string
+}
+
+// NewNameProcessor returns a span processor that renames every span to
+// prefix + name + suffix, namespacing a run's spans from other generators
+func NewNameProcessor(prefix, suffix string) sdktrace.SpanProcessor {
+	return &nameProcessor{prefix: prefix, suffix: suffix}
+}
+
+func (p *nameProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	s.SetName(p.prefix + s.Name() + p.suffix)
+}
+
+func (p *nameProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *nameProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *nameProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,40 @@
+package traces
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNameProcessor(t *testing.T) {
+	tests := []struct {
+		name           string
+		prefix, suffix string
+		want           string
+	}{
+		{name: "prefix", prefix: "load-", want: "load-ping"},
+		{name: "suffix", suffix: "-run2", want: "ping-run2"},
+		{name: "both", prefix: "load-", suffix: "-run2", want: "load-ping-run2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			c := &Config{NamePrefix: tt.prefix, NameSuffix: tt.suffix}
+			tp := sdktrace.NewTracerProvider(append(c.TracerProviderOptions(), sdktrace.WithSpanProcessor(recorder))...)
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+
+			_, span := tp.Tracer("test").Start(context.Background(), "ping")
+			span.End()
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			if got := spans[0].Name(); got != tt.want {
+				t.Errorf("span name = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
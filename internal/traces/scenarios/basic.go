@@ -18,12 +18,36 @@ const (
 	fakeVer string = "1.2.3"
 )
 
-func BasicScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string) error {
+// BasicDescription documents the spans emitted by BasicScenario
+var BasicDescription = Description{
+	Summary: "A ping-pong exchange: a client span with a single server child.",
+	Spans: []SpanDescription{
+		{
+			Name:       "ping",
+			Kind:       "client, or the kind set by --span-kind",
+			Attributes: []string{"span.kind", "service.namespace", "network.peer.address", "peer.service", "service.instance.id", "service.version", "telemetry.sdk.language"},
+		},
+		{
+			Name:       "pong",
+			Kind:       "server",
+			Parent:     "ping",
+			Attributes: []string{"span.kind", "service.namespace", "network.peer.address", "peer.service", "service.instance.id", "service.version", "telemetry.sdk.language"},
+		},
+	},
+}
+
+func BasicScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
 	hn, _ := os.Hostname()
 
+	kind := opts.spanKind(trace.SpanKindClient)
+
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	ctx, sp := tracer.Start(ctx, "ping",
+		trace.WithSpanKind(kind),
 		trace.WithAttributes(
-			attribute.String("span.kind", "client"),
+			attribute.String("span.kind", kind.String()),
 			semconv.ServiceNamespace(fakeNS),
 			semconv.NetworkPeerAddress(fakeIP),
 			semconv.PeerServiceKey.String("ping-pong-server"),
@@ -38,7 +62,11 @@ func BasicScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger,
 	pingDuration := time.Duration(rand.Intn(100)) * time.Millisecond
 	time.Sleep(pingDuration)
 
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	_, child := tracer.Start(ctx, "pong",
+		trace.WithSpanKind(trace.SpanKindServer),
 		trace.WithAttributes(
 			attribute.String("span.kind", "server"),
 			semconv.ServiceNamespace(fakeNS),
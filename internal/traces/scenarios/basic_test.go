@@ -0,0 +1,55 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestBasicScenarioRootSpanKind(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		want trace.SpanKind
+	}{
+		{"default", "", trace.SpanKindClient},
+		{"server", "server", trace.SpanKindServer},
+		{"producer", "producer", trace.SpanKindProducer},
+		{"consumer", "consumer", trace.SpanKindConsumer},
+		{"internal", "internal", trace.SpanKindInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, err := ParseSpanKind(tt.kind)
+			if err != nil {
+				t.Fatalf("ParseSpanKind(%q) error = %v", tt.kind, err)
+			}
+			tracer, recorder := newRecordingTracer(t)
+			if err := BasicScenario(context.Background(), tracer, zap.NewNop(), "test", Options{SpanKind: kind}); err != nil {
+				t.Fatalf("BasicScenario() error = %v", err)
+			}
+
+			for _, s := range recorder.Ended() {
+				switch s.Name() {
+				case "ping":
+					if s.SpanKind() != tt.want {
+						t.Errorf("ping kind = %s, want %s", s.SpanKind(), tt.want)
+					}
+				case "pong":
+					if s.SpanKind() != trace.SpanKindServer {
+						t.Errorf("pong kind = %s, want %s", s.SpanKind(), trace.SpanKindServer)
+					}
+				}
+			}
+			if got := len(recorder.Ended()); got != 2 {
+				t.Errorf("got %d spans, want 2", got)
+			}
+		})
+	}
+
+	if _, err := ParseSpanKind("sideways"); err == nil {
+		t.Error("ParseSpanKind(sideways) succeeded, want an error")
+	}
+}
@@ -0,0 +1,85 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// clockSkewChildren is the number of child spans emitted by the clock skew scenario
+const clockSkewChildren = 4
+
+// ClockSkewDescription documents the spans emitted by ClockSkewScenario
+var ClockSkewDescription = Description{
+	Summary: "A parent whose children start skewed into the past and future by --clock-skew.",
+	Spans: []SpanDescription{
+		{
+			Name:       "skewed_request",
+			Kind:       "internal",
+			Attributes: []string{"service.name", "clock.skew_ms"},
+		},
+		{
+			Name:       "skewed_call_<n>",
+			Kind:       "internal",
+			Parent:     "skewed_request",
+			Count:      "4",
+			Attributes: []string{"clock.offset_ms"},
+			Notes:      "children alternate between starting before and after their parent",
+		},
+	},
+}
+
+// ClockSkewScenario emits a parent span whose children start skewed into the
+// past and the future relative to it, as if recorded on hosts with drifting clocks
+func ClockSkewScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
+	skew := opts.clockSkew()
+	parentStart := time.Now()
+
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
+	ctx, parent := tracer.Start(ctx, "skewed_request",
+		trace.WithTimestamp(parentStart),
+		trace.WithAttributes(
+			semconv.ServiceNameKey.String(fmt.Sprintf("%s-skew", serviceName)),
+			attribute.Int64("clock.skew_ms", skew.Milliseconds()),
+		),
+	)
+	defer parent.End()
+
+	for i := 0; i < clockSkewChildren; i++ {
+		// Alternate the children between starting before and after the parent
+		offset := skew
+		if i%2 == 0 {
+			offset = -skew
+		}
+		start := parentStart.Add(offset)
+		duration := time.Duration(rand.Intn(50)+1) * time.Millisecond
+
+		if err := WaitSpan(ctx); err != nil {
+			return err
+		}
+		_, child := tracer.Start(ctx, fmt.Sprintf("skewed_call_%d", i),
+			trace.WithTimestamp(start),
+			trace.WithAttributes(
+				attribute.Int64("clock.offset_ms", offset.Milliseconds()),
+			),
+		)
+		child.SetStatus(codes.Ok, "")
+		child.End(trace.WithTimestamp(start.Add(duration)))
+	}
+
+	logger.Debug("clock skew trace generated",
+		zap.String("traceId", parent.SpanContext().TraceID().String()),
+		zap.Duration("skew", skew),
+	)
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+func TestClockSkewScenarioSkewsChildren(t *testing.T) {
+	tests := []struct {
+		name string
+		skew time.Duration
+		want time.Duration
+	}{
+		{"default", 0, DefaultClockSkew},
+		{"configured", 2 * time.Second, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, recorder := newRecordingTracer(t)
+			if err := ClockSkewScenario(context.Background(), tracer, zap.NewNop(), "test", Options{ClockSkew: tt.skew}); err != nil {
+				t.Fatalf("ClockSkewScenario() error = %v", err)
+			}
+
+			var parent sdktrace.ReadOnlySpan
+			var children []sdktrace.ReadOnlySpan
+			for _, s := range recorder.Ended() {
+				if s.Name() == "skewed_request" {
+					parent = s
+				} else {
+					children = append(children, s)
+				}
+			}
+			if parent == nil {
+				t.Fatal("no skewed_request span was recorded")
+			}
+			if len(children) != clockSkewChildren {
+				t.Fatalf("got %d child spans, want %d", len(children), clockSkewChildren)
+			}
+
+			var before, after int
+			for _, child := range children {
+				if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+					t.Errorf("%s isn't a child of skewed_request", child.Name())
+				}
+				switch offset := child.StartTime().Sub(parent.StartTime()); offset {
+				case -tt.want:
+					before++
+				case tt.want:
+					after++
+				default:
+					t.Errorf("%s starts %s from its parent, want ±%s", child.Name(), offset, tt.want)
+				}
+			}
+			if before != clockSkewChildren/2 || after != clockSkewChildren/2 {
+				t.Errorf("got %d children before and %d after their parent, want %d each", before, after, clockSkewChildren/2)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// DeepDescription documents the spans emitted by DeepScenario
+var DeepDescription = Description{
+	Summary: "A single chain of nested calls, each the child of the one before.",
+	Spans: []SpanDescription{
+		{
+			Name:       "nested_call_<n>",
+			Kind:       "internal",
+			Parent:     "nested_call_<n-1>, the first is the root",
+			Count:      "--max-trace-depth",
+			Attributes: []string{"service.name", "nesting.level", "nesting.max_depth"},
+		},
+	},
+}
+
+func DeepScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
+	depth := opts.maxTraceDepth()
+
+	spans := make([]trace.Span, 0, depth)
+	defer func() {
+		// End the spans from the innermost outwards so every parent outlives its children
+		for i := len(spans) - 1; i >= 0; i-- {
+			spans[i].End()
+		}
+	}()
+
+	for level := 0; level < depth; level++ {
+		if err := WaitSpan(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		var span trace.Span
+		ctx, span = tracer.Start(ctx, fmt.Sprintf("nested_call_%d", level),
+			trace.WithAttributes(
+				semconv.ServiceNameKey.String(fmt.Sprintf("%s-deep", serviceName)),
+				attribute.Int("nesting.level", level),
+				attribute.Int("nesting.max_depth", depth),
+			),
+		)
+		spans = append(spans, span)
+
+		// Simulate some work before descending further
+		time.Sleep(time.Duration(rand.Intn(10)) * time.Millisecond)
+
+		span.SetStatus(codes.Ok, "")
+	}
+
+	logger.Debug("deep trace generated",
+		zap.String("traceId", spans[0].SpanContext().TraceID().String()),
+		zap.Int("depth", depth),
+	)
+
+	return nil
+}
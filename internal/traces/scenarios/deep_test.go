@@ -0,0 +1,46 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestDeepScenarioChainLength(t *testing.T) {
+	for _, depth := range []int{1, 3, DefaultMaxTraceDepth} {
+		t.Run(fmt.Sprint(depth), func(t *testing.T) {
+			tracer, recorder := newRecordingTracer(t)
+			if err := DeepScenario(context.Background(), tracer, zap.NewNop(), "test", Options{MaxTraceDepth: depth}); err != nil {
+				t.Fatalf("DeepScenario() error = %v", err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != depth {
+				t.Fatalf("got %d spans, want %d", len(spans), depth)
+			}
+			parents := make(map[trace.SpanID]trace.SpanID, len(spans))
+			var leaf trace.SpanID
+			for _, s := range spans {
+				parents[s.SpanContext().SpanID()] = s.Parent().SpanID()
+				if s.Name() == fmt.Sprintf("nested_call_%d", depth-1) {
+					leaf = s.SpanContext().SpanID()
+				}
+			}
+
+			// Walk from the innermost span up to the root
+			length := 0
+			for id := leaf; id.IsValid(); id = parents[id] {
+				length++
+				if length > depth {
+					t.Fatal("the parent chain is longer than the number of spans")
+				}
+			}
+			if length != depth {
+				t.Errorf("parent chain length = %d, want %d", length, depth)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package scenarios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Description documents what a scenario emits, so backend queries can be written
+// against it without reading the implementation.
+type Description struct {
+	// Summary is a one line account of what the scenario simulates.
+	Summary string
+	// Spans lists the spans of one trace, parents before their children.
+	Spans []SpanDescription
+}
+
+// SpanDescription documents one span, or a group of sibling spans, of a scenario.
+type SpanDescription struct {
+	// Name is the span name, a pattern such as nested_call_<n> for numbered spans.
+	Name string
+	// Kind is the span kind, or how it's chosen when it varies.
+	Kind string
+	// Parent is the name of the parent span, empty for the scenario's root span.
+	Parent string
+	// Count says how many of the span are emitted when it's more than one.
+	Count string
+	// Attributes are the keys of the span's notable attributes.
+	Attributes []string
+	// Events are the names of the span's events.
+	Events []string
+	// Notes covers anything else that sets the span apart.
+	Notes string
+}
+
+// String renders the description as indented text for the terminal.
+func (d Description) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", d.Summary)
+	for _, s := range d.Spans {
+		fmt.Fprintf(&b, "\nspan: %s\n", s.Name)
+		fmt.Fprintf(&b, "  kind: %s\n", s.Kind)
+		if s.Parent == "" {
+			fmt.Fprintf(&b, "  parent: (root)\n")
+		} else {
+			fmt.Fprintf(&b, "  parent: %s\n", s.Parent)
+		}
+		if s.Count != "" {
+			fmt.Fprintf(&b, "  count: %s\n", s.Count)
+		}
+		if len(s.Attributes) > 0 {
+			fmt.Fprintf(&b, "  attributes: %s\n", strings.Join(s.Attributes, ", "))
+		}
+		if len(s.Events) > 0 {
+			fmt.Fprintf(&b, "  events: %s\n", strings.Join(s.Events, ", "))
+		}
+		if s.Notes != "" {
+			fmt.Fprintf(&b, "  notes: %s\n", s.Notes)
+		}
+	}
+	return b.String()
+}
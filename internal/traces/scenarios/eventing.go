@@ -11,7 +11,32 @@ import (
 	"go.uber.org/zap"
 )
 
-func EventingScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string) error {
+// EventingDescription documents the spans emitted by EventingScenario
+var EventingDescription = Description{
+	Summary: "A Kafka producer and a consumer that processes the message asynchronously.",
+	Spans: []SpanDescription{
+		{
+			Name:       "event_producer",
+			Kind:       "producer",
+			Attributes: []string{"service.name", "messaging.system", "messaging.operation.type", "messaging.destination.name", "messaging.message.id", "messaging.message.conversation_id", "messaging.kafka.message.key", "messaging.message.body.size"},
+		},
+		{
+			Name:       "event_consumer",
+			Kind:       "consumer",
+			Parent:     "event_producer with --link-mode parent, otherwise a new trace",
+			Attributes: []string{"service.name", "messaging.system", "messaging.operation.type", "messaging.destination.name", "messaging.message.id", "messaging.message.conversation_id", "messaging.eventhubs.consumer.group", "messaging.kafka.message.offset"},
+			Notes:      "links to event_producer with --link-mode link",
+		},
+		{
+			Name:       "process_event",
+			Kind:       "internal",
+			Parent:     "event_consumer",
+			Attributes: []string{"faas.trigger", "faas.invoked_name", "faas.document.operation"},
+		},
+	},
+}
+
+func EventingScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
 	// Use different service names for producer and consumer
 	producerServiceName := fmt.Sprintf("%s-event-producer", serviceName)
 	consumerServiceName := fmt.Sprintf("%s-event-consumer", serviceName)
@@ -20,7 +45,11 @@ func EventingScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logg
 	conversationID := fmt.Sprintf("conv-%d", rand.Int63())
 
 	// Producer
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	ctx, producerSpan := tracer.Start(ctx, "event_producer",
+		trace.WithSpanKind(trace.SpanKindProducer),
 		trace.WithAttributes(
 			semconv.ServiceNameKey.String(producerServiceName),
 			semconv.MessagingSystemKey.String("kafka"),
@@ -40,8 +69,20 @@ func EventingScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logg
 	// Simulate some time passing
 	time.Sleep(time.Duration(rand.Intn(200)) * time.Millisecond)
 
-	// Consumer
-	consumerCtx, consumerSpan := tracer.Start(context.Background(), "event_consumer",
+	// Consumer, either continuing the producer's trace or starting a new one linked to it
+	consumerParent := context.Background()
+	var consumerLinks []trace.Link
+	if opts.LinkMode == LinkModeParent {
+		consumerParent = ctx
+	} else {
+		consumerLinks = append(consumerLinks, trace.LinkFromContext(ctx))
+	}
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
+	consumerCtx, consumerSpan := tracer.Start(consumerParent, "event_consumer",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(consumerLinks...),
 		trace.WithAttributes(
 			semconv.ServiceNameKey.String(consumerServiceName),
 			semconv.MessagingSystemKey.String("kafka"),
@@ -54,15 +95,16 @@ func EventingScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logg
 		),
 	)
 
-	// Add link to the producer span
-	consumerSpan.AddLink(trace.LinkFromContext(ctx))
-
 	// Simulate consuming a message
 	time.Sleep(time.Duration(rand.Intn(100)) * time.Millisecond)
 	consumerSpan.End()
 
-	// Process event
+	// Process event, waiting on ctx as the consumer's context may not carry the limiter
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	_, processSpan := tracer.Start(consumerCtx, "process_event",
+		trace.WithSpanKind(trace.SpanKindInternal),
 		trace.WithAttributes(
 			semconv.FaaSTriggerPubsub,
 			semconv.FaaSInvokedName(fmt.Sprintf("execution-%d", rand.Int63())),
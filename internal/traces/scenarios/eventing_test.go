@@ -0,0 +1,62 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+func TestEventingScenarioLinkMode(t *testing.T) {
+	tests := []struct {
+		mode LinkMode
+		// wantOneTrace is whether the consumer continues the producer's trace
+		wantOneTrace bool
+	}{
+		{LinkModeParent, true},
+		{LinkModeLink, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			tracer, recorder := newRecordingTracer(t)
+			if err := EventingScenario(context.Background(), tracer, zap.NewNop(), "test", Options{LinkMode: tt.mode}); err != nil {
+				t.Fatalf("EventingScenario() error = %v", err)
+			}
+
+			spans := map[string]sdktrace.ReadOnlySpan{}
+			for _, s := range recorder.Ended() {
+				spans[s.Name()] = s
+			}
+			producer, consumer, process := spans["event_producer"], spans["event_consumer"], spans["process_event"]
+			if producer == nil || consumer == nil || process == nil {
+				t.Fatalf("got spans %v, want a producer, consumer and process_event", spans)
+			}
+
+			producerTrace := producer.SpanContext().TraceID()
+			if got := consumer.SpanContext().TraceID() == producerTrace; got != tt.wantOneTrace {
+				t.Errorf("consumer shares the producer's trace = %t, want %t", got, tt.wantOneTrace)
+			}
+			if process.SpanContext().TraceID() != consumer.SpanContext().TraceID() {
+				t.Error("process_event isn't in the consumer's trace")
+			}
+
+			if tt.wantOneTrace {
+				if consumer.Parent().SpanID() != producer.SpanContext().SpanID() {
+					t.Error("the consumer isn't a child of the producer")
+				}
+				if len(consumer.Links()) != 0 {
+					t.Errorf("the consumer has %d links, want none", len(consumer.Links()))
+				}
+				return
+			}
+			if consumer.Parent().IsValid() {
+				t.Errorf("the consumer has parent %s, want a new root", consumer.Parent().SpanID())
+			}
+			if links := consumer.Links(); len(links) != 1 || !links[0].SpanContext.Equal(producer.SpanContext()) {
+				t.Errorf("consumer links = %v, want one to the producer", links)
+			}
+		})
+	}
+}
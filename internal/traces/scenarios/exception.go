@@ -0,0 +1,132 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// errInsufficientFunds is the root cause raised by the exception scenario
+var errInsufficientFunds = errors.New("insufficient funds")
+
+// ExceptionDescription documents the spans emitted by ExceptionScenario
+var ExceptionDescription = Description{
+	Summary: "A checkout request failing in a nested ledger call, recording the error at every level.",
+	Spans: []SpanDescription{
+		{
+			Name:       "POST /checkout",
+			Kind:       "server",
+			Attributes: []string{"service.name", "http.request.method", "http.route", "http.response.status_code"},
+			Events:     []string{"exception"},
+			Notes:      "status error with the --status-message description",
+		},
+		{
+			Name:       "charge_card",
+			Kind:       "internal",
+			Parent:     "POST /checkout",
+			Attributes: []string{"rpc.system", "rpc.service", "rpc.method"},
+			Events:     []string{"exception"},
+		},
+		{
+			Name:       "debit_ledger",
+			Kind:       "internal",
+			Parent:     "charge_card",
+			Attributes: []string{"db.system", "db.operation.name", "ledger.balance_cents"},
+			Events:     []string{"exception"},
+			Notes:      "exception events carry exception.type, exception.message and exception.stacktrace",
+		},
+	},
+}
+
+// ExceptionScenario emits a request whose nested calls fail, recording the error on
+// each span as an exception event carrying an exception.stacktrace attribute
+func ExceptionScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
+	service := fmt.Sprintf("%s-payments", serviceName)
+
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
+	ctx, root := tracer.Start(ctx, "POST /checkout",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.ServiceNameKey.String(service),
+			semconv.HTTPRequestMethodKey.String("POST"),
+			semconv.HTTPRouteKey.String("/checkout"),
+			semconv.HTTPResponseStatusCodeKey.Int(500),
+		),
+	)
+	defer root.End()
+
+	err := chargeCard(ctx, tracer)
+	if !errors.Is(err, errInsufficientFunds) {
+		// The run ended while a span waited to start, not the failure being simulated
+		root.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	root.RecordError(err, trace.WithStackTrace(true))
+	root.SetStatus(codes.Error, opts.statusMessage(service))
+
+	logger.Debug("exception trace generated",
+		zap.String("traceId", root.SpanContext().TraceID().String()),
+		zap.Error(err),
+	)
+
+	return nil
+}
+
+// chargeCard wraps the failure of the ledger call, recording it on its own span
+func chargeCard(ctx context.Context, tracer trace.Tracer) error {
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
+	ctx, span := tracer.Start(ctx, "charge_card",
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("grpc"),
+			semconv.RPCServiceKey.String("PaymentService"),
+			semconv.RPCMethodKey.String("Charge"),
+		),
+	)
+	defer span.End()
+
+	if err := debitLedger(ctx, tracer); err != nil {
+		if !errors.Is(err, errInsufficientFunds) {
+			return err
+		}
+		err = fmt.Errorf("charge card: %w", err)
+		span.RecordError(err, trace.WithStackTrace(true))
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// debitLedger always fails with the root cause of the exception scenario
+func debitLedger(ctx context.Context, tracer trace.Tracer) error {
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
+	_, span := tracer.Start(ctx, "debit_ledger",
+		trace.WithAttributes(
+			semconv.DBSystemKey.String("postgresql"),
+			semconv.DBOperationNameKey.String("UPDATE"),
+			attribute.Int64("ledger.balance_cents", rand.Int63n(1000)),
+		),
+	)
+	defer span.End()
+
+	time.Sleep(time.Duration(rand.Intn(5)+1) * time.Millisecond)
+
+	err := fmt.Errorf("debit ledger: %w", errInsufficientFunds)
+	span.RecordError(err, trace.WithStackTrace(true))
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
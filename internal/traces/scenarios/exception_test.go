@@ -0,0 +1,82 @@
+package scenarios
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/zap"
+)
+
+func TestExceptionScenarioRecordsStackTraces(t *testing.T) {
+	tracer, recorder := newRecordingTracer(t)
+	if err := ExceptionScenario(context.Background(), tracer, zap.NewNop(), "test", Options{}); err != nil {
+		t.Fatalf("ExceptionScenario() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	for _, s := range spans {
+		var exceptions int
+		for _, e := range s.Events() {
+			if e.Name != semconv.ExceptionEventName {
+				continue
+			}
+			exceptions++
+			attrs := attribute.NewSet(e.Attributes...)
+			for _, key := range []attribute.Key{semconv.ExceptionTypeKey, semconv.ExceptionMessageKey, semconv.ExceptionStacktraceKey} {
+				if v, ok := attrs.Value(key); !ok || v.AsString() == "" {
+					t.Errorf("%s exception event has no %s", s.Name(), key)
+				}
+			}
+			if msg, _ := attrs.Value(semconv.ExceptionMessageKey); !strings.Contains(msg.AsString(), errInsufficientFunds.Error()) {
+				t.Errorf("%s exception message = %q, want it to wrap %q", s.Name(), msg.AsString(), errInsufficientFunds)
+			}
+			if stack, _ := attrs.Value(semconv.ExceptionStacktraceKey); !strings.Contains(stack.AsString(), "goroutine") {
+				t.Errorf("%s exception stacktrace = %q, want a Go stack trace", s.Name(), stack.AsString())
+			}
+		}
+		if exceptions != 1 {
+			t.Errorf("%s has %d exception events, want 1", s.Name(), exceptions)
+		}
+		if s.Status().Code != codes.Error {
+			t.Errorf("%s status = %v, want %v", s.Name(), s.Status().Code, codes.Error)
+		}
+	}
+}
+
+func TestExceptionScenarioStatusMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"default", "", DefaultStatusMessage},
+		{"configured", "payment declined", "payment declined"},
+		{"service template", "{service} is down", "test-payments is down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, recorder := newRecordingTracer(t)
+			if err := ExceptionScenario(context.Background(), tracer, zap.NewNop(), "test", Options{StatusMessage: tt.message}); err != nil {
+				t.Fatalf("ExceptionScenario() error = %v", err)
+			}
+
+			for _, s := range recorder.Ended() {
+				if s.Name() != "POST /checkout" {
+					continue
+				}
+				if s.Status().Code != codes.Error || s.Status().Description != tt.want {
+					t.Errorf("status = %v %q, want %v %q", s.Status().Code, s.Status().Description, codes.Error, tt.want)
+				}
+				return
+			}
+			t.Fatal("no POST /checkout span was recorded")
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// FanOutDescription documents the spans emitted by FanOutScenario
+var FanOutDescription = Description{
+	Summary: "A request fanning out concurrent sub-requests, each started in its own goroutine.",
+	Spans: []SpanDescription{
+		{
+			Name:       "GET /dashboard",
+			Kind:       "server",
+			Attributes: []string{"service.name", "http.request.method", "http.route", "http.response.status_code"},
+		},
+		{
+			Name:       "fetch_widget",
+			Kind:       "client",
+			Parent:     "GET /dashboard",
+			Count:      "--span-count",
+			Attributes: []string{"http.request.method", "url.full", "widget.index", "http.response.status_code"},
+			Notes:      "siblings overlap in time",
+		},
+	},
+}
+
+// FanOutScenario emits a request that fans out concurrent sub-requests, each started
+// in its own goroutine as a child of the shared request span
+func FanOutScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
+	service := fmt.Sprintf("%s-aggregator", serviceName)
+
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
+	ctx, root := tracer.Start(ctx, "GET /dashboard",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.ServiceNameKey.String(service),
+			semconv.HTTPRequestMethodKey.String("GET"),
+			semconv.HTTPRouteKey.String("/dashboard"),
+		),
+	)
+	defer root.End()
+
+	width := opts.spanCount()
+	var wg sync.WaitGroup
+	var err error
+	for i := 0; i < width; i++ {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		// Each sub-request is allowed to start before its goroutine is spawned
+		if err = WaitSpan(ctx); err != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fetchWidget(ctx, tracer, i)
+		}(i)
+	}
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		root.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	root.SetAttributes(semconv.HTTPResponseStatusCodeKey.Int(200))
+	root.SetStatus(codes.Ok, "")
+
+	logger.Debug("fan-out trace generated",
+		zap.String("traceId", root.SpanContext().TraceID().String()),
+		zap.Int("width", width),
+	)
+
+	return nil
+}
+
+// fetchWidget is one concurrent sub-request of the fan-out scenario
+func fetchWidget(ctx context.Context, tracer trace.Tracer, i int) {
+	_, span := tracer.Start(ctx, "fetch_widget",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String("GET"),
+			semconv.URLFull(fmt.Sprintf("http://widgets/api/v1/widgets/%d", i)),
+			attribute.Int("widget.index", i),
+		),
+	)
+	defer span.End()
+
+	select {
+	case <-ctx.Done():
+		span.SetStatus(codes.Error, ctx.Err().Error())
+		return
+	case <-time.After(time.Duration(rand.Intn(50)+1) * time.Millisecond):
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCodeKey.Int(200))
+	span.SetStatus(codes.Ok, "")
+}
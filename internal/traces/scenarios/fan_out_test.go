@@ -0,0 +1,50 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Run with -race, the sub-requests start their spans concurrently
+func TestFanOutScenarioParentsEverySpan(t *testing.T) {
+	for _, width := range []int{1, 8, DefaultSpanCount} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			tracer, recorder := newRecordingTracer(t)
+			if err := FanOutScenario(context.Background(), tracer, zap.NewNop(), "test", Options{SpanCount: width}); err != nil {
+				t.Fatalf("FanOutScenario() error = %v", err)
+			}
+
+			var root trace.SpanContext
+			for _, s := range recorder.Ended() {
+				if s.Name() == "GET /dashboard" {
+					root = s.SpanContext()
+				}
+			}
+			if !root.IsValid() {
+				t.Fatal("no GET /dashboard span was recorded")
+			}
+
+			indices := make(map[int64]bool, width)
+			for _, s := range recorder.Ended() {
+				if s.Name() != "fetch_widget" {
+					continue
+				}
+				if s.Parent().SpanID() != root.SpanID() || s.Parent().TraceID() != root.TraceID() {
+					t.Errorf("fetch_widget parent = %v, want %v", s.Parent().SpanID(), root.SpanID())
+				}
+				for _, kv := range s.Attributes() {
+					if kv.Key == "widget.index" {
+						indices[kv.Value.AsInt64()] = true
+					}
+				}
+			}
+			if len(indices) != width {
+				t.Errorf("got %d distinct fetch_widget spans, want %d", len(indices), width)
+			}
+		})
+	}
+}
@@ -12,7 +12,28 @@ import (
 	"go.uber.org/zap"
 )
 
-func MicroservicesScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string) error {
+// MicroservicesDescription documents the spans emitted by MicroservicesScenario
+var MicroservicesDescription = Description{
+	Summary: "An API gateway request calling a random mix of backend services.",
+	Spans: []SpanDescription{
+		{
+			Name:       "complex_request",
+			Kind:       "server",
+			Attributes: []string{"http.request.method", "http.route", "url.scheme", "url.full", "url.path", "client.address", "client.port", "user_agent.original", "http.request.body.size", "service.name"},
+		},
+		{
+			Name:       "<service>_operation",
+			Kind:       "client",
+			Parent:     "complex_request",
+			Count:      "--span-count",
+			Attributes: []string{"service.name", "service.version", "service.instance.id", "process.runtime.name", "process.runtime.version", "plus attributes specific to the called service"},
+			Events:     []string{"operation_started"},
+			Notes:      "<service> is picked at random, e.g. auth_service, payment_service or cache_service",
+		},
+	},
+}
+
+func MicroservicesScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
 	services := []string{
 		"api_gateway", "auth_service", "user_service", "product_service", "inventory_service",
 		"order_service", "payment_service", "shipping_service", "notification_service",
@@ -20,7 +41,11 @@ func MicroservicesScenario(ctx context.Context, tracer trace.Tracer, logger *zap
 		"cache_service", "config_service", "monitoring_service",
 	}
 
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	ctx, rootSpan := tracer.Start(ctx, "complex_request",
+		trace.WithSpanKind(trace.SpanKindServer),
 		trace.WithAttributes(
 			semconv.HTTPRequestMethodPost,
 			semconv.HTTPRouteKey.String("/api/v1/order"),
@@ -36,11 +61,21 @@ func MicroservicesScenario(ctx context.Context, tracer trace.Tracer, logger *zap
 	)
 	defer rootSpan.End()
 
-	for i := 0; i < 100; i++ {
+	for i := 0; i < opts.spanCount(); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if err := WaitSpan(ctx); err != nil {
+			return err
+		}
+
 		microserviceName := services[rand.Intn(len(services))]
 		specificServiceName := fmt.Sprintf("%s_%s", serviceName, microserviceName)
 
 		_, span := tracer.Start(ctx, fmt.Sprintf("%s_operation", microserviceName),
+			trace.WithSpanKind(trace.SpanKindClient),
 			trace.WithAttributes(
 				semconv.ServiceNameKey.String(specificServiceName),
 				semconv.ServiceVersionKey.String(fmt.Sprintf("1.%d.0", rand.Intn(10))),
@@ -89,7 +124,7 @@ func MicroservicesScenario(ctx context.Context, tracer trace.Tracer, logger *zap
 		}
 
 		if rand.Float32() < 0.1 { // 10% chance of an error
-			span.SetStatus(codes.Error, "Operation failed")
+			span.SetStatus(codes.Error, opts.statusMessage(microserviceName))
 			span.RecordError(fmt.Errorf("random error in %s", microserviceName))
 		} else {
 			span.SetStatus(codes.Ok, "Operation successful")
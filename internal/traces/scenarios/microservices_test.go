@@ -0,0 +1,63 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+func TestMicroservicesScenarioStopsOnCancel(t *testing.T) {
+	tracer, recorder := newRecordingTracer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := MicroservicesScenario(ctx, tracer, zap.NewNop(), "test", Options{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("MicroservicesScenario() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// A child sleeps for up to 100ms, so the loop notices the cancellation within one of them
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want promptly after the 200ms deadline", elapsed)
+	}
+	if children := len(recorder.Ended()) - 1; children >= DefaultSpanCount {
+		t.Errorf("got %d child spans, want fewer than %d", children, DefaultSpanCount)
+	}
+}
+
+func TestMicroservicesScenarioSpanCount(t *testing.T) {
+	for _, count := range []int{1, 5} {
+		t.Run(fmt.Sprint(count), func(t *testing.T) {
+			tracer, recorder := newRecordingTracer(t)
+			if err := MicroservicesScenario(context.Background(), tracer, zap.NewNop(), "test", Options{SpanCount: count}); err != nil {
+				t.Fatalf("MicroservicesScenario() error = %v", err)
+			}
+
+			var root sdktrace.ReadOnlySpan
+			for _, s := range recorder.Ended() {
+				if s.Name() == "complex_request" {
+					root = s
+				}
+			}
+			if root == nil {
+				t.Fatal("no complex_request span was recorded")
+			}
+
+			children := 0
+			for _, s := range recorder.Ended() {
+				if s.Parent().SpanID() == root.SpanContext().SpanID() {
+					children++
+				}
+			}
+			if children != count {
+				t.Errorf("got %d child spans, want %d", children, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package scenarios
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultSpanCount is the number of child spans emitted by the microservices
+// scenario when no span count is configured.
+const DefaultSpanCount = 100
+
+// DefaultMaxTraceDepth is the number of nested spans emitted by the deep
+// scenario when no depth is configured.
+const DefaultMaxTraceDepth = 10
+
+// DefaultClockSkew is how far the clock skew scenario shifts its child spans
+// when no skew is configured.
+const DefaultClockSkew = 250 * time.Millisecond
+
+// MaxClockSkew is the largest skew the clock skew scenario accepts.
+const MaxClockSkew = time.Hour
+
+// DefaultStatusMessage is the status description set on error spans when no
+// message is configured.
+const DefaultStatusMessage = "Operation failed"
+
+// LinkMode decides how asynchronous consumer spans relate to the span that
+// produced their message.
+type LinkMode string
+
+const (
+	// LinkModeLink starts consumer spans in a new trace that links back to the producer.
+	LinkModeLink LinkMode = "link"
+	// LinkModeParent makes consumer spans children of the producer so the whole
+	// flow shares one trace.
+	LinkModeParent LinkMode = "parent"
+)
+
+// Options holds the tunables that are shared across scenarios.
+type Options struct {
+	// SpanCount is the number of child spans a fan-out scenario emits.
+	SpanCount int
+	// MaxTraceDepth is the length of the parent-child chain the deep scenario builds.
+	MaxTraceDepth int
+	// ClockSkew is how far the clock skew scenario shifts child spans from their parent.
+	ClockSkew time.Duration
+	// StatusMessage is the status description of error spans, {service} is replaced
+	// by the name of the failing service.
+	StatusMessage string
+	// SpanKind overrides the kind of the basic scenario's root span when set.
+	SpanKind trace.SpanKind
+	// LinkMode decides whether consumers join the producer's trace or link to it,
+	// LinkModeLink is used when unset.
+	LinkMode LinkMode
+}
+
+// spanCount returns the configured span count, falling back to the default.
+func (o Options) spanCount() int {
+	if o.SpanCount < 1 {
+		return DefaultSpanCount
+	}
+	return o.SpanCount
+}
+
+// maxTraceDepth returns the configured trace depth, falling back to the default.
+func (o Options) maxTraceDepth() int {
+	if o.MaxTraceDepth < 1 {
+		return DefaultMaxTraceDepth
+	}
+	return o.MaxTraceDepth
+}
+
+// clockSkew returns the configured clock skew, falling back to the default.
+func (o Options) clockSkew() time.Duration {
+	if o.ClockSkew <= 0 {
+		return DefaultClockSkew
+	}
+	return o.ClockSkew
+}
+
+// statusMessage returns the status description for an error span in service.
+func (o Options) statusMessage(service string) string {
+	if o.StatusMessage == "" {
+		return DefaultStatusMessage
+	}
+	return strings.ReplaceAll(o.StatusMessage, "{service}", service)
+}
+
+// spanKind returns the configured span kind, falling back to def.
+func (o Options) spanKind(def trace.SpanKind) trace.SpanKind {
+	if o.SpanKind == trace.SpanKindUnspecified {
+		return def
+	}
+	return o.SpanKind
+}
+
+// ParseLinkMode returns the link mode with the given name.
+func ParseLinkMode(s string) (LinkMode, error) {
+	switch m := LinkMode(s); m {
+	case LinkModeLink, LinkModeParent:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unsupported link mode: %s, use one of: parent, link", s)
+	}
+}
+
+// ParseSpanKind returns the span kind with the given name, an empty name leaves it unspecified.
+func ParseSpanKind(s string) (trace.SpanKind, error) {
+	switch s {
+	case "":
+		return trace.SpanKindUnspecified, nil
+	case "internal":
+		return trace.SpanKindInternal, nil
+	case "server":
+		return trace.SpanKindServer, nil
+	case "client":
+		return trace.SpanKindClient, nil
+	case "producer":
+		return trace.SpanKindProducer, nil
+	case "consumer":
+		return trace.SpanKindConsumer, nil
+	default:
+		return trace.SpanKindUnspecified, fmt.Errorf("unsupported span kind: %s, use one of: client, server, producer, consumer, internal", s)
+	}
+}
@@ -0,0 +1,26 @@
+package scenarios
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+type spanLimiterKey struct{}
+
+// WithSpanLimiter returns a copy of ctx whose scenarios wait on limiter before
+// starting each span, capping the spans started across every worker sharing it
+func WithSpanLimiter(ctx context.Context, limiter *rate.Limiter) context.Context {
+	return context.WithValue(ctx, spanLimiterKey{}, limiter)
+}
+
+// WaitSpan blocks until the span limiter of ctx allows another span to start. It is
+// called before a span is started, so the wait is never part of its duration, and
+// returns the error of a ctx done first. Without a limiter it returns straight away.
+func WaitSpan(ctx context.Context) error {
+	limiter, ok := ctx.Value(spanLimiterKey{}).(*rate.Limiter)
+	if !ok {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
@@ -0,0 +1,88 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+func TestWaitSpan(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	exhausted := rate.NewLimiter(rate.Every(time.Hour), 1)
+	exhausted.Allow()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+	}{
+		{name: "without a limiter", ctx: context.Background()},
+		{name: "without a limiter once cancelled", ctx: cancelled},
+		{name: "with tokens left", ctx: WithSpanLimiter(context.Background(), rate.NewLimiter(rate.Inf, 1))},
+		{name: "cancelled while waiting", ctx: WithSpanLimiter(cancelled, exhausted), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WaitSpan(tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitSpan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpanLimiterWaitsOutsideSpans(t *testing.T) {
+	const interval = 100 * time.Millisecond
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	ctx := WithSpanLimiter(context.Background(), rate.NewLimiter(rate.Every(interval), 1))
+	if err := ExceptionScenario(ctx, tp.Tracer("test"), zap.NewNop(), "test", Options{}); err != nil {
+		t.Fatalf("ExceptionScenario() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].StartTime().Before(spans[j].StartTime()) })
+
+	for i := 1; i < len(spans); i++ {
+		if gap := spans[i].StartTime().Sub(spans[i-1].StartTime()); gap < interval*9/10 {
+			t.Errorf("%s started %v after %s, want at least %v", spans[i].Name(), gap, spans[i-1].Name(), interval)
+		}
+	}
+	// debit_ledger only sleeps a few milliseconds, so the limiter must not have held it
+	if leaf := spans[2]; leaf.EndTime().Sub(leaf.StartTime()) >= interval/2 {
+		t.Errorf("%s lasted %v, the span limiter wait leaked into it", leaf.Name(), leaf.EndTime().Sub(leaf.StartTime()))
+	}
+}
+
+func TestExceptionScenarioReturnsWaitError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ctx = WithSpanLimiter(ctx, rate.NewLimiter(rate.Every(time.Hour), 1))
+
+	err := ExceptionScenario(ctx, tp.Tracer("test"), zap.NewNop(), "test", Options{})
+	if err == nil || errors.Is(err, errInsufficientFunds) {
+		t.Fatalf("ExceptionScenario() error = %v, want the span limiter error", err)
+	}
+	if got := len(recorder.Ended()); got != 1 {
+		t.Errorf("got %d spans, want only the root", got)
+	}
+}
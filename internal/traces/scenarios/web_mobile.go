@@ -12,7 +12,39 @@ import (
 	"go.uber.org/zap"
 )
 
-func WebMobileScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string) error {
+// WebMobileDescription documents the spans emitted by WebMobileScenario
+var WebMobileDescription = Description{
+	Summary: "A web or mobile client request passing through a web server and app to a database.",
+	Spans: []SpanDescription{
+		{
+			Name:       "client_request",
+			Kind:       "client",
+			Attributes: []string{"service.name", "user_agent.original", "user_agent.name", "user_agent.version", "device.model.identifier", "os.name", "os.version", "http.request.method", "http.route", "url.scheme", "url.full", "url.path", "url.query", "client.address", "client.port"},
+		},
+		{
+			Name:       "web_server",
+			Kind:       "server",
+			Parent:     "client_request",
+			Attributes: []string{"service.name", "server.address", "server.port", "http.response.status_code", "network.protocol.name", "network.protocol.version"},
+			Events:     []string{"request_received"},
+		},
+		{
+			Name:       "app_endpoint",
+			Kind:       "server",
+			Parent:     "web_server",
+			Attributes: []string{"service.name", "service.version", "service.instance.id"},
+			Events:     []string{"processing_started", "processing_completed"},
+		},
+		{
+			Name:       "database_query",
+			Kind:       "client",
+			Parent:     "app_endpoint",
+			Attributes: []string{"service.name", "db.system", "db.namespace", "db.query.text", "db.operation.name"},
+		},
+	},
+}
+
+func WebMobileScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts Options) error {
 	clientTypes := []string{"web_browser", "ios_app", "android_app"}
 	clientType := clientTypes[rand.Intn(len(clientTypes))]
 
@@ -41,7 +73,11 @@ func WebMobileScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Log
 	}
 
 	// Start the root span
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	ctx, rootSpan := tracer.Start(ctx, "client_request",
+		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(
 			semconv.ServiceNameKey.String(clientServiceName),
 			semconv.UserAgentOriginal(userAgent),
@@ -63,7 +99,11 @@ func WebMobileScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Log
 	defer rootSpan.End()
 
 	// Web Server
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	ctx, webSpan := tracer.Start(ctx, "web_server",
+		trace.WithSpanKind(trace.SpanKindServer),
 		trace.WithAttributes(
 			semconv.ServiceNameKey.String(webServerServiceName),
 			semconv.ServerAddress("api.example.com"),
@@ -81,7 +121,11 @@ func WebMobileScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Log
 	webSpan.End()
 
 	// Application Endpoint
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	ctx, appSpan := tracer.Start(ctx, "app_endpoint",
+		trace.WithSpanKind(trace.SpanKindServer),
 		trace.WithAttributes(
 			semconv.ServiceNameKey.String(appServerServiceName),
 			semconv.ServiceNameKey.String("data-service"),
@@ -95,7 +139,11 @@ func WebMobileScenario(ctx context.Context, tracer trace.Tracer, logger *zap.Log
 	appSpan.End()
 
 	// Database Backend
+	if err := WaitSpan(ctx); err != nil {
+		return err
+	}
 	_, dbSpan := tracer.Start(ctx, "database_query",
+		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(
 			semconv.ServiceNameKey.String(dbServerServiceName),
 			semconv.DBSystemKey.String("postgresql"),
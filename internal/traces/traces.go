@@ -2,13 +2,17 @@ package traces
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/krzko/otelgen/internal/progress"
+	"github.com/krzko/otelgen/internal/rateprofile"
 	"github.com/krzko/otelgen/internal/traces/scenarios"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
@@ -17,24 +21,55 @@ import (
 )
 
 type worker struct {
+	tracer           trace.Tracer
 	running          *atomic.Bool
+	emitted          *atomic.Int64
 	numTraces        int
 	propagateContext bool
 	totalDuration    time.Duration
-	limitPerSecond   rate.Limit
-	wg               *sync.WaitGroup
-	logger           *zap.Logger
-	scenarios        []string
-	serviceName      string
+	// limiter paces every worker together, limitPerSecond being its rate before
+	// the profile is applied from start
+	limiter         *rate.Limiter
+	limitPerSecond  rate.Limit
+	start           time.Time
+	rateProfile     rateprofile.Profile
+	wg              *sync.WaitGroup
+	logger          *zap.Logger
+	scenarios       []string
+	serviceName     string
+	scenarioOptions scenarios.Options
+	scenarioRetries int
+	scenarioBackoff time.Duration
 }
 
-func Run(c *Config, logger *zap.Logger) error {
+// Run generates traces on the global tracer provider until ctx is cancelled or the
+// configured number or duration is reached
+func Run(ctx context.Context, c *Config, logger *zap.Logger) error {
+	return NewGenerator(otel.GetTracerProvider(), c, logger).Run(ctx)
+}
+
+// Generator emits the configured scenarios on a tracer provider built by the caller
+type Generator struct {
+	provider trace.TracerProvider
+	config   *Config
+	logger   *zap.Logger
+}
+
+// NewGenerator returns a generator that emits traces on tp
+func NewGenerator(tp trace.TracerProvider, c *Config, logger *zap.Logger) *Generator {
+	return &Generator{provider: tp, config: c, logger: logger}
+}
+
+// Run generates traces until the configured number or duration is reached, or ctx is cancelled
+func (g *Generator) Run(ctx context.Context) error {
+	c, logger := g.config, g.logger
 	if c.TotalDuration > 0 {
 		c.NumTraces = 0
 	} else if c.NumTraces <= 0 {
 		return fmt.Errorf("either `traces` or `duration` must be greater than 0")
 	}
 
+	// A single limiter shared by the workers keeps the rate an aggregate across them
 	limit := rate.Limit(c.Rate)
 	if c.Rate == 0 {
 		limit = rate.Inf
@@ -42,60 +77,113 @@ func Run(c *Config, logger *zap.Logger) error {
 	} else {
 		logger.Info("generation of traces is limited", zap.Float64("per-second", float64(limit)))
 	}
+	limiter := rate.NewLimiter(limit, 1)
+	start := time.Now()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if c.Baggage.Len() > 0 {
+		ctx = baggage.ContextWithBaggage(ctx, c.Baggage)
+	}
+	if c.SpanRate > 0 {
+		logger.Info("starting of spans is limited", zap.Float64("per-second", c.SpanRate))
+		ctx = scenarios.WithSpanLimiter(ctx, rate.NewLimiter(rate.Limit(c.SpanRate), 1))
+	}
 
 	wg := sync.WaitGroup{}
 	running := atomic.NewBool(true)
+	emitted := atomic.NewInt64(0)
+
+	stopProgress := progress.Start(logger, "traces", c.ProgressInterval, c.TotalDuration, emitted.Load)
+	defer stopProgress()
 
 	for i := 0; i < c.WorkerCount; i++ {
 		wg.Add(1)
 		w := worker{
+			tracer:           g.provider.Tracer(c.ServiceName),
 			running:          running,
+			emitted:          emitted,
 			numTraces:        c.NumTraces,
 			propagateContext: c.PropagateContext,
 			totalDuration:    c.TotalDuration,
+			limiter:          limiter,
 			limitPerSecond:   limit,
+			start:            start,
+			rateProfile:      c.RateProfile,
 			wg:               &wg,
 			logger:           logger.With(zap.Int("worker", i)),
 			scenarios:        c.Scenarios,
 			serviceName:      c.ServiceName,
+			scenarioOptions: scenarios.Options{
+				SpanCount:     c.SpanCount,
+				MaxTraceDepth: c.MaxTraceDepth,
+				ClockSkew:     c.ClockSkew,
+				StatusMessage: c.StatusMessage,
+				SpanKind:      c.SpanKind,
+				LinkMode:      c.LinkMode,
+			},
+			scenarioRetries: c.ScenarioRetries,
+			scenarioBackoff: c.ScenarioBackoff,
 		}
-		go w.simulateTraces()
+		go w.simulateTraces(ctx)
 	}
 
 	if c.TotalDuration > 0 {
 		logger.Info("generation duration", zap.Float64("seconds", c.TotalDuration.Seconds()))
-		time.Sleep(c.TotalDuration)
+		select {
+		case <-time.After(c.TotalDuration):
+		case <-ctx.Done():
+		}
 		running.Store(false)
+		cancel()
 	}
 
 	wg.Wait()
 	return nil
 }
 
-func (w *worker) simulateTraces() {
-	tracer := otel.Tracer(w.serviceName)
-	limiter := rate.NewLimiter(w.limitPerSecond, 1)
+func (w *worker) simulateTraces(ctx context.Context) {
+	tracer := w.tracer
 	var i int
 
-	for w.running.Load() {
+	for w.running.Load() && ctx.Err() == nil {
 		w.logger.Info("starting traces")
 		for _, scenario := range w.scenarios {
 			w.logger.Info("generating scenario", zap.String("scenario", scenario))
 
-			ctx, sp := tracer.Start(context.Background(), scenario)
-			childCtx := ctx
+			// The span limiter only fails once ctx is done, ending the run
+			if err := scenarios.WaitSpan(ctx); err != nil {
+				break
+			}
+			spCtx, sp := tracer.Start(ctx, scenario)
+			childCtx := spCtx
 			if w.propagateContext {
 				header := propagation.HeaderCarrier{}
 				otel.GetTextMapPropagator().Inject(childCtx, header)
 				childCtx = otel.GetTextMapPropagator().Extract(childCtx, header)
 			}
 
-			err := runScenario(childCtx, scenario, tracer, w.logger, w.serviceName)
-			if err != nil {
+			err := retryScenario(childCtx, w.scenarioRetries, w.scenarioBackoff, w.logger.With(zap.String("scenario", scenario)), func(ctx context.Context) error {
+				return runScenario(ctx, scenario, tracer, w.logger, w.serviceName, w.scenarioOptions)
+			})
+			if errors.Is(err, context.Canceled) {
+				w.logger.Info("scenario cancelled", zap.String("scenario", scenario))
+				sp.End()
+				break
+			} else if err != nil {
 				w.logger.Error("failed to run scenario", zap.String("scenario", scenario), zap.Error(err))
 			}
 
-			if err := limiter.Wait(context.Background()); err != nil {
+			if w.limitPerSecond != rate.Inf {
+				w.limiter.SetLimit(rate.Limit(w.rateProfile.Pace(float64(w.limitPerSecond), time.Since(w.start), w.totalDuration)))
+			}
+
+			if err := w.limiter.Wait(ctx); err != nil {
+				if ctx.Err() != nil {
+					sp.End()
+					break
+				}
 				w.logger.Fatal("limiter waited failed, retry", zap.Error(err))
 			}
 
@@ -105,6 +193,7 @@ func (w *worker) simulateTraces() {
 				zap.String("spanId", sp.SpanContext().SpanID().String()),
 			)
 			sp.End()
+			w.emitted.Inc()
 		}
 
 		i++
@@ -117,17 +206,60 @@ func (w *worker) simulateTraces() {
 	w.wg.Done()
 }
 
-func runScenario(ctx context.Context, scenario string, tracer trace.Tracer, logger *zap.Logger, serviceName string) error {
+// retryScenario calls run, calling it again up to retries times while it fails. It waits
+// backoff before the first retry and doubles the wait after each, giving up early when
+// ctx is done.
+func retryScenario(ctx context.Context, retries int, backoff time.Duration, logger *zap.Logger, run func(context.Context) error) error {
+	err := run(ctx)
+	for attempt := 1; attempt <= retries && err != nil; attempt++ {
+		if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+			return err
+		}
+		logger.Warn("scenario failed, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		err = run(ctx)
+	}
+	return err
+}
+
+func runScenario(ctx context.Context, scenario string, tracer trace.Tracer, logger *zap.Logger, serviceName string, opts scenarios.Options) error {
 	scenarioFunc, ok := Scenarios[scenario]
 	if !ok {
 		return fmt.Errorf("unknown scenario: %s", scenario)
 	}
-	return scenarioFunc(ctx, tracer, logger, serviceName)
+	return scenarioFunc(ctx, tracer, logger, serviceName, opts)
 }
 
-var Scenarios = map[string]func(context.Context, trace.Tracer, *zap.Logger, string) error{
+var Scenarios = map[string]func(context.Context, trace.Tracer, *zap.Logger, string, scenarios.Options) error{
 	"basic":         scenarios.BasicScenario,
+	"clock_skew":    scenarios.ClockSkewScenario,
+	"deep":          scenarios.DeepScenario,
 	"web_mobile":    scenarios.WebMobileScenario,
 	"eventing":      scenarios.EventingScenario,
+	"exception":     scenarios.ExceptionScenario,
+	"fan_out":       scenarios.FanOutScenario,
 	"microservices": scenarios.MicroservicesScenario,
 }
+
+// Descriptions documents the spans and attributes each of the Scenarios emits
+var Descriptions = map[string]scenarios.Description{
+	"basic":         scenarios.BasicDescription,
+	"clock_skew":    scenarios.ClockSkewDescription,
+	"deep":          scenarios.DeepDescription,
+	"web_mobile":    scenarios.WebMobileDescription,
+	"eventing":      scenarios.EventingDescription,
+	"exception":     scenarios.ExceptionDescription,
+	"fan_out":       scenarios.FanOutDescription,
+	"microservices": scenarios.MicroservicesDescription,
+}
@@ -0,0 +1,95 @@
+package traces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestGeneratorSharesRateAcrossWorkers(t *testing.T) {
+	const (
+		perSec   = 10
+		duration = 2 * time.Second
+		// want is the limiter's burst of one plus a trace every tenth of a second
+		want = 1 + perSec*int(duration/time.Second)
+	)
+
+	for _, workers := range []int{1, 4, 16} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			tp := sdktrace.NewTracerProvider()
+			defer func() { _ = tp.Shutdown(context.Background()) }()
+			core, logs := observer.New(zap.InfoLevel)
+
+			c := &Config{
+				WorkerCount:   workers,
+				Rate:          perSec,
+				TotalDuration: duration,
+				ServiceName:   "test",
+				// fan_out doesn't sleep, so a single worker can keep up with the rate
+				Scenarios: []string{"fan_out"},
+			}
+			if err := NewGenerator(tp, c, zap.New(core)).Run(context.Background()); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			// The workers share perSec between them rather than each running at it
+			got := logs.FilterMessage("scenario completed").Len()
+			if got < want-2 || got > want+1 {
+				t.Errorf("got %d traces in %v, want %d", got, duration, want)
+			}
+		})
+	}
+}
+
+func TestRetryScenario(t *testing.T) {
+	errFailed := errors.New("scenario failed")
+
+	tests := []struct {
+		name      string
+		retries   int
+		failures  int
+		err       error
+		cancelled bool
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "succeeds first time", retries: 3, wantCalls: 1},
+		{name: "succeeds on a retry", retries: 3, failures: 2, err: errFailed, wantCalls: 3},
+		{name: "no retries", retries: 0, failures: 1, err: errFailed, wantCalls: 1, wantErr: errFailed},
+		{name: "gives up after retries", retries: 2, failures: 5, err: errFailed, wantCalls: 3, wantErr: errFailed},
+		{name: "cancellation is not retried", retries: 3, failures: 5, err: context.Canceled, wantCalls: 1, wantErr: context.Canceled},
+		{name: "stops once the context is done", retries: 3, failures: 5, err: errFailed, cancelled: true, wantCalls: 1, wantErr: errFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			calls := 0
+			run := func(context.Context) error {
+				calls++
+				if tt.cancelled {
+					cancel()
+				}
+				if calls <= tt.failures {
+					return tt.err
+				}
+				return nil
+			}
+
+			err := retryScenario(ctx, tt.retries, time.Millisecond, zap.NewNop(), run)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("retryScenario() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}